@@ -0,0 +1,203 @@
+// Copyright 2023 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package builder
+
+import (
+	"errors"
+	"strconv"
+	"unicode/utf8"
+)
+
+// ansiLen returns the length of the ANSI escape sequence at the start of s,
+// which begins with ESC. CSI sequences (ESC [ ... final byte) and OSC
+// sequences (ESC ] ... terminated by BEL or ESC \) are recognized, as are
+// the two and three byte forms ESC followed by an optional intermediate and
+// a final byte. An unterminated sequence extends to the end of s.
+func ansiLen(s string) int {
+	if len(s) < 2 {
+		return len(s)
+	}
+
+	switch s[1] {
+	case '[':
+		for i := 2; i < len(s); i++ {
+			if c := s[i]; c >= 0x40 && c <= 0x7e {
+				return i + 1
+			}
+		}
+	case ']':
+		for i := 2; i < len(s); i++ {
+			switch s[i] {
+			case '\a':
+				return i + 1
+			case '\x1b':
+				if i+1 < len(s) && s[i+1] == '\\' {
+					return i + 2
+				}
+			}
+		}
+	default:
+		for i := 1; i < len(s); i++ {
+			if c := s[i]; c < 0x20 || c > 0x2f {
+				return i + 1
+			}
+		}
+	}
+	return len(s)
+}
+
+// DisplayLenIgnoringANSI returns the number of terminal columns s occupies,
+// ignoring ANSI escape sequences such as color codes. East Asian wide
+// characters count as two columns and combining marks and other
+// zero-width characters as none.
+func DisplayLenIgnoringANSI(s string) int {
+	w := 0
+	for i := 0; i < len(s); {
+		if s[i] == '\x1b' {
+			i += ansiLen(s[i:])
+			continue
+		}
+		r, size := utf8.DecodeRuneInString(s[i:])
+		w += runeWidth(r)
+		i += size
+	}
+	return w
+}
+
+// WriteTruncatedANSI is like WriteTruncated but ignores ANSI escape
+// sequences when measuring s, so styled text can be truncated to a terminal
+// width. When s is truncated the escape sequences from the removed part are
+// still written, after the ellipsis, so that a trailing reset is not lost.
+// It returns the length of written and a nil error.
+func (b *Builder) WriteTruncatedANSI(s string, maxWidth int, ellipsis string) (int, error) {
+	b.copyCheck()
+	n := len(b.buf)
+	if b.widthIgnoringANSI(s) <= maxWidth {
+		b.buf = append(b.buf, s...)
+		return b.commit(n), nil
+	}
+
+	limit := maxWidth - b.widthIgnoringANSI(ellipsis)
+	i, w := 0, 0
+	for i < len(s) {
+		if s[i] == '\x1b' {
+			i += ansiLen(s[i:])
+			continue
+		}
+		r, size := utf8.DecodeRuneInString(s[i:])
+		rw := b.runeWidth(r)
+		if w+rw > limit {
+			break
+		}
+		w += rw
+		i += size
+	}
+	b.buf = append(b.buf, s[:i]...)
+	b.buf = append(b.buf, ellipsis...)
+	for i < len(s) {
+		if s[i] == '\x1b' {
+			l := ansiLen(s[i:])
+			b.buf = append(b.buf, s[i:i+l]...)
+			i += l
+			continue
+		}
+		i++
+	}
+	return b.commit(n), nil
+}
+
+// widthIgnoringANSI is like width but skips ANSI escape sequences.
+func (b *Builder) widthIgnoringANSI(s string) int {
+	w := 0
+	for i := 0; i < len(s); {
+		if s[i] == '\x1b' {
+			i += ansiLen(s[i:])
+			continue
+		}
+		r, size := utf8.DecodeRuneInString(s[i:])
+		w += b.runeWidth(r)
+		i += size
+	}
+	return w
+}
+
+// SetHyperlinks sets whether WriteHyperlink writes terminal hyperlinks,
+// which it does by default. Disable them when the output is not a terminal
+// or the terminal does not support them.
+func (b *Builder) SetHyperlinks(enabled bool) {
+	if enabled && b.mode == nil {
+		return
+	}
+	if b.mode == nil {
+		b.mode = new(mode)
+	}
+	b.mode.noHyperlinks = !enabled
+}
+
+// WriteOSC appends the operating system command sequence ESC ] code ; data,
+// terminated by ST (ESC \), to b's buffer. Terminals use such sequences for
+// window titles, hyperlinks and clipboard access.
+// It returns the length of written. If data contains a control character,
+// which would end the sequence early or corrupt the terminal state, b is left
+// unchanged and an error is returned.
+func (b *Builder) WriteOSC(code int, data string) (int, error) {
+	if err := checkOSCData(data); err != nil {
+		return 0, err
+	}
+
+	b.copyCheck()
+	n := len(b.buf)
+	b.buf = appendOSC(b.buf, code, data)
+	return b.commit(n), nil
+}
+
+// WriteHyperlink appends text to b's buffer as a terminal hyperlink to url,
+// using the OSC 8 sequences supported by most modern terminal emulators.
+// If hyperlinks are disabled by SetHyperlinks, it writes text followed by
+// url in parentheses instead, or just url if text is empty or equal to it.
+// It returns the length of written. If url contains a control character,
+// b is left unchanged and an error is returned.
+func (b *Builder) WriteHyperlink(url, text string) (int, error) {
+	if err := checkOSCData(url); err != nil {
+		return 0, err
+	}
+
+	b.copyCheck()
+	n := len(b.buf)
+	switch {
+	case b.mode == nil || !b.mode.noHyperlinks:
+		b.buf = append(b.buf, "\x1b]8;;"...)
+		b.buf = append(b.buf, url...)
+		b.buf = append(b.buf, "\x1b\\"...)
+		b.buf = append(b.buf, text...)
+		b.buf = append(b.buf, "\x1b]8;;\x1b\\"...)
+	case text == "" || text == url:
+		b.buf = append(b.buf, url...)
+	default:
+		b.buf = append(b.buf, text...)
+		b.buf = append(b.buf, " ("...)
+		b.buf = append(b.buf, url...)
+		b.buf = append(b.buf, ')')
+	}
+	return b.commit(n), nil
+}
+
+func appendOSC(dst []byte, code int, data string) []byte {
+	dst = append(dst, "\x1b]"...)
+	dst = strconv.AppendInt(dst, int64(code), 10)
+	dst = append(dst, ';')
+	dst = append(dst, data...)
+	return append(dst, "\x1b\\"...)
+}
+
+// checkOSCData reports an error if s contains a C0 control character or DEL.
+func checkOSCData(s string) error {
+	for i := 0; i < len(s); i++ {
+		if c := s[i]; c < 0x20 || c == 0x7f {
+			return errors.New("builder: control character " + strconv.QuoteRune(rune(c)) + " in OSC data")
+		}
+	}
+	return nil
+}
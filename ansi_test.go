@@ -0,0 +1,119 @@
+// Copyright 2023 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package builder_test
+
+import (
+	"testing"
+
+	. "github.com/weiwenchen2022/builder"
+)
+
+const (
+	red   = "\x1b[31m"
+	bold  = "\x1b[1;4m"
+	reset = "\x1b[0m"
+	link  = "\x1b]8;;https://example.com\x1b\\"
+	bell  = "\x1b]0;title\a"
+)
+
+func TestDisplayLenIgnoringANSI(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		s    string
+		want int
+	}{
+		{"", 0},
+		{"plain", 5},
+		{red + "error" + reset, 5},
+		{bold + "a" + reset + red + "b" + reset, 2},
+		{link + "site" + "\x1b]8;;\x1b\\", 4},
+		{bell + "x", 1},
+		{"\x1b(Bx\x1b7", 1},
+		{red + "世界" + reset, 4},
+		{"é", 1},
+		{"unterminated \x1b[31", 13},
+	}
+
+	for _, tt := range tests {
+		if got := DisplayLenIgnoringANSI(tt.s); tt.want != got {
+			t.Errorf("DisplayLenIgnoringANSI(%q) = %d; want %d", tt.s, got, tt.want)
+		}
+	}
+}
+
+func TestBuilderWriteTruncatedANSI(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		s        string
+		maxWidth int
+		ellipsis string
+		want     string
+	}{
+		{red + "short" + reset, 5, "...", red + "short" + reset},
+		{red + "hello world" + reset, 8, "...", red + "hello" + "..." + reset},
+		{bold + "ab" + reset + red + "cdef" + reset, 4, "~", bold + "ab" + reset + red + "c~" + reset},
+		{"abc" + red + "def" + reset, 3, "", "abc" + red + reset},
+	}
+
+	for _, tt := range tests {
+		var b Builder
+		n, err := b.WriteTruncatedANSI(tt.s, tt.maxWidth, tt.ellipsis)
+		if err != nil || len(tt.want) != n {
+			t.Errorf("WriteTruncatedANSI(%q, %d, %q): got %d,%v; want %d,nil", tt.s, tt.maxWidth, tt.ellipsis, n, err, len(tt.want))
+		}
+		check(t, &b, tt.want)
+	}
+}
+
+func TestBuilderWriteHyperlink(t *testing.T) {
+	t.Parallel()
+
+	var b Builder
+	n, err := b.WriteHyperlink("https://go.dev/", "Go")
+	want := "\x1b]8;;https://go.dev/\x1b\\Go\x1b]8;;\x1b\\"
+	if err != nil || n != len(want) {
+		t.Errorf("WriteHyperlink: got %d,%v; want %d,nil", n, err, len(want))
+	}
+	check(t, &b, want)
+	if w := DisplayLenIgnoringANSI(b.String()); w != 2 {
+		t.Errorf("DisplayLenIgnoringANSI: got %d; want 2", w)
+	}
+
+	b.Reset()
+	b.SetHyperlinks(false)
+	b.WriteHyperlink("https://go.dev/", "Go")
+	b.WriteByte(' ')
+	b.WriteHyperlink("https://go.dev/", "https://go.dev/")
+	b.WriteByte(' ')
+	b.WriteHyperlink("https://go.dev/", "")
+	check(t, &b, "Go (https://go.dev/) https://go.dev/ https://go.dev/")
+
+	b.Reset()
+	for _, url := range []string{"https://x\x1b\\", "https://x\a", "a\nb"} {
+		if n, err := b.WriteHyperlink(url, "x"); n != 0 || err == nil {
+			t.Errorf("WriteHyperlink(%q): got %d,%v; want 0,error", url, n, err)
+		}
+	}
+	check(t, &b, "")
+}
+
+func TestBuilderWriteOSC(t *testing.T) {
+	t.Parallel()
+
+	var b Builder
+	n, err := b.WriteOSC(0, "window title")
+	want := "\x1b]0;window title\x1b\\"
+	if err != nil || n != len(want) {
+		t.Errorf("WriteOSC: got %d,%v; want %d,nil", n, err, len(want))
+	}
+	check(t, &b, want)
+
+	if n, err := b.WriteOSC(52, "c;\x07"); n != 0 || err == nil {
+		t.Errorf("WriteOSC with BEL: got %d,%v; want 0,error", n, err)
+	}
+	check(t, &b, want)
+}
@@ -0,0 +1,30 @@
+// Copyright 2023 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package builder
+
+import "encoding/base32"
+
+// CrockfordEncoding is Douglas Crockford's base32 encoding, without padding,
+// as commonly used for human-readable identifiers.
+var CrockfordEncoding = base32.NewEncoding("0123456789ABCDEFGHJKMNPQRSTVWXYZ").WithPadding(base32.NoPadding)
+
+// WriteBase32 appends the base32 encoding of p, using enc, to b's buffer.
+// The encoding is written directly into the buffer, growing it at most once.
+// A nil enc selects base32.StdEncoding.
+// It returns the length of written and a nil error.
+func (b *Builder) WriteBase32(p []byte, enc *base32.Encoding) (int, error) {
+	if enc == nil {
+		enc = base32.StdEncoding
+	}
+
+	size := enc.EncodedLen(len(p))
+	if !b.reserve(size) {
+		return 0, ErrFull
+	}
+	n := len(b.buf)
+	b.buf = b.buf[:n+size]
+	enc.Encode(b.buf[n:], p)
+	return b.commit(n), nil
+}
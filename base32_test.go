@@ -0,0 +1,55 @@
+// Copyright 2023 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package builder_test
+
+import (
+	"encoding/base32"
+	"testing"
+
+	. "github.com/weiwenchen2022/builder"
+)
+
+func TestBuilderWriteBase32(t *testing.T) {
+	t.Parallel()
+
+	encodings := []*base32.Encoding{
+		base32.StdEncoding,
+		base32.HexEncoding,
+		base32.StdEncoding.WithPadding(base32.NoPadding),
+		CrockfordEncoding,
+	}
+	inputs := [][]byte{nil, {0}, {0xfb, 0xff}, []byte("hello, world"), []byte("\xff\xfe\xfd\xfc\xfb")}
+
+	for _, enc := range encodings {
+		for _, p := range inputs {
+			var b Builder
+			b.WriteString("id:")
+			want := enc.EncodeToString(p)
+			n, err := b.WriteBase32(p, enc)
+			if err != nil || len(want) != n {
+				t.Errorf("WriteBase32(%q): got %d,%v; want %d,nil", p, n, err, len(want))
+			}
+			check(t, &b, "id:"+want)
+		}
+	}
+
+	var b Builder
+	b.WriteBase32([]byte("f"), nil)
+	b.WriteByte(' ')
+	b.WriteBase32([]byte{0xff, 0xff}, CrockfordEncoding)
+	check(t, &b, "MY====== ZZZG")
+}
+
+func TestBuilderWriteBase32Allocs(t *testing.T) {
+	var b Builder
+	b.Grow(1024)
+	p := []byte("some binary payload")
+	allocs := testing.AllocsPerRun(10, func() {
+		b.WriteBase32(p, CrockfordEncoding)
+	})
+	if allocs != 0 {
+		t.Errorf("WriteBase32 allocs = %v; want 0", allocs)
+	}
+}
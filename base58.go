@@ -0,0 +1,55 @@
+// Copyright 2023 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package builder
+
+const base58Alphabet = "123456789ABCDEFGHJKLMNPQRSTUVWXYZabcdefghijkmnopqrstuvwxyz"
+
+// WriteBase58 appends the base58 encoding of p, using the Bitcoin alphabet,
+// to b's buffer. Each leading zero byte of p is written as '1'.
+// The digits are computed in the buffer itself, growing it at most once.
+// It returns the length of written and a nil error.
+func (b *Builder) WriteBase58(p []byte) (int, error) {
+	zeros := 0
+	for zeros < len(p) && p[zeros] == 0 {
+		zeros++
+	}
+	// log(256)/log(58) < 1.38, so this is enough digits for the rest of p.
+	size := (len(p)-zeros)*138/100 + 1
+	if !b.reserve(zeros + size) {
+		return 0, ErrFull
+	}
+	n := len(b.buf)
+
+	// Convert p to base 58 in the space after the leading '1's,
+	// most significant digit first.
+	digits := b.buf[n+zeros : n+zeros+size]
+	for i := range digits {
+		digits[i] = 0
+	}
+	high := size - 1
+	for _, c := range p[zeros:] {
+		carry := int(c)
+		j := size - 1
+		for ; j > high || carry != 0; j-- {
+			carry += 256 * int(digits[j])
+			digits[j] = byte(carry % 58)
+			carry /= 58
+		}
+		high = j
+	}
+
+	skip := 0
+	for skip < size && digits[skip] == 0 {
+		skip++
+	}
+	b.buf = b.buf[:n+zeros]
+	for i := n; i < n+zeros; i++ {
+		b.buf[i] = '1'
+	}
+	for _, d := range digits[skip:] {
+		b.buf = append(b.buf, base58Alphabet[d])
+	}
+	return b.commit(n), nil
+}
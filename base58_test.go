@@ -0,0 +1,53 @@
+// Copyright 2023 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package builder_test
+
+import (
+	"testing"
+
+	. "github.com/weiwenchen2022/builder"
+)
+
+func TestBuilderWriteBase58(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		in   string
+		want string
+	}{
+		{"", ""},
+		{"\x00", "1"},
+		{"\x00\x00\x00", "111"},
+		{"\x39", "z"},
+		{"\x3a", "21"},
+		{"a", "2g"},
+		{"abc", "ZiCa"},
+		{"Hello World!", "2NEpo7TZRRrLZSi2U"},
+		{"\x00\x00\x28\x7f\xb4\xcd", "11233QC4"},
+		{"\xff\xff\xff\xff", "7YXq9G"},
+		{"The quick brown fox jumps over the lazy dog.", "USm3fpXnKG5EUBx2ndxBDMPVciP5hGey2Jh4NDv6gmeo1LkMeiKrLJUUBk6Z"},
+	}
+	for _, tt := range tests {
+		var b Builder
+		b.WriteString("addr:")
+		n, err := b.WriteBase58([]byte(tt.in))
+		if err != nil || len(tt.want) != n {
+			t.Errorf("WriteBase58(%q): got %d,%v; want %d,nil", tt.in, n, err, len(tt.want))
+		}
+		check(t, &b, "addr:"+tt.want)
+	}
+}
+
+func TestBuilderWriteBase58Allocs(t *testing.T) {
+	var b Builder
+	b.Grow(1024)
+	p := []byte("\x00\x00some binary payload")
+	allocs := testing.AllocsPerRun(10, func() {
+		b.WriteBase58(p)
+	})
+	if allocs != 0 {
+		t.Errorf("WriteBase58 allocs = %v; want 0", allocs)
+	}
+}
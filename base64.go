@@ -0,0 +1,26 @@
+// Copyright 2023 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package builder
+
+import "encoding/base64"
+
+// WriteBase64 appends the base64 encoding of p, using enc, to b's buffer.
+// The encoding is written directly into the buffer, growing it at most once.
+// A nil enc selects base64.StdEncoding.
+// It returns the length of written and a nil error.
+func (b *Builder) WriteBase64(p []byte, enc *base64.Encoding) (int, error) {
+	if enc == nil {
+		enc = base64.StdEncoding
+	}
+
+	size := enc.EncodedLen(len(p))
+	if !b.reserve(size) {
+		return 0, ErrFull
+	}
+	n := len(b.buf)
+	b.buf = b.buf[:n+size]
+	enc.Encode(b.buf[n:], p)
+	return b.commit(n), nil
+}
@@ -0,0 +1,53 @@
+// Copyright 2023 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package builder_test
+
+import (
+	"encoding/base64"
+	"testing"
+
+	. "github.com/weiwenchen2022/builder"
+)
+
+func TestBuilderWriteBase64(t *testing.T) {
+	t.Parallel()
+
+	encodings := []*base64.Encoding{
+		base64.StdEncoding,
+		base64.URLEncoding,
+		base64.RawStdEncoding,
+		base64.RawURLEncoding,
+	}
+	inputs := [][]byte{nil, {0}, {0xfb, 0xff}, []byte("hello, world"), []byte("\xff\xfe\xfd\xfc\xfb")}
+
+	for _, enc := range encodings {
+		for _, p := range inputs {
+			var b Builder
+			b.WriteString("data:")
+			want := enc.EncodeToString(p)
+			n, err := b.WriteBase64(p, enc)
+			if err != nil || len(want) != n {
+				t.Errorf("WriteBase64(%q): got %d,%v; want %d,nil", p, n, err, len(want))
+			}
+			check(t, &b, "data:"+want)
+		}
+	}
+
+	var b Builder
+	b.WriteBase64([]byte{0xfb, 0xff}, nil)
+	check(t, &b, "+/8=")
+}
+
+func TestBuilderWriteBase64Allocs(t *testing.T) {
+	var b Builder
+	b.Grow(1024)
+	p := []byte("some binary payload")
+	allocs := testing.AllocsPerRun(10, func() {
+		b.WriteBase64(p, base64.StdEncoding)
+	})
+	if allocs != 0 {
+		t.Errorf("WriteBase64 allocs = %v; want 0", allocs)
+	}
+}
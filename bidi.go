@@ -0,0 +1,61 @@
+// Copyright 2023 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package builder
+
+import "unicode/utf8"
+
+// A BidiIsolate is a Unicode directional isolate initiator.
+type BidiIsolate rune
+
+const (
+	FSI BidiIsolate = '\u2068' // first strong isolate: direction from the text itself
+	LRI BidiIsolate = '\u2066' // left-to-right isolate
+	RLI BidiIsolate = '\u2067' // right-to-left isolate
+)
+
+// pdi is the pop directional isolate that terminates an isolate.
+const pdi = '\u2069'
+
+// WriteBidiIsolated appends s to b's buffer wrapped in a first strong
+// isolate, so that right-to-left text, or directional formatting characters
+// within s, cannot visually reorder the text surrounding it.
+// It is equivalent to WriteBidiIsolatedAs(s, FSI).
+// It returns the length of written and a nil error.
+func (b *Builder) WriteBidiIsolated(s string) (int, error) {
+	return b.WriteBidiIsolatedAs(s, FSI)
+}
+
+// WriteBidiIsolatedAs appends s to b's buffer between the isolate initiator
+// iso and a matching pop directional isolate (U+2069). Pop directional
+// isolates in s that have no matching initiator in s are dropped, since
+// they would end the isolate early. Isolates left open by s are closed by
+// extra pop directional isolates before the final one, and embeddings and
+// overrides left open by s are closed by the final one (Unicode Standard
+// Annex #9).
+// It returns the length of written and a nil error.
+func (b *Builder) WriteBidiIsolatedAs(s string, iso BidiIsolate) (int, error) {
+	b.copyCheck()
+	n := len(b.buf)
+	b.buf = utf8.AppendRune(b.buf, rune(iso))
+	depth, last := 0, 0
+	for i, r := range s {
+		switch r {
+		case rune(FSI), rune(LRI), rune(RLI):
+			depth++
+		case pdi:
+			if depth > 0 {
+				depth--
+				break
+			}
+			b.buf = append(b.buf, s[last:i]...)
+			last = i + utf8.RuneLen(pdi)
+		}
+	}
+	b.buf = append(b.buf, s[last:]...)
+	for ; depth >= 0; depth-- {
+		b.buf = utf8.AppendRune(b.buf, pdi)
+	}
+	return b.commit(n), nil
+}
@@ -0,0 +1,63 @@
+// Copyright 2023 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package builder_test
+
+import (
+	"testing"
+
+	. "github.com/weiwenchen2022/builder"
+)
+
+func TestBuilderWriteBidiIsolated(t *testing.T) {
+	t.Parallel()
+
+	const (
+		fsi = "\u2068"
+		lri = "\u2066"
+		rli = "\u2067"
+		pdi = "\u2069"
+		rlo = "\u202e"
+	)
+
+	tests := []struct {
+		s    string
+		iso  BidiIsolate
+		want string
+	}{
+		{"", FSI, fsi + pdi},
+		{"alice", FSI, fsi + "alice" + pdi},
+		{"שלום", RLI, rli + "שלום" + pdi},
+		{"abc", LRI, lri + "abc" + pdi},
+		{rlo + "evil", FSI, fsi + rlo + "evil" + pdi},
+		{"x" + pdi + rlo + "y", FSI, fsi + "x" + rlo + "y" + pdi},
+		{lri + "a" + pdi + pdi + "b", FSI, fsi + lri + "a" + pdi + "b" + pdi},
+		{rli + "open", FSI, fsi + rli + "open" + pdi + pdi},
+		{rli + "evil", FSI, fsi + rli + "evil" + pdi + pdi},
+		{fsi + "a" + lri + "b", RLI, rli + fsi + "a" + lri + "b" + pdi + pdi + pdi},
+		{lri + "a" + pdi + rli + "b", FSI, fsi + lri + "a" + pdi + rli + "b" + pdi + pdi},
+	}
+
+	for _, tt := range tests {
+		var b Builder
+		n, err := b.WriteBidiIsolatedAs(tt.s, tt.iso)
+		if err != nil || len(tt.want) != n {
+			t.Errorf("WriteBidiIsolatedAs(%q, %U): got %d,%v; want %d,nil", tt.s, tt.iso, n, err, len(tt.want))
+		}
+		check(t, &b, tt.want)
+	}
+
+	var b Builder
+	b.WriteString("user ")
+	b.WriteBidiIsolated("x" + pdi)
+	b.WriteString(" logged in")
+	check(t, &b, "user "+fsi+"x"+pdi+" logged in")
+
+	// An isolate left open in s must not capture the surrounding text.
+	b.Reset()
+	b.WriteString("user ")
+	b.WriteBidiIsolated(rli + "evil")
+	b.WriteString(" logged in")
+	check(t, &b, "user "+fsi+rli+"evil"+pdi+pdi+" logged in")
+}
@@ -0,0 +1,60 @@
+// Copyright 2023 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package builder
+
+import "math/big"
+
+// WriteBigInt appends the string form of x in the given base, as generated by
+// x.Text(base), to b's buffer.
+// It returns the length of written and a nil error.
+func (b *Builder) WriteBigInt(x *big.Int, base int) (int, error) {
+	b.copyCheck()
+	n := len(b.buf)
+	b.buf = x.Append(b.buf, base)
+	return b.commit(n), nil
+}
+
+// WriteBigFloat appends the string form of x, as generated by
+// x.Text(fmt, prec) and subject to b's FloatPolicy, to b's buffer.
+// It returns the length of written and a nil error.
+func (b *Builder) WriteBigFloat(x *big.Float, fmt byte, prec int) (int, error) {
+	b.copyCheck()
+	n := len(b.buf)
+	b.buf = b.appendBigFloat(b.buf, x, fmt, prec)
+	return b.commit(n), nil
+}
+
+// appendBigFloat is like x.Append but applies b's FloatPolicy.
+func (b *Builder) appendBigFloat(dst []byte, x *big.Float, fmt byte, prec int) []byte {
+	if b.mode == nil || b.mode.floats == nil {
+		return x.Append(dst, fmt, prec)
+	}
+
+	p := b.mode.floats
+	switch {
+	case x.IsInf() && x.Sign() > 0 && p.PosInf != "":
+		return append(dst, p.PosInf...)
+	case x.IsInf() && x.Sign() < 0 && p.NegInf != "":
+		return append(dst, p.NegInf...)
+	case x.Sign() == 0 && x.Signbit() && p.NormalizeZero:
+		x = new(big.Float)
+	}
+	if p.Fmt != 0 {
+		fmt, prec = p.Fmt, p.Prec
+	}
+	return x.Append(dst, fmt, prec)
+}
+
+// WriteBigRat appends the string form of x, "a/b" as generated by x.String(),
+// to b's buffer.
+// It returns the length of written and a nil error.
+func (b *Builder) WriteBigRat(x *big.Rat) (int, error) {
+	b.copyCheck()
+	n := len(b.buf)
+	b.buf = x.Num().Append(b.buf, 10)
+	b.buf = append(b.buf, '/')
+	b.buf = x.Denom().Append(b.buf, 10)
+	return b.commit(n), nil
+}
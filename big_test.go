@@ -0,0 +1,55 @@
+// Copyright 2023 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package builder_test
+
+import (
+	"math/big"
+	"testing"
+
+	. "github.com/weiwenchen2022/builder"
+)
+
+func TestBuilderWriteBig(t *testing.T) {
+	t.Parallel()
+
+	huge, _ := new(big.Int).SetString("-123456789012345678901234567890", 10)
+	for _, x := range []*big.Int{nil, big.NewInt(0), big.NewInt(255), huge} {
+		for _, base := range []int{2, 10, 16, 62} {
+			var b Builder
+			want := x.Text(base)
+			n, err := b.WriteBigInt(x, base)
+			if err != nil || n != len(want) {
+				t.Errorf("WriteBigInt(%v, %d): got %d,%v; want %d,nil", x, base, n, err, len(want))
+			}
+			check(t, &b, want)
+		}
+	}
+
+	pi, _ := new(big.Float).SetPrec(200).SetString("3.14159265358979323846264338327950288419716939937510582097494459")
+	for _, x := range []*big.Float{new(big.Float), big.NewFloat(-1.5), pi, new(big.Float).SetInf(true)} {
+		for _, f := range []struct {
+			fmt  byte
+			prec int
+		}{{'g', -1}, {'f', 30}, {'e', 5}, {'p', 0}} {
+			var b Builder
+			want := x.Text(f.fmt, f.prec)
+			n, err := b.WriteBigFloat(x, f.fmt, f.prec)
+			if err != nil || n != len(want) {
+				t.Errorf("WriteBigFloat(%v, %c, %d): got %d,%v; want %d,nil", x, f.fmt, f.prec, n, err, len(want))
+			}
+			check(t, &b, want)
+		}
+	}
+
+	for _, x := range []*big.Rat{new(big.Rat), big.NewRat(3, 1), big.NewRat(-22, 7), new(big.Rat).SetFrac(huge, big.NewInt(9))} {
+		var b Builder
+		want := x.String()
+		n, err := b.WriteBigRat(x)
+		if err != nil || n != len(want) {
+			t.Errorf("WriteBigRat(%v): got %d,%v; want %d,nil", x, n, err, len(want))
+		}
+		check(t, &b, want)
+	}
+}
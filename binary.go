@@ -0,0 +1,153 @@
+// Copyright 2023 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package builder
+
+import (
+	"encoding/binary"
+	"errors"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// WriteBinaryUint16 appends the 2-byte encoding of v, in the given byte order,
+// to b's buffer.
+// It returns the length of written and a nil error.
+func (b *Builder) WriteBinaryUint16(v uint16, order binary.ByteOrder) (int, error) {
+	if !b.reserve(2) {
+		return 0, ErrFull
+	}
+	n := len(b.buf)
+	b.buf = b.buf[:n+2]
+	order.PutUint16(b.buf[n:], v)
+	return b.commit(n), nil
+}
+
+// WriteBinaryUint32 appends the 4-byte encoding of v, in the given byte order,
+// to b's buffer.
+// It returns the length of written and a nil error.
+func (b *Builder) WriteBinaryUint32(v uint32, order binary.ByteOrder) (int, error) {
+	if !b.reserve(4) {
+		return 0, ErrFull
+	}
+	n := len(b.buf)
+	b.buf = b.buf[:n+4]
+	order.PutUint32(b.buf[n:], v)
+	return b.commit(n), nil
+}
+
+// WriteBinaryUint64 appends the 8-byte encoding of v, in the given byte order,
+// to b's buffer.
+// It returns the length of written and a nil error.
+func (b *Builder) WriteBinaryUint64(v uint64, order binary.ByteOrder) (int, error) {
+	if !b.reserve(8) {
+		return 0, ErrFull
+	}
+	n := len(b.buf)
+	b.buf = b.buf[:n+8]
+	order.PutUint64(b.buf[n:], v)
+	return b.commit(n), nil
+}
+
+// WriteBinaryInt16 appends the 2-byte two's complement encoding of v,
+// in the given byte order, to b's buffer.
+// It returns the length of written and a nil error.
+func (b *Builder) WriteBinaryInt16(v int16, order binary.ByteOrder) (int, error) {
+	return b.WriteBinaryUint16(uint16(v), order)
+}
+
+// WriteBinaryInt32 appends the 4-byte two's complement encoding of v,
+// in the given byte order, to b's buffer.
+// It returns the length of written and a nil error.
+func (b *Builder) WriteBinaryInt32(v int32, order binary.ByteOrder) (int, error) {
+	return b.WriteBinaryUint32(uint32(v), order)
+}
+
+// WriteBinaryInt64 appends the 8-byte two's complement encoding of v,
+// in the given byte order, to b's buffer.
+// It returns the length of written and a nil error.
+func (b *Builder) WriteBinaryInt64(v int64, order binary.ByteOrder) (int, error) {
+	return b.WriteBinaryUint64(uint64(v), order)
+}
+
+// WriteVarint appends the varint encoding of v, as produced by
+// binary.AppendVarint, to b's buffer.
+// It returns the length of written and a nil error.
+func (b *Builder) WriteVarint(v int64) (int, error) {
+	b.copyCheck()
+	n := len(b.buf)
+	b.buf = binary.AppendVarint(b.buf, v)
+	return b.commit(n), nil
+}
+
+// WriteUvarint appends the varint encoding of v, as produced by
+// binary.AppendUvarint, to b's buffer.
+// It returns the length of written and a nil error.
+func (b *Builder) WriteUvarint(v uint64) (int, error) {
+	b.copyCheck()
+	n := len(b.buf)
+	b.buf = binary.AppendUvarint(b.buf, v)
+	return b.commit(n), nil
+}
+
+// A PrefixKind selects the length prefix written by WriteLengthPrefixed.
+type PrefixKind int
+
+const (
+	PrefixUvarint  PrefixKind = iota // unsigned varint, as in protocol buffers
+	PrefixUint16BE                   // 2-byte big-endian unsigned integer
+	PrefixUint32BE                   // 4-byte big-endian unsigned integer
+)
+
+// WriteLengthPrefixed appends the length of s in bytes, encoded as selected
+// by prefix, followed by s, to b's buffer.
+// It returns the length of written, including the prefix. If the length
+// of s does not fit in the prefix, b is left unchanged and an error is returned.
+func (b *Builder) WriteLengthPrefixed(s string, prefix PrefixKind) (int, error) {
+	var limit uint64
+	switch prefix {
+	case PrefixUvarint:
+		limit = math.MaxUint64
+	case PrefixUint16BE:
+		limit = math.MaxUint16
+	case PrefixUint32BE:
+		limit = math.MaxUint32
+	default:
+		panic("builder.Builder.WriteLengthPrefixed: unknown prefix kind")
+	}
+	if uint64(len(s)) > limit {
+		return 0, errors.New("builder: length " + strconv.Itoa(len(s)) + " too large for prefix")
+	}
+
+	b.copyCheck()
+	n := len(b.buf)
+	switch prefix {
+	case PrefixUvarint:
+		b.buf = binary.AppendUvarint(b.buf, uint64(len(s)))
+	case PrefixUint16BE:
+		b.buf = binary.BigEndian.AppendUint16(b.buf, uint16(len(s)))
+	case PrefixUint32BE:
+		b.buf = binary.BigEndian.AppendUint32(b.buf, uint32(len(s)))
+	}
+	b.buf = append(b.buf, s...)
+	return b.commit(n), nil
+}
+
+// WriteCString appends s followed by a terminating NUL byte to b's buffer,
+// as expected by C APIs and many legacy file formats.
+// It returns the length of written, including the NUL. If s itself contains
+// a NUL byte, which would truncate it for C, b is left unchanged and an
+// error is returned.
+func (b *Builder) WriteCString(s string) (int, error) {
+	if i := strings.IndexByte(s, 0); i >= 0 {
+		return 0, errors.New("builder: C string contains NUL byte at offset " + strconv.Itoa(i))
+	}
+
+	b.copyCheck()
+	n := len(b.buf)
+	b.buf = append(b.buf, s...)
+	b.buf = append(b.buf, 0)
+	return b.commit(n), nil
+}
@@ -0,0 +1,135 @@
+// Copyright 2023 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package builder_test
+
+import (
+	"bytes"
+	"encoding/binary"
+	"math"
+	"strings"
+	"testing"
+
+	. "github.com/weiwenchen2022/builder"
+)
+
+func TestBuilderWriteBinary(t *testing.T) {
+	t.Parallel()
+
+	for _, order := range []binary.ByteOrder{binary.BigEndian, binary.LittleEndian} {
+		var b Builder
+		b.WriteString("hdr")
+		b.WriteBinaryUint16(0x0102, order)
+		b.WriteBinaryUint32(0x03040506, order)
+		b.WriteBinaryUint64(0x0708090a0b0c0d0e, order)
+		b.WriteBinaryInt16(-2, order)
+		b.WriteBinaryInt32(-3, order)
+		n, err := b.WriteBinaryInt64(-4, order)
+		if err != nil || n != 8 {
+			t.Errorf("WriteBinaryInt64: got %d,%v; want 8,nil", n, err)
+		}
+
+		var want bytes.Buffer
+		want.WriteString("hdr")
+		for _, v := range []any{uint16(0x0102), uint32(0x03040506), uint64(0x0708090a0b0c0d0e), int16(-2), int32(-3), int64(-4)} {
+			binary.Write(&want, order, v)
+		}
+		check(t, &b, want.String())
+	}
+}
+
+func TestBuilderWriteBinaryAllocs(t *testing.T) {
+	var b Builder
+	b.Grow(1024)
+	allocs := testing.AllocsPerRun(10, func() {
+		b.WriteBinaryUint16(1, binary.BigEndian)
+		b.WriteBinaryUint32(2, binary.LittleEndian)
+		b.WriteBinaryInt64(-3, binary.BigEndian)
+	})
+	if allocs != 0 {
+		t.Errorf("WriteBinary allocs = %v; want 0", allocs)
+	}
+}
+
+func TestBuilderWriteVarint(t *testing.T) {
+	t.Parallel()
+
+	for _, v := range []int64{0, 1, -1, 63, -64, 64, 300, -300, math.MaxInt64, math.MinInt64} {
+		var b Builder
+		b.WriteByte('>')
+		want := binary.AppendVarint([]byte(">"), v)
+		n, err := b.WriteVarint(v)
+		if err != nil || n != len(want)-1 {
+			t.Errorf("WriteVarint(%d): got %d,%v; want %d,nil", v, n, err, len(want)-1)
+		}
+		check(t, &b, string(want))
+	}
+
+	for _, v := range []uint64{0, 1, 127, 128, 300, 1 << 35, math.MaxUint64} {
+		var b Builder
+		b.WriteByte('>')
+		want := binary.AppendUvarint([]byte(">"), v)
+		n, err := b.WriteUvarint(v)
+		if err != nil || n != len(want)-1 {
+			t.Errorf("WriteUvarint(%d): got %d,%v; want %d,nil", v, n, err, len(want)-1)
+		}
+		check(t, &b, string(want))
+	}
+}
+
+func TestBuilderWriteLengthPrefixed(t *testing.T) {
+	t.Parallel()
+
+	long := strings.Repeat("x", 300)
+	tests := []struct {
+		s      string
+		prefix PrefixKind
+		want   string
+	}{
+		{"", PrefixUvarint, "\x00"},
+		{"abc", PrefixUvarint, "\x03abc"},
+		{long, PrefixUvarint, "\xac\x02" + long},
+		{"", PrefixUint16BE, "\x00\x00"},
+		{"abc", PrefixUint16BE, "\x00\x03abc"},
+		{long, PrefixUint16BE, "\x01\x2c" + long},
+		{"abc", PrefixUint32BE, "\x00\x00\x00\x03abc"},
+		{long, PrefixUint32BE, "\x00\x00\x01\x2c" + long},
+	}
+	for _, tt := range tests {
+		var b Builder
+		b.WriteByte('>')
+		n, err := b.WriteLengthPrefixed(tt.s, tt.prefix)
+		if err != nil || n != len(tt.want) {
+			t.Errorf("WriteLengthPrefixed(%.10q, %d): got %d,%v; want %d,nil", tt.s, tt.prefix, n, err, len(tt.want))
+		}
+		check(t, &b, ">"+tt.want)
+	}
+
+	var b Builder
+	b.WriteByte('>')
+	if n, err := b.WriteLengthPrefixed(strings.Repeat("x", 1<<16), PrefixUint16BE); n != 0 || err == nil {
+		t.Errorf("WriteLengthPrefixed(64 KiB, PrefixUint16BE): got %d,%v; want 0,error", n, err)
+	}
+	check(t, &b, ">")
+}
+
+func TestBuilderWriteCString(t *testing.T) {
+	t.Parallel()
+
+	var b Builder
+	for _, s := range []string{"hello", "", "héllo"} {
+		n, err := b.WriteCString(s)
+		if err != nil || n != len(s)+1 {
+			t.Errorf("WriteCString(%q): got %d,%v; want %d,nil", s, n, err, len(s)+1)
+		}
+	}
+	check(t, &b, "hello\x00\x00héllo\x00")
+
+	for _, s := range []string{"\x00", "a\x00b", "ab\x00"} {
+		if n, err := b.WriteCString(s); n != 0 || err == nil {
+			t.Errorf("WriteCString(%q): got %d,%v; want 0,error", s, n, err)
+		}
+	}
+	check(t, &b, "hello\x00\x00héllo\x00")
+}
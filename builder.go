@@ -18,6 +18,54 @@ import (
 type Builder struct {
 	addr *Builder // of receiver, to detect copies by value
 	buf  []byte
+	mode *mode // optional write modes; nil until one is enabled
+}
+
+// mode holds the state of the optional write modes of a Builder.
+type mode struct {
+	sep      string
+	sepArmed bool // a write happened since SetSep; separate the next one
+
+	prefixes []string // stack of line prefixes
+	prefix   string   // concatenation of prefixes
+	midLine  bool     // the last byte written was not a newline
+	scratch  []byte   // reused when rewriting written bytes
+
+	eastAsian    bool // measure display width rather than runes
+	noHyperlinks bool // WriteHyperlink writes plain text
+	crlf         bool // end lines with "\r\n" rather than "\n"
+	csvComma     rune // CSV field delimiter if not ','
+
+	shortcodes map[string]string // table for WriteWithShortcodes if not Shortcodes
+	floats     *FloatPolicy      // set by SetFloatPolicy
+
+	rebuilding bool   // RebuildFrom was called
+	prev       string // content being rebuilt
+	same       int    // length of the common prefix of b.buf and prev
+	spare      []byte // buffer retired by the previous RebuildFrom
+	released   *byte  // start of the buffer last passed to Release
+
+	validators []validator
+	err        *ValidationError // first violation reported by a validator
+
+	recording bool
+	records   []WriteRecord
+
+	counted  bool // the builder has been counted in Stats.BuildersUsed
+	statsCap int  // capacity of the buffer at the last recorded write
+
+	fixed []byte // caller's buffer given to NewFixed
+	full  bool   // a write did not fit in fixed
+
+	timings []SectionTiming
+
+	trackCuts bool   // a Document has slots to keep in step with cuts
+	cutTo     int    // shortest length the content was cut to, if trackCuts
+	section   string // name of the innermost running TimedSection
+
+	once map[string]struct{} // keys written by WriteOnce
+
+	transformers []Transformer // stack set up by PushTransformer
 }
 
 // noescape hides a pointer from escape analysis. It is the identity function
@@ -46,6 +94,124 @@ func (b *Builder) copyCheck() {
 	}
 }
 
+// commit finishes a write that appended b.buf[n:], applying any enabled write
+// modes to the appended bytes. It returns the number of bytes the write itself
+// appended, which does not include bytes added by the modes.
+// Every write method calls commit exactly once.
+func (b *Builder) commit(n int) int {
+	if b.mode != nil && b.mode.overflowed(b, n) {
+		return 0
+	}
+	m := len(b.buf) - n
+	if statsEnabled.Load() {
+		b.recordStats(m)
+	}
+	if b.mode != nil {
+		if b.mode.recording {
+			b.mode.record(b, n)
+		}
+		b.mode.apply(b, n)
+		if b.mode.overflowed(b, n) {
+			return 0
+		}
+	}
+	return m
+}
+
+func (m *mode) apply(b *Builder, n int) {
+	if len(m.transformers) > 0 {
+		m.transform(b, n)
+	}
+	if m.sep != "" {
+		if m.sepArmed {
+			b.buf = insertString(b.buf, n, m.sep)
+		}
+		m.sepArmed = true
+	}
+	if m.prefix != "" {
+		m.prefixLines(b, n)
+	}
+	if m.rebuilding && m.same == n && n <= len(m.prev) {
+		m.same += commonPrefix(b.buf[n:], m.prev[n:])
+	}
+	if m.err == nil {
+		m.validate(b.buf[n:], n)
+	}
+}
+
+// insertString inserts s into buf at offset i.
+func insertString(buf []byte, i int, s string) []byte {
+	buf = append(buf, s...)
+	copy(buf[i+len(s):], buf[i:len(buf)-len(s)])
+	copy(buf[i:], s)
+	return buf
+}
+
+// SetSep enables automatic separator mode: every write after the first one
+// following the call is preceded by sep, so delimited lists can be built
+// without tracking which element comes first. Each call to a write method
+// counts as one write, even if it writes nothing. The counts returned by write
+// methods do not include the separator. An empty sep disables the mode.
+func (b *Builder) SetSep(sep string) {
+	if sep == "" && b.mode == nil {
+		return
+	}
+	if b.mode == nil {
+		b.mode = new(mode)
+	}
+	b.mode.sep = sep
+	b.mode.sepArmed = false
+}
+
+// Sep is like SetSep but returns b, so that it can be chained with a write.
+func (b *Builder) Sep(sep string) *Builder {
+	b.SetSep(sep)
+	return b
+}
+
+// SetEastAsianWidth sets how the padding, alignment and truncation helpers
+// measure text. By default they count runes. When enabled is true, they
+// measure display width instead: East Asian wide and fullwidth characters
+// take two columns, while combining marks and other zero-width characters
+// take none, as in a terminal.
+func (b *Builder) SetEastAsianWidth(enabled bool) {
+	if !enabled && b.mode == nil {
+		return
+	}
+	if b.mode == nil {
+		b.mode = new(mode)
+	}
+	b.mode.eastAsian = enabled
+}
+
+// A LineEnding is a line terminator.
+type LineEnding int
+
+const (
+	LF   LineEnding = iota // "\n", the default
+	CRLF                   // "\r\n", as expected by many Windows programs
+)
+
+// SetLineEnding sets the line terminator written by WriteLine and by the
+// other helpers that end lines, such as WriteWrapped and Table.Row.
+func (b *Builder) SetLineEnding(le LineEnding) {
+	if le == LF && b.mode == nil {
+		return
+	}
+	if b.mode == nil {
+		b.mode = new(mode)
+	}
+	b.mode.crlf = le == CRLF
+}
+
+// eol returns the line terminator set by SetLineEnding.
+func (b *Builder) eol() string {
+	if b.mode != nil && b.mode.crlf {
+		return "\r\n"
+	}
+	return "\n"
+}
+
 // String returns the accumulated string.
 func (b *Builder) String() string {
 	return unsafe.String(unsafe.SliceData(b.buf), len(b.buf))
@@ -54,21 +220,51 @@ func (b *Builder) String() string {
 // Len returns the number of accumulated bytes; b.Len() == len(b.String()).
 func (b *Builder) Len() int { return len(b.buf) }
 
+// LastByte returns the last byte of b's content, and false if b is empty.
+func (b *Builder) LastByte() (byte, bool) {
+	if len(b.buf) == 0 {
+		return 0, false
+	}
+	return b.buf[len(b.buf)-1], true
+}
+
+// LastRune returns the last rune of b's content and its width in bytes, and
+// false if b is empty. If the content ends in invalid UTF-8, such as the start
+// of an incomplete encoding, it returns utf8.RuneError and a width of 1.
+func (b *Builder) LastRune() (rune, int, bool) {
+	if len(b.buf) == 0 {
+		return 0, 0, false
+	}
+	r, size := utf8.DecodeLastRune(b.buf)
+	return r, size, true
+}
+
 // Cap returns the capacity of the builder's underlying byte slice. It is the
 // total space allocated for the string being built and includes any bytes
 // already written.
 func (b *Builder) Cap() int { return cap(b.buf) }
 
-// Reset resets the Builder to be empty.
+// Reset resets the Builder to be empty and disables any write modes.
+// A Builder created by NewFixed stays fixed to its buffer.
 func (b *Builder) Reset() {
+	if b.mode != nil && b.mode.fixed != nil {
+		*b.mode = mode{fixed: b.mode.fixed}
+		b.buf = b.mode.fixed
+		return
+	}
 	b.addr = nil
 	b.buf = nil
+	b.mode = nil
 }
 
 // grow copies the buffer to a new, larger buffer so that there are at least n
 // bytes of capacity beyond len(b.buf).
 func (b *Builder) grow(n int) {
-	buf := make([]byte, len(b.buf), 2*cap(b.buf)+n)
+	c := 2*cap(b.buf) + n
+	if cap(b.buf) > (maxInt-n)/2 {
+		c = len(b.buf) + n
+	}
+	buf := make([]byte, len(b.buf), c)
 	copy(buf, b.buf)
 	b.buf = buf
 }
@@ -76,13 +272,14 @@ func (b *Builder) grow(n int) {
 // Grow grows b's capacity, if necessary, to guarantee space for
 // another n bytes. After Grow(n), at least n bytes can be written to b
 // without another allocation. If n is negative, Grow panics.
+// Grow does nothing if b was created by NewFixed.
 func (b *Builder) Grow(n int) {
 	b.copyCheck()
 	if n < 0 {
 		panic("builder.Builder.Grow: negative count")
 	}
 
-	if cap(b.buf)-len(b.buf) < n {
+	if cap(b.buf)-len(b.buf) < n && (b.mode == nil || b.mode.fixed == nil) {
 		b.grow(n)
 	}
 }
@@ -94,51 +291,239 @@ func (b *Builder) Clip() {
 }
 
 // Write appends the contents of p to b's buffer.
-// Write always returns len(p), nil.
+// Write returns len(p), nil, unless b was created by NewFixed and p does not
+// fit, in which case it returns 0, ErrFull.
 func (b *Builder) Write(p []byte) (int, error) {
 	b.copyCheck()
+	if b.full(len(p)) {
+		return 0, ErrFull
+	}
+	n := len(b.buf)
 	b.buf = append(b.buf, p...)
+	if b.commit(n) < len(p) {
+		return 0, ErrFull
+	}
 	return len(p), nil
 }
 
 // WriteByte appends the byte c to b's buffer.
-// The returned error is always nil.
+// The returned error is nil, unless b was created by NewFixed and is full.
 func (b *Builder) WriteByte(c byte) error {
 	b.copyCheck()
+	if b.full(1) {
+		return ErrFull
+	}
+	n := len(b.buf)
 	b.buf = append(b.buf, c)
+	if b.commit(n) < 1 {
+		return ErrFull
+	}
 	return nil
 }
 
 // WriteRune appends the UTF-8 encoding of Unicode code point r to b's buffer.
-// It returns the length of r and a nil error.
+// It returns the length of r and a nil error, unless b was created by
+// NewFixed and r does not fit.
 func (b *Builder) WriteRune(r rune) (int, error) {
 	// Compare as uint32 to correctly handle negative runes.
 	if uint32(r) < utf8.RuneSelf {
-		_ = b.WriteByte(byte(r))
+		if err := b.WriteByte(byte(r)); err != nil {
+			return 0, err
+		}
 		return 1, nil
 	}
 
 	b.copyCheck()
+	if b.full(utf8.RuneLen(r)) {
+		return 0, ErrFull
+	}
 	n := len(b.buf)
 	b.buf = utf8.AppendRune(b.buf, r)
-	return len(b.buf) - n, nil
+	m := b.commit(n)
+	if m == 0 {
+		return 0, ErrFull
+	}
+	return m, nil
+}
+
+// WriteRunes appends the UTF-8 encoding of the code points in rs to b's
+// buffer, growing it at most once. Invalid code points are written as
+// utf8.RuneError, as by WriteRune.
+// It returns the length of written and a nil error, unless b was created by
+// NewFixed and rs does not fit.
+func (b *Builder) WriteRunes(rs []rune) (int, error) {
+	size := 0
+	for _, r := range rs {
+		if uint32(r) < utf8.RuneSelf {
+			size++
+		} else if k := utf8.RuneLen(r); k > 0 {
+			size += k
+		} else {
+			size += len(string(utf8.RuneError))
+		}
+	}
+
+	if !b.reserve(size) {
+		return 0, ErrFull
+	}
+	n := len(b.buf)
+	for _, r := range rs {
+		if uint32(r) < utf8.RuneSelf {
+			b.buf = append(b.buf, byte(r))
+		} else {
+			b.buf = utf8.AppendRune(b.buf, r)
+		}
+	}
+	if b.commit(n) < size {
+		return 0, ErrFull
+	}
+	return size, nil
 }
 
 // WriteString appends the contents of s to b's buffer.
-// It returns the length of s and a nil error.
+// It returns the length of s and a nil error, unless b was created by
+// NewFixed and s does not fit.
 func (b *Builder) WriteString(s string) (int, error) {
 	b.copyCheck()
+	if b.full(len(s)) {
+		return 0, ErrFull
+	}
+	n := len(b.buf)
 	b.buf = append(b.buf, s...)
+	if b.commit(n) < len(s) {
+		return 0, ErrFull
+	}
 	return len(s), nil
 }
 
+// WriteLine appends s followed by a line ending, as set by SetLineEnding,
+// to b's buffer.
+// It returns the length of written and a nil error.
+func (b *Builder) WriteLine(s string) (int, error) {
+	b.copyCheck()
+	n := len(b.buf)
+	b.buf = append(b.buf, s...)
+	b.buf = append(b.buf, b.eol()...)
+	return b.commit(n), nil
+}
+
+// maxInt is the largest value of type int.
+const maxInt = int(^uint(0) >> 1)
+
+// WriteByteN appends count copies of the byte c to b's buffer, growing the
+// buffer at most once. It panics if count is negative.
+// It returns count and a nil error, unless b was created by NewFixed and the
+// bytes do not fit.
+func (b *Builder) WriteByteN(c byte, count int) (int, error) {
+	if count < 0 {
+		panic("builder.Builder.WriteByteN: negative count")
+	}
+
+	if !b.reserve(count) {
+		return 0, ErrFull
+	}
+	n := len(b.buf)
+	b.buf = b.buf[:n+count]
+	fill(b.buf[n:], c)
+	return b.commit(n), nil
+}
+
+// fill sets every byte of p to c.
+func fill(p []byte, c byte) {
+	if len(p) == 0 {
+		return
+	}
+	p[0] = c
+	for k := 1; k < len(p); k *= 2 {
+		copy(p[k:], p[:k])
+	}
+}
+
+// WriteRepeat appends count copies of s to b's buffer. After the first copy,
+// the appended region is doubled in place, so it matches strings.Repeat
+// without the separate allocation.
+// It panics if count is negative or if len(s) * count overflows.
+// It returns the length of written and a nil error.
+func (b *Builder) WriteRepeat(s string, count int) (int, error) {
+	if count < 0 {
+		panic("builder.Builder.WriteRepeat: negative count")
+	}
+	if len(s) > 0 && count > maxInt/len(s) {
+		panic("builder.Builder.WriteRepeat: output length overflow")
+	}
+
+	size := len(s) * count
+	if !b.reserve(size) {
+		return 0, ErrFull
+	}
+	n := len(b.buf)
+	b.buf = b.buf[:n+size]
+	dst := b.buf[n:]
+	for k := copy(dst, s); k < size; {
+		k += copy(dst[k:], dst[:k])
+	}
+	return b.commit(n), nil
+}
+
+// WriteJoin appends the elements of elems, separated by sep, to b's buffer.
+// It is like strings.Join but without the intermediate string.
+// It panics if the output length overflows an int.
+// It returns the length of written and a nil error.
+func (b *Builder) WriteJoin(sep string, elems ...string) (int, error) {
+	if len(elems) == 0 {
+		b.copyCheck()
+		return b.commit(len(b.buf)), nil
+	}
+
+	var p SizePlan
+	p.AddN(len(elems)-1, len(sep))
+	for _, e := range elems {
+		p.Add(len(e))
+	}
+	size, err := p.Size()
+	if err != nil {
+		panic("builder.Builder.WriteJoin: output length overflow")
+	}
+	if !b.reserve(size) {
+		return 0, ErrFull
+	}
+
+	n := len(b.buf)
+	b.buf = append(b.buf, elems[0]...)
+	for _, e := range elems[1:] {
+		b.buf = append(b.buf, sep...)
+		b.buf = append(b.buf, e...)
+	}
+	return b.commit(n), nil
+}
+
+// WriteJoinFunc calls fn for each element of elems, in order, writing sep to
+// b between consecutive calls.
+func WriteJoinFunc[T any](b *Builder, sep string, elems []T, fn func(*Builder, T)) {
+	for i, e := range elems {
+		if i > 0 {
+			b.WriteString(sep)
+		}
+		fn(b, e)
+	}
+}
+
+// WriteError appends err.Error(), or "<nil>" if err is nil, to b's buffer.
+// It returns the length of written and a nil error.
+func (b *Builder) WriteError(err error) (int, error) {
+	if err == nil {
+		return b.WriteString("<nil>")
+	}
+	return b.WriteString(err.Error())
+}
+
 // WriteBool appends "true" or "false", according to the value of v, to b's buffer.
 // It returns the length of written and a nil error.
 func (b *Builder) WriteBool(v bool) (int, error) {
 	b.copyCheck()
 	n := len(b.buf)
 	b.buf = strconv.AppendBool(b.buf, v)
-	return len(b.buf) - n, nil
+	return b.commit(n), nil
 }
 
 // WriteInt appends the string form of the integer i,
@@ -148,7 +533,7 @@ func (b *Builder) WriteInt(i int64, base int) (int, error) {
 	b.copyCheck()
 	n := len(b.buf)
 	b.buf = strconv.AppendInt(b.buf, i, base)
-	return len(b.buf) - n, nil
+	return b.commit(n), nil
 }
 
 // WriteUint appends the string form of the unsigned integer i,
@@ -158,7 +543,7 @@ func (b *Builder) WriteUint(i uint64, base int) (int, error) {
 	b.copyCheck()
 	n := len(b.buf)
 	b.buf = strconv.AppendUint(b.buf, i, base)
-	return len(b.buf) - n, nil
+	return b.commit(n), nil
 }
 
 // WriteFloat appends the string form of the floating-point number f,
@@ -167,8 +552,32 @@ func (b *Builder) WriteUint(i uint64, base int) (int, error) {
 func (b *Builder) WriteFloat(f float64, fmt byte, prec, bitSize int) (int, error) {
 	b.copyCheck()
 	n := len(b.buf)
-	b.buf = strconv.AppendFloat(b.buf, f, fmt, prec, bitSize)
-	return len(b.buf) - n, nil
+	b.buf = b.appendFloat(b.buf, f, fmt, prec, bitSize)
+	return b.commit(n), nil
+}
+
+// WriteComplex appends the string form of the complex number c,
+// as generated by FormatComplex, to b's buffer. bitSize is 64 for
+// complex64 and 128 for complex128; WriteComplex panics otherwise.
+// It returns the length of written and a nil error.
+func (b *Builder) WriteComplex(c complex128, fmt byte, prec, bitSize int) (int, error) {
+	if bitSize != 64 && bitSize != 128 {
+		panic("builder.Builder.WriteComplex: invalid bitSize")
+	}
+	bitSize >>= 1 // complex64 uses float32 internally
+
+	b.copyCheck()
+	n := len(b.buf)
+	b.buf = append(b.buf, '(')
+	b.buf = b.appendFloat(b.buf, real(c), fmt, prec, bitSize)
+	i := len(b.buf)
+	b.buf = b.appendFloat(b.buf, imag(c), fmt, prec, bitSize)
+	// Add a sign if the imaginary part has none, as for NaN and zero.
+	if c := b.buf[i]; c != '+' && c != '-' {
+		b.buf = insertString(b.buf, i, "+")
+	}
+	b.buf = append(b.buf, "i)"...)
+	return b.commit(n), nil
 }
 
 // WriteQuote appends a double-quoted Go string literal representing s,
@@ -178,7 +587,7 @@ func (b *Builder) WriteQuote(s string) (int, error) {
 	b.copyCheck()
 	n := len(b.buf)
 	b.buf = strconv.AppendQuote(b.buf, s)
-	return len(b.buf) - n, nil
+	return b.commit(n), nil
 }
 
 // WriteQuoteRune appends a single-quoted Go character literal representing the rune,
@@ -188,7 +597,7 @@ func (b *Builder) WriteQuoteRune(r rune) (int, error) {
 	b.copyCheck()
 	n := len(b.buf)
 	b.buf = strconv.AppendQuoteRune(b.buf, r)
-	return len(b.buf) - n, nil
+	return b.commit(n), nil
 }
 
 // WriteQuoteRuneToASCII appends a single-quoted Go character literal representing the rune,
@@ -198,7 +607,7 @@ func (b *Builder) WriteQuoteRuneToASCII(r rune) (int, error) {
 	b.copyCheck()
 	n := len(b.buf)
 	b.buf = strconv.AppendQuoteRuneToASCII(b.buf, r)
-	return len(b.buf) - n, nil
+	return b.commit(n), nil
 }
 
 // WriteQuoteRuneToGraphic appends a single-quoted Go character literal representing the rune,
@@ -208,7 +617,7 @@ func (b *Builder) WriteQuoteRuneToGraphic(r rune) (int, error) {
 	b.copyCheck()
 	n := len(b.buf)
 	b.buf = strconv.AppendQuoteRuneToGraphic(b.buf, r)
-	return len(b.buf) - n, nil
+	return b.commit(n), nil
 }
 
 // WriteQuoteToASCII appends a double-quoted Go string literal representing s,
@@ -218,7 +627,7 @@ func (b *Builder) WriteQuoteToASCII(s string) (int, error) {
 	b.copyCheck()
 	n := len(b.buf)
 	b.buf = strconv.AppendQuoteToASCII(b.buf, s)
-	return len(b.buf) - n, nil
+	return b.commit(n), nil
 }
 
 // WriteQuoteToGraphic appends a double-quoted Go string literal representing s,
@@ -228,5 +637,5 @@ func (b *Builder) WriteQuoteToGraphic(s string) (int, error) {
 	b.copyCheck()
 	n := len(b.buf)
 	b.buf = strconv.AppendQuoteToGraphic(b.buf, s)
-	return len(b.buf) - n, nil
+	return b.commit(n), nil
 }
@@ -5,14 +5,16 @@
 package builder_test
 
 import (
-	"fmt"
-	"reflect"
+	"errors"
+	"math"
 	"strconv"
 	"strings"
+	"sync"
 	"testing"
 	"unicode/utf8"
 
 	. "github.com/weiwenchen2022/builder"
+	"github.com/weiwenchen2022/builder/builderbench"
 )
 
 func check(t *testing.T, b *Builder, want string) {
@@ -81,6 +83,33 @@ func TestBuilderString(t *testing.T) {
 	}
 }
 
+func TestBuilderStringConcurrent(t *testing.T) {
+	t.Parallel()
+
+	// String and the other read methods must not write to b, so that they
+	// can be called concurrently; run with -race.
+	var b Builder
+	b.WriteString("line1\nline2\n")
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				if got := b.String(); got != "line1\nline2\n" {
+					t.Errorf("String: got %q", got)
+					return
+				}
+				if r, _, _ := b.LastRune(); r != '\n' || b.Len() != 12 {
+					t.Errorf("LastRune, Len: got %q, %d", r, b.Len())
+					return
+				}
+			}
+		}()
+	}
+	wg.Wait()
+}
+
 func TestBuilderReset(t *testing.T) {
 	t.Parallel()
 
@@ -189,12 +218,78 @@ func TestBuilderWrite2(t *testing.T) {
 			3,
 			"世",
 		},
+		{
+			"WriteRunes",
+			func(b *Builder) (int, error) { return b.WriteRunes([]rune(s)) },
+			len(s),
+			s,
+		},
+		{
+			"WriteRunesInvalid",
+			func(b *Builder) (int, error) { return b.WriteRunes([]rune{'a', -1, 0xD800, utf8.MaxRune + 1}) },
+			10,
+			"a\uFFFD\uFFFD\uFFFD",
+		},
 		{
 			"WriteString",
 			func(b *Builder) (int, error) { return b.WriteString(s) },
 			len(s),
 			s,
 		},
+		{
+			"WriteByteN",
+			func(b *Builder) (int, error) { return b.WriteByteN('-', 5) },
+			5,
+			"-----",
+		},
+		{
+			"WriteByteNZero",
+			func(b *Builder) (int, error) { return b.WriteByteN('-', 0) },
+			0,
+			"",
+		},
+		{
+			"WriteLine",
+			func(b *Builder) (int, error) { return b.WriteLine("line") },
+			5,
+			"line\n",
+		},
+		{
+			"WriteRepeat",
+			func(b *Builder) (int, error) { return b.WriteRepeat("ab", 5) },
+			10,
+			"ababababab",
+		},
+		{
+			"WriteRepeatZero",
+			func(b *Builder) (int, error) { return b.WriteRepeat("ab", 0) },
+			0,
+			"",
+		},
+		{
+			"WriteJoin",
+			func(b *Builder) (int, error) { return b.WriteJoin(", ", "a", "bc", "", "d") },
+			len("a, bc, , d"),
+			"a, bc, , d",
+		},
+		{
+			"WriteJoinEmpty",
+			func(b *Builder) (int, error) { return b.WriteJoin(", ") },
+			0,
+			"",
+		},
+		{
+			"WriteError",
+			func(b *Builder) (int, error) { return b.WriteError(errors.New("boom")) },
+			4,
+			"boom",
+		},
+		{
+			"WriteErrorNil",
+			func(b *Builder) (int, error) { return b.WriteError(nil) },
+			5,
+			"<nil>",
+		},
 		{
 			"WriteBool",
 			func(b *Builder) (int, error) { return b.WriteBool(true) },
@@ -237,6 +332,24 @@ func TestBuilderWrite2(t *testing.T) {
 			len("3.1415926535E+00"),
 			"3.1415926535E+00",
 		},
+		{
+			"WriteComplex64",
+			func(b *Builder) (int, error) { return b.WriteComplex(complex(3.1415926535, -1), 'g', -1, 64) },
+			len(strconv.FormatComplex(complex(3.1415926535, -1), 'g', -1, 64)),
+			strconv.FormatComplex(complex(3.1415926535, -1), 'g', -1, 64),
+		},
+		{
+			"WriteComplex128",
+			func(b *Builder) (int, error) { return b.WriteComplex(complex(-1.5, 2), 'e', 3, 128) },
+			len("(-1.500e+00+2.000e+00i)"),
+			"(-1.500e+00+2.000e+00i)",
+		},
+		{
+			"WriteComplexNaN",
+			func(b *Builder) (int, error) { return b.WriteComplex(complex(math.Inf(1), math.NaN()), 'f', 2, 128) },
+			len("(+Inf+NaNi)"),
+			"(+Inf+NaNi)",
+		},
 		{
 			"WriteQuote",
 			func(b *Builder) (int, error) { return b.WriteQuote(`"Fran & Freddie's Diner"`) },
@@ -323,6 +436,113 @@ func TestBuilderWrite2(t *testing.T) {
 	}
 }
 
+func TestBuilderWriteByteN(t *testing.T) {
+	t.Parallel()
+
+	for _, count := range []int{1, 2, 3, 100, 4097} {
+		var b Builder
+		b.WriteString("|")
+		b.WriteByteN(' ', count)
+		check(t, &b, "|"+strings.Repeat(" ", count))
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Error("WriteByteN(' ', -1) should panic")
+		}
+	}()
+	var b Builder
+	b.WriteByteN(' ', -1)
+}
+
+func TestBuilderWriteRepeat(t *testing.T) {
+	t.Parallel()
+
+	for _, count := range []int{1, 2, 3, 7, 64, 1000} {
+		var b Builder
+		b.WriteString("<")
+		b.WriteRepeat("abc", count)
+		check(t, &b, "<"+strings.Repeat("abc", count))
+	}
+
+	for _, count := range []int{-1, maxInt/2 + 1} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("WriteRepeat(%q, %d) should panic", "ab", count)
+				}
+			}()
+			var b Builder
+			b.WriteRepeat("ab", count)
+		}()
+	}
+}
+
+const maxInt = int(^uint(0) >> 1)
+
+func TestWriteJoinFunc(t *testing.T) {
+	t.Parallel()
+
+	var b Builder
+	WriteJoinFunc(&b, ", ", []int{1, 2, 3}, func(b *Builder, i int) {
+		b.WriteInt(int64(i*i), 10)
+	})
+	check(t, &b, "1, 4, 9")
+
+	b.Reset()
+	WriteJoinFunc(&b, ", ", nil, func(*Builder, string) { t.Error("fn called for empty slice") })
+	check(t, &b, "")
+}
+
+func TestBuilderSep(t *testing.T) {
+	t.Parallel()
+
+	var b Builder
+	b.WriteString("SELECT ")
+	b.SetSep(", ")
+	for _, col := range []string{"id", "name", "email"} {
+		if n, _ := b.WriteString(col); len(col) != n {
+			t.Errorf("WriteString(%q): got n=%d; want %d", col, n, len(col))
+		}
+	}
+	b.WriteInt(42, 10)
+	b.SetSep("")
+	b.WriteString(" FROM t")
+	check(t, &b, "SELECT id, name, email, 42 FROM t")
+
+	// Empty writes are separated too, as needed for empty CSV fields.
+	b.Reset()
+	b.Sep(",").WriteString("a")
+	b.WriteString("")
+	b.WriteByte('c')
+	b.WriteRune('世')
+	check(t, &b, "a,,c,世")
+
+	// Reset disables the mode.
+	b.Reset()
+	b.WriteString("x")
+	b.WriteString("y")
+	check(t, &b, "xy")
+}
+
+func TestBuilderLineEnding(t *testing.T) {
+	t.Parallel()
+
+	var b Builder
+	b.SetLineEnding(CRLF)
+	if n, _ := b.WriteLine("@echo off"); n != len("@echo off\r\n") {
+		t.Errorf("WriteLine: got n=%d; want %d", n, len("@echo off\r\n"))
+	}
+	b.WriteWrapped("one two\nthree", 3)
+	b.WriteLine("")
+	tbl := b.Columns(3, 3)
+	tbl.Row("a", "b")
+	tbl.Rule('-')
+	b.SetLineEnding(LF)
+	b.WriteLine("end")
+	check(t, &b, "@echo off\r\none\r\ntwo\r\nthree\r\na    b\r\n--------\r\nend\n")
+}
+
 func TestBuilderWriteByte(t *testing.T) {
 	t.Parallel()
 
@@ -350,6 +570,34 @@ func TestBuilderAllocs(t *testing.T) {
 	}
 }
 
+func TestBuilderSmallBuildAllocs(t *testing.T) {
+	// A small build through the commonly used write methods allocates only
+	// its buffer: neither the write modes nor the helpers may make the
+	// Builder itself escape. The buffer cannot live on the stack, since
+	// String returns a string sharing it that may outlive the Builder.
+	n := testing.AllocsPerRun(10000, func() {
+		var b Builder
+		b.Grow(64)
+		b.WriteString("id=")
+		b.WriteInt(-42, 10)
+		b.WriteByte(' ')
+		b.WriteRune('é')
+		b.WriteUint(7, 16)
+		b.WriteBool(true)
+		b.WriteFloat(1.5, 'g', -1, 64)
+		b.WriteJSONString("q")
+		b.WriteHTMLEscaped("<a>")
+		b.WriteByteN('-', 3)
+		if b.Len() >= 64 {
+			panic("build is not small")
+		}
+		_ = b.String()
+	})
+	if n != 1 {
+		t.Errorf("Builder allocs = %v; want 1", n)
+	}
+}
+
 func TestBuilderCopyPanic(t *testing.T) {
 	t.Parallel()
 
@@ -475,70 +723,57 @@ func TestBuilderCopyPanic(t *testing.T) {
 	}
 }
 
-func TestBuilderWriteInvalidRune(t *testing.T) {
-	// Invalid runes, including negative ones, should be written as
-	// utf8.RuneError.
-	for _, r := range []rune{-1, utf8.MaxRune + 1} {
+func TestBuilderWriteRunesAllocs(t *testing.T) {
+	rs := []rune("hello, \u4e16\u754c \U0001F600")
+	allocs := testing.AllocsPerRun(100, func() {
 		var b Builder
-		b.WriteRune(r)
-		check(t, &b, "\uFFFD")
+		b.WriteRunes(rs)
+		_ = b.String()
+	})
+	if allocs != 1 {
+		t.Errorf("WriteRunes allocs = %v; want 1", allocs)
 	}
 }
 
-var someBytes = []byte("some bytes sdljlk jsklj3lkjlk djlkjw")
-
-type builderInterface interface {
-	Grow(n int)
-	Write(p []byte) (int, error)
-	String() string
-}
+func TestBuilderLastByteRune(t *testing.T) {
+	t.Parallel()
 
-func benchmarkBuilder(b *testing.B, f func(b *testing.B, buf builderInterface, numWrite int, grow bool)) {
-	b.Run("1Write_NoGrow", func(b *testing.B) {
-		for _, buf := range []builderInterface{&strings.Builder{}, &Builder{}} {
-			b.Run(fmt.Sprintf("%T", buf), func(b *testing.B) {
-				b.ReportAllocs()
-				for i := 0; i < b.N; i++ {
-					buf = reflect.New(reflect.TypeOf(buf).Elem()).Interface().(builderInterface)
-					f(b, buf, 1, false)
-				}
-			})
-		}
-	})
-	b.Run("3Write_NoGrow", func(b *testing.B) {
-		for _, buf := range []builderInterface{&strings.Builder{}, &Builder{}} {
-			b.Run(fmt.Sprintf("%T", buf), func(b *testing.B) {
-				b.ReportAllocs()
-				for i := 0; i < b.N; i++ {
-					buf = reflect.New(reflect.TypeOf(buf).Elem()).Interface().(builderInterface)
-					f(b, buf, 3, false)
-				}
-			})
+	tests := []struct {
+		in   string
+		c    byte
+		r    rune
+		size int
+		ok   bool
+	}{
+		{"", 0, 0, 0, false},
+		{"ab\n", '\n', '\n', 1, true},
+		{"caf\u00e9", 0xa9, '\u00e9', 2, true},
+		{"x\U0001F600", 0x80, '\U0001F600', 4, true},
+		{"x\xe4\xb8", 0xb8, utf8.RuneError, 1, true},
+		{"x\xff", 0xff, utf8.RuneError, 1, true},
+	}
+	for _, tt := range tests {
+		var b Builder
+		b.WriteString(tt.in)
+		if c, ok := b.LastByte(); c != tt.c || ok != tt.ok {
+			t.Errorf("LastByte of %q: got %#x,%v; want %#x,%v", tt.in, c, ok, tt.c, tt.ok)
 		}
-	})
-	b.Run("3Write_Grow", func(b *testing.B) {
-		for _, buf := range []builderInterface{&strings.Builder{}, &Builder{}} {
-			b.Run(fmt.Sprintf("%T", buf), func(b *testing.B) {
-				b.ReportAllocs()
-				for i := 0; i < b.N; i++ {
-					buf = reflect.New(reflect.TypeOf(buf).Elem()).Interface().(builderInterface)
-					f(b, buf, 3, true)
-				}
-			})
+		if r, size, ok := b.LastRune(); r != tt.r || size != tt.size || ok != tt.ok {
+			t.Errorf("LastRune of %q: got %q,%d,%v; want %q,%d,%v", tt.in, r, size, ok, tt.r, tt.size, tt.ok)
 		}
-	})
+	}
 }
 
-var sinkS string
+func TestBuilderWriteInvalidRune(t *testing.T) {
+	// Invalid runes, including negative ones, should be written as
+	// utf8.RuneError.
+	for _, r := range []rune{-1, utf8.MaxRune + 1} {
+		var b Builder
+		b.WriteRune(r)
+		check(t, &b, "\uFFFD")
+	}
+}
 
 func BenchmarkBuildString_Builder(b *testing.B) {
-	benchmarkBuilder(b, func(b *testing.B, buf builderInterface, numWrite int, grow bool) {
-		if grow {
-			buf.Grow(len(someBytes) * numWrite)
-		}
-		for j := 0; j < numWrite; j++ {
-			buf.Write(someBytes)
-		}
-		sinkS = buf.String()
-	})
+	builderbench.Run(b, builderbench.Standard...)
 }
@@ -0,0 +1,152 @@
+// Copyright 2023 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package builderbench compares builder.Builder with strings.Builder and
+// bytes.Buffer on a given workload, so that users can measure their own write
+// patterns and check whether a Grow hint pays off. Call Run from a benchmark:
+//
+//	func BenchmarkReport(b *testing.B) {
+//		builderbench.Run(b, builderbench.Workload{
+//			Name:   "Rows",
+//			Chunks: builderbench.Chunks(100, 40),
+//			Grow:   true,
+//		})
+//	}
+package builderbench
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/weiwenchen2022/builder"
+)
+
+// A Target is a string builder under comparison.
+type Target interface {
+	Grow(n int)
+	Write(p []byte) (int, error)
+	WriteByte(c byte) error
+	WriteString(s string) (int, error)
+	String() string
+}
+
+// An Impl is a named implementation of Target.
+type Impl struct {
+	Name string
+	New  func() Target // returns a new, empty Target
+}
+
+// Impls are the implementations compared by Run.
+var Impls = []Impl{
+	{"strings.Builder", func() Target { return new(strings.Builder) }},
+	{"bytes.Buffer", func() Target { return new(bytes.Buffer) }},
+	{"builder.Builder", func() Target { return new(builder.Builder) }},
+}
+
+// A WriteKind selects the method a Workload writes its chunks with.
+type WriteKind int
+
+const (
+	WriteBytes  WriteKind = iota // Write
+	WriteString                  // WriteString
+	WriteByte                    // WriteByte, once per byte
+)
+
+// A Workload describes the building of one string.
+type Workload struct {
+	Name   string    // name of the sub-benchmark
+	Chunks []string  // written in order
+	Kind   WriteKind // how the chunks are written
+	Grow   bool      // call Grow with the total size first
+}
+
+// Size returns the length of the string built by w.
+func (w Workload) Size() int {
+	size := 0
+	for _, c := range w.Chunks {
+		size += len(c)
+	}
+	return size
+}
+
+// Build builds the string described by w with t and returns it.
+func (w Workload) Build(t Target) string {
+	return w.build(t, w.byteChunks())
+}
+
+// byteChunks returns the chunks of w as byte slices if Write is used.
+func (w Workload) byteChunks() [][]byte {
+	if w.Kind != WriteBytes {
+		return nil
+	}
+	chunks := make([][]byte, len(w.Chunks))
+	for i, c := range w.Chunks {
+		chunks[i] = []byte(c)
+	}
+	return chunks
+}
+
+func (w Workload) build(t Target, byteChunks [][]byte) string {
+	if w.Grow {
+		t.Grow(w.Size())
+	}
+	switch w.Kind {
+	case WriteBytes:
+		for _, p := range byteChunks {
+			t.Write(p)
+		}
+	case WriteString:
+		for _, c := range w.Chunks {
+			t.WriteString(c)
+		}
+	case WriteByte:
+		for _, c := range w.Chunks {
+			for i := 0; i < len(c); i++ {
+				t.WriteByte(c[i])
+			}
+		}
+	}
+	return t.String()
+}
+
+// Chunks returns n chunks of size bytes of printable text.
+func Chunks(n, size int) []string {
+	const text = "some bytes sdljlk jsklj3lkjlk djlkjw "
+	chunk := strings.Repeat(text, size/len(text)+1)[:size]
+	chunks := make([]string, n)
+	for i := range chunks {
+		chunks[i] = chunk
+	}
+	return chunks
+}
+
+// Standard are the workloads the builder package benchmarks itself with:
+// one or three short writes, with and without a Grow hint.
+var Standard = []Workload{
+	{Name: "1Write_NoGrow", Chunks: Chunks(1, 35)},
+	{Name: "3Write_NoGrow", Chunks: Chunks(3, 35)},
+	{Name: "3Write_Grow", Chunks: Chunks(3, 35), Grow: true},
+}
+
+var sink string
+
+// Run runs a sub-benchmark for each workload and, within it, for each of
+// Impls, reporting allocations and throughput.
+func Run(b *testing.B, workloads ...Workload) {
+	for _, w := range workloads {
+		b.Run(w.Name, func(b *testing.B) {
+			byteChunks := w.byteChunks()
+			for _, impl := range Impls {
+				b.Run(impl.Name, func(b *testing.B) {
+					b.ReportAllocs()
+					b.SetBytes(int64(w.Size()))
+					for i := 0; i < b.N; i++ {
+						sink = w.build(impl.New(), byteChunks)
+					}
+				})
+			}
+		})
+	}
+}
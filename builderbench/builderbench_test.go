@@ -0,0 +1,48 @@
+// Copyright 2023 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package builderbench_test
+
+import (
+	"strings"
+	"testing"
+
+	. "github.com/weiwenchen2022/builder/builderbench"
+)
+
+func TestBuild(t *testing.T) {
+	t.Parallel()
+
+	workloads := append([]Workload{
+		{Name: "Empty"},
+		{Name: "Bytes", Chunks: Chunks(10, 100), Kind: WriteByte, Grow: true},
+		{Name: "Strings", Chunks: []string{"a", "", "bc"}, Kind: WriteString},
+	}, Standard...)
+	for _, w := range workloads {
+		want := strings.Join(w.Chunks, "")
+		if w.Size() != len(want) {
+			t.Errorf("%s: Size = %d; want %d", w.Name, w.Size(), len(want))
+		}
+		for _, impl := range Impls {
+			if got := w.Build(impl.New()); got != want {
+				t.Errorf("%s with %s: got %q; want %q", w.Name, impl.Name, got, want)
+			}
+		}
+	}
+}
+
+func TestChunks(t *testing.T) {
+	t.Parallel()
+
+	for _, size := range []int{0, 1, 35, 100} {
+		chunks := Chunks(3, size)
+		if len(chunks) != 3 || len(chunks[0]) != size || chunks[1] != chunks[0] {
+			t.Errorf("Chunks(3, %d) = %q", size, chunks)
+		}
+	}
+}
+
+func BenchmarkStandard(b *testing.B) {
+	Run(b, Standard...)
+}
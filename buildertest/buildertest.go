@@ -0,0 +1,92 @@
+// Copyright 2023 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package buildertest implements generators of pseudo-random and adversarial
+// text, written to a builder.Builder, for fuzzing code that escapes or parses
+// builder output. Given a *rand.Rand created with the same seed, every
+// generator writes the same content on every run.
+package buildertest
+
+import (
+	"math/rand"
+	"unicode/utf8"
+
+	"github.com/weiwenchen2022/builder"
+)
+
+// WriteRandomASCII appends n pseudo-random ASCII bytes, including control
+// characters, to b.
+func WriteRandomASCII(b *builder.Builder, r *rand.Rand, n int) {
+	b.Grow(n)
+	for i := 0; i < n; i++ {
+		_ = b.WriteByte(byte(r.Intn(utf8.RuneSelf)))
+	}
+}
+
+// utf8Ranges are the ranges of code points WriteRandomUTF8 draws from,
+// indexed by the length of their encoding minus one. Three-byte encodings
+// span two ranges, either side of the surrogates.
+var utf8Ranges = [utf8.UTFMax][]struct{ lo, hi rune }{
+	{{0, utf8.RuneSelf - 1}},
+	{{0x80, 0x7FF}},
+	{{0x800, 0xD7FF}, {0xE000, 0xFFFF}},
+	{{0x10000, utf8.MaxRune}},
+}
+
+// WriteRandomUTF8 appends n pseudo-random code points, encoded as valid UTF-8,
+// to b. Each code point first gets an encoding length, each equally likely,
+// and is then drawn uniformly from the code points of that length, including
+// those just outside the surrogate range.
+func WriteRandomUTF8(b *builder.Builder, r *rand.Rand, n int) {
+	for i := 0; i < n; i++ {
+		ranges := utf8Ranges[r.Intn(len(utf8Ranges))]
+		var size int64
+		for _, rg := range ranges {
+			size += int64(rg.hi - rg.lo + 1)
+		}
+		k := r.Int63n(size)
+		for _, rg := range ranges {
+			if m := int64(rg.hi - rg.lo + 1); k >= m {
+				k -= m
+				continue
+			}
+			_, _ = b.WriteRune(rg.lo + rune(k))
+			break
+		}
+	}
+}
+
+// Adversarial holds the byte sequences WriteAdversarial draws from. They are
+// invalid UTF-8 or valid but troublesome text that escaping and parsing
+// layers commonly get wrong.
+var Adversarial = []string{
+	"\x00",             // NUL
+	"\x7f",             // DEL
+	"\xc0\xaf",         // overlong '/'
+	"\xe0\x80\xaf",     // overlong '/', three bytes
+	"\xf0\x80\x80\xaf", // overlong '/', four bytes
+	"\xed\xa0\x80",     // lone high surrogate U+D800
+	"\xed\xbf\xbf",     // lone low surrogate U+DFFF
+	"\xf4\x90\x80\x80", // beyond U+10FFFF
+	"\xc3",             // truncated two-byte sequence
+	"\xe2\x82",         // truncated three-byte sequence
+	"\x80",             // unexpected continuation byte
+	"\xfe", "\xff",     // never valid in UTF-8
+	"\ufeff",     // byte order mark
+	"\u2028",     // line separator
+	"\u2029",     // paragraph separator
+	"\u202e",     // right-to-left override
+	"\u200b",     // zero width space
+	"\ufffd",     // replacement character
+	"\U0010ffff", // largest code point
+	"\"", "'", "\\", "<", ">", "&", "\r\n", "\t",
+}
+
+// WriteAdversarial appends n sequences drawn pseudo-randomly from Adversarial
+// to b.
+func WriteAdversarial(b *builder.Builder, r *rand.Rand, n int) {
+	for i := 0; i < n; i++ {
+		_, _ = b.WriteString(Adversarial[r.Intn(len(Adversarial))])
+	}
+}
@@ -0,0 +1,82 @@
+// Copyright 2023 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package buildertest_test
+
+import (
+	"math/rand"
+	"testing"
+	"unicode/utf8"
+
+	"github.com/weiwenchen2022/builder"
+	. "github.com/weiwenchen2022/builder/buildertest"
+)
+
+func generate(seed int64, write func(*builder.Builder, *rand.Rand, int)) string {
+	var b builder.Builder
+	write(&b, rand.New(rand.NewSource(seed)), 1000)
+	return b.String()
+}
+
+func TestDeterministic(t *testing.T) {
+	t.Parallel()
+
+	for name, write := range map[string]func(*builder.Builder, *rand.Rand, int){
+		"ASCII":       WriteRandomASCII,
+		"UTF8":        WriteRandomUTF8,
+		"Adversarial": WriteAdversarial,
+	} {
+		if generate(1, write) != generate(1, write) {
+			t.Errorf("%s: same seed produced different content", name)
+		}
+		if generate(1, write) == generate(2, write) {
+			t.Errorf("%s: different seeds produced the same content", name)
+		}
+	}
+}
+
+func TestWriteRandomASCII(t *testing.T) {
+	t.Parallel()
+
+	s := generate(1, WriteRandomASCII)
+	if len(s) != 1000 {
+		t.Errorf("got %d bytes; want 1000", len(s))
+	}
+	for i := 0; i < len(s); i++ {
+		if s[i] >= utf8.RuneSelf {
+			t.Fatalf("byte %d is %#x; want ASCII", i, s[i])
+		}
+	}
+}
+
+func TestWriteRandomUTF8(t *testing.T) {
+	t.Parallel()
+
+	s := generate(1, WriteRandomUTF8)
+	if !utf8.ValidString(s) {
+		t.Error("content is not valid UTF-8")
+	}
+	if n := utf8.RuneCountInString(s); n != 1000 {
+		t.Errorf("got %d runes; want 1000", n)
+	}
+
+	var widths [utf8.UTFMax + 1]int
+	for _, r := range s {
+		widths[utf8.RuneLen(r)]++
+	}
+	for w := 1; w <= utf8.UTFMax; w++ {
+		// Each length should get about a quarter of the 1000 runes.
+		if widths[w] < 200 || widths[w] > 300 {
+			t.Errorf("%d-byte encodings: got %d of 1000; want about 250", w, widths[w])
+		}
+	}
+}
+
+func TestWriteAdversarial(t *testing.T) {
+	t.Parallel()
+
+	if s := generate(1, WriteAdversarial); utf8.ValidString(s) {
+		t.Error("adversarial content is valid UTF-8")
+	}
+}
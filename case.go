@@ -0,0 +1,112 @@
+// Copyright 2023 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package builder
+
+import (
+	"unicode"
+	"unicode/utf8"
+)
+
+// WriteUpper appends s with all Unicode letters mapped to their upper case,
+// as strings.ToUpper does, to b's buffer, without the intermediate string.
+// It returns the length of written and a nil error.
+func (b *Builder) WriteUpper(s string) (int, error) {
+	b.copyCheck()
+	n := len(b.buf)
+	b.buf = appendMapped(b.buf, s, unicode.ToUpper)
+	return b.commit(n), nil
+}
+
+// WriteLower appends s with all Unicode letters mapped to their lower case,
+// as strings.ToLower does, to b's buffer, without the intermediate string.
+// It returns the length of written and a nil error.
+func (b *Builder) WriteLower(s string) (int, error) {
+	b.copyCheck()
+	n := len(b.buf)
+	b.buf = appendMapped(b.buf, s, unicode.ToLower)
+	return b.commit(n), nil
+}
+
+// WriteUpperASCII appends s with the ASCII letters a to z mapped to A to Z to
+// b's buffer. All other bytes, including those of non-ASCII characters and
+// invalid UTF-8, are copied unchanged.
+// It returns the length of written and a nil error.
+func (b *Builder) WriteUpperASCII(s string) (int, error) {
+	b.copyCheck()
+	n := len(b.buf)
+	b.buf = appendASCIICase(b.buf, s, 'a', 'z')
+	return b.commit(n), nil
+}
+
+// WriteLowerASCII appends s with the ASCII letters A to Z mapped to a to z to
+// b's buffer. All other bytes, including those of non-ASCII characters and
+// invalid UTF-8, are copied unchanged.
+// It returns the length of written and a nil error.
+func (b *Builder) WriteLowerASCII(s string) (int, error) {
+	b.copyCheck()
+	n := len(b.buf)
+	b.buf = appendASCIICase(b.buf, s, 'A', 'Z')
+	return b.commit(n), nil
+}
+
+// WriteTitle appends s to b's buffer with the first letter of each word mapped
+// to title case and its other letters to lower case, so "hello wORLD" becomes
+// "Hello World". Words are runs of letters, marks, digits and apostrophes,
+// so "don't" becomes "Don't". Invalid UTF-8 is replaced by U+FFFD.
+// It returns the length of written and a nil error.
+func (b *Builder) WriteTitle(s string) (int, error) {
+	b.copyCheck()
+	n := len(b.buf)
+	inWord := false
+	for _, r := range s {
+		switch {
+		case !isWordRune(r):
+			inWord = false
+		case inWord:
+			r = unicode.ToLower(r)
+		case unicode.IsLetter(r):
+			r = unicode.ToTitle(r)
+			inWord = true
+		default:
+			inWord = true
+		}
+		b.buf = utf8.AppendRune(b.buf, r)
+	}
+	return b.commit(n), nil
+}
+
+// isWordRune reports whether r is part of a word for WriteTitle.
+func isWordRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsMark(r) || unicode.IsDigit(r) || r == '\'' || r == '\u2019'
+}
+
+// appendMapped appends s with every rune mapped by mapping, and invalid UTF-8
+// replaced by U+FFFD as strings.Map does, to dst.
+func appendMapped(dst []byte, s string, mapping func(rune) rune) []byte {
+	for i := 0; i < len(s); {
+		if c := s[i]; c < utf8.RuneSelf {
+			dst = append(dst, byte(mapping(rune(c))))
+			i++
+			continue
+		}
+		r, size := utf8.DecodeRuneInString(s[i:])
+		dst = utf8.AppendRune(dst, mapping(r))
+		i += size
+	}
+	return dst
+}
+
+// appendASCIICase appends s to dst with the bytes in [lo, hi], the letters of
+// one ASCII case, flipped to the other case.
+func appendASCIICase(dst []byte, s string, lo, hi byte) []byte {
+	n := len(dst)
+	dst = append(dst, s...)
+	for i, c := range dst[n:] {
+		if lo <= c && c <= hi {
+			dst[n+i] = c ^ 0x20
+		}
+	}
+	return dst
+}
@@ -0,0 +1,112 @@
+// Copyright 2023 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package builder_test
+
+import (
+	"strings"
+	"testing"
+
+	. "github.com/weiwenchen2022/builder"
+	"github.com/weiwenchen2022/builder/buildertest"
+)
+
+var caseInputs = []string{
+	"", "hello, World", "été ÉTÉ", "straße", "ǆ Ǆ ǅ",
+	"İstanbul ı", "ΣΟΦΟΣ", "bad\xffutf8\xc3", "ASCII only 123",
+}
+
+func TestBuilderWriteUpperLower(t *testing.T) {
+	t.Parallel()
+
+	inputs := append(caseInputs, buildertest.Adversarial...)
+	for _, s := range inputs {
+		var b Builder
+		want := strings.ToUpper(s)
+		n, err := b.WriteUpper(s)
+		if err != nil || n != len(want) {
+			t.Errorf("WriteUpper(%q): got %d,%v; want %d,nil", s, n, err, len(want))
+		}
+		check(t, &b, want)
+
+		b.Reset()
+		want = strings.ToLower(s)
+		n, err = b.WriteLower(s)
+		if err != nil || n != len(want) {
+			t.Errorf("WriteLower(%q): got %d,%v; want %d,nil", s, n, err, len(want))
+		}
+		check(t, &b, want)
+	}
+}
+
+func TestBuilderWriteUpperLowerASCII(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		in, upper, lower string
+	}{
+		{"", "", ""},
+		{"Content-Type", "CONTENT-TYPE", "content-type"},
+		{"x-Request-ID_09@[`{", "X-REQUEST-ID_09@[`{", "x-request-id_09@[`{"},
+		{"\u00e9t\u00e9 \u00c9", "\u00e9T\u00e9 \u00c9", "\u00e9t\u00e9 \u00c9"},
+		{"bad\xffutf8\xc3", "BAD\xffUTF8\xc3", "bad\xffutf8\xc3"},
+	}
+	for _, tt := range tests {
+		var b Builder
+		n, err := b.WriteUpperASCII(tt.in)
+		if err != nil || n != len(tt.upper) {
+			t.Errorf("WriteUpperASCII(%q): got %d,%v; want %d,nil", tt.in, n, err, len(tt.upper))
+		}
+		check(t, &b, tt.upper)
+
+		b.Reset()
+		n, err = b.WriteLowerASCII(tt.in)
+		if err != nil || n != len(tt.lower) {
+			t.Errorf("WriteLowerASCII(%q): got %d,%v; want %d,nil", tt.in, n, err, len(tt.lower))
+		}
+		check(t, &b, tt.lower)
+	}
+}
+
+func TestBuilderWriteTitle(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		s    string
+		want string
+	}{
+		{"", ""},
+		{"hello wORLD", "Hello World"},
+		{"don't stop", "Don't Stop"},
+		{"rock'n'roll", "Rock'n'roll"},
+		{"hello-world_foo.bar", "Hello-World_Foo.Bar"},
+		{"3rd place", "3rd Place"},
+		{"été ÉTÉ", "Été Été"},
+		{"ǆemal", "ǅemal"}, // title case differs from upper case
+		{"x\xffy", "X\ufffdY"},
+	}
+	for _, tt := range tests {
+		var b Builder
+		n, err := b.WriteTitle(tt.s)
+		if err != nil || n != len(tt.want) {
+			t.Errorf("WriteTitle(%q): got %d,%v; want %d,nil", tt.s, n, err, len(tt.want))
+		}
+		check(t, &b, tt.want)
+	}
+}
+
+func TestBuilderWriteUpperAllocs(t *testing.T) {
+	var b Builder
+	b.Grow(1024)
+	allocs := testing.AllocsPerRun(100, func() {
+		b.WriteUpper("content-type: été")
+		b.WriteLower("Content-Type")
+		b.WriteTitle("content type")
+		b.WriteUpperASCII("content-type")
+		b.WriteLowerASCII("Content-Type")
+	})
+	if allocs != 0 {
+		t.Errorf("WriteUpper allocs = %v; want 0", allocs)
+	}
+}
@@ -0,0 +1,155 @@
+// Copyright 2023 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package builder
+
+import (
+	"encoding/binary"
+	"math"
+)
+
+// CBOR major types (RFC 8949, section 3.1).
+const (
+	cborUint   = 0 << 5
+	cborNegInt = 1 << 5
+	cborBytes  = 2 << 5
+	cborText   = 3 << 5
+	cborArray  = 4 << 5
+	cborMap    = 5 << 5
+	cborTag    = 6 << 5
+)
+
+// A CBORWriter appends CBOR-encoded scalars and container headers to a
+// Builder, always using the shortest argument encoding of RFC 8949.
+// The elements of an array or map are written by further calls after its
+// header, so only definite-length containers are produced.
+type CBORWriter struct {
+	b *Builder
+}
+
+// CBOR returns a CBORWriter that writes to b.
+func (b *Builder) CBOR() *CBORWriter {
+	return &CBORWriter{b: b}
+}
+
+// Null writes null.
+func (w *CBORWriter) Null() {
+	b := w.b
+	b.copyCheck()
+	n := len(b.buf)
+	b.buf = append(b.buf, 0xf6)
+	b.commit(n)
+}
+
+// Bool writes v.
+func (w *CBORWriter) Bool(v bool) {
+	b := w.b
+	b.copyCheck()
+	n := len(b.buf)
+	if v {
+		b.buf = append(b.buf, 0xf5)
+	} else {
+		b.buf = append(b.buf, 0xf4)
+	}
+	b.commit(n)
+}
+
+// Int writes i.
+func (w *CBORWriter) Int(i int64) {
+	if i >= 0 {
+		w.head(cborUint, uint64(i))
+	} else {
+		w.head(cborNegInt, uint64(^i))
+	}
+}
+
+// Uint writes u.
+func (w *CBORWriter) Uint(u uint64) {
+	w.head(cborUint, u)
+}
+
+// Float32 writes f as a single-precision float.
+func (w *CBORWriter) Float32(f float32) {
+	b := w.b
+	b.copyCheck()
+	n := len(b.buf)
+	b.buf = append(b.buf, 0xfa)
+	b.buf = binary.BigEndian.AppendUint32(b.buf, math.Float32bits(f))
+	b.commit(n)
+}
+
+// Float64 writes f as a double-precision float.
+func (w *CBORWriter) Float64(f float64) {
+	b := w.b
+	b.copyCheck()
+	n := len(b.buf)
+	b.buf = append(b.buf, 0xfb)
+	b.buf = binary.BigEndian.AppendUint64(b.buf, math.Float64bits(f))
+	b.commit(n)
+}
+
+// String writes s as a text string. It does not check that s is valid UTF-8.
+func (w *CBORWriter) String(s string) {
+	b := w.b
+	b.copyCheck()
+	n := len(b.buf)
+	b.buf = appendCBORHead(b.buf, cborText, uint64(len(s)))
+	b.buf = append(b.buf, s...)
+	b.commit(n)
+}
+
+// Bytes writes p as a byte string.
+func (w *CBORWriter) Bytes(p []byte) {
+	b := w.b
+	b.copyCheck()
+	n := len(b.buf)
+	b.buf = appendCBORHead(b.buf, cborBytes, uint64(len(p)))
+	b.buf = append(b.buf, p...)
+	b.commit(n)
+}
+
+// ArrayHeader writes the header of an array of length elements.
+// It panics if length is negative.
+func (w *CBORWriter) ArrayHeader(length int) {
+	if length < 0 {
+		panic("builder.CBORWriter.ArrayHeader: negative length")
+	}
+	w.head(cborArray, uint64(length))
+}
+
+// MapHeader writes the header of a map of length key-value pairs.
+// It panics if length is negative.
+func (w *CBORWriter) MapHeader(length int) {
+	if length < 0 {
+		panic("builder.CBORWriter.MapHeader: negative length")
+	}
+	w.head(cborMap, uint64(length))
+}
+
+// Tag writes a tag number; the tagged data item is written by the next call.
+func (w *CBORWriter) Tag(number uint64) {
+	w.head(cborTag, number)
+}
+
+func (w *CBORWriter) head(major byte, arg uint64) {
+	b := w.b
+	b.copyCheck()
+	n := len(b.buf)
+	b.buf = appendCBORHead(b.buf, major, arg)
+	b.commit(n)
+}
+
+func appendCBORHead(dst []byte, major byte, arg uint64) []byte {
+	switch {
+	case arg < 24:
+		return append(dst, major|byte(arg))
+	case arg <= math.MaxUint8:
+		return append(dst, major|24, byte(arg))
+	case arg <= math.MaxUint16:
+		return binary.BigEndian.AppendUint16(append(dst, major|25), uint16(arg))
+	case arg <= math.MaxUint32:
+		return binary.BigEndian.AppendUint32(append(dst, major|26), uint32(arg))
+	}
+	return binary.BigEndian.AppendUint64(append(dst, major|27), arg)
+}
@@ -0,0 +1,70 @@
+// Copyright 2023 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package builder_test
+
+import (
+	"encoding/hex"
+	"math"
+	"testing"
+
+	. "github.com/weiwenchen2022/builder"
+)
+
+// Most expectations are from RFC 8949, Appendix A.
+func TestCBORWriter(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name  string
+		write func(w *CBORWriter)
+		want  string // hex
+	}{
+		{"null", func(w *CBORWriter) { w.Null() }, "f6"},
+		{"false", func(w *CBORWriter) { w.Bool(false) }, "f4"},
+		{"true", func(w *CBORWriter) { w.Bool(true) }, "f5"},
+		{"0", func(w *CBORWriter) { w.Int(0) }, "00"},
+		{"23", func(w *CBORWriter) { w.Int(23) }, "17"},
+		{"24", func(w *CBORWriter) { w.Int(24) }, "1818"},
+		{"1000", func(w *CBORWriter) { w.Uint(1000) }, "1903e8"},
+		{"1000000", func(w *CBORWriter) { w.Uint(1000000) }, "1a000f4240"},
+		{"MaxUint64", func(w *CBORWriter) { w.Uint(math.MaxUint64) }, "1bffffffffffffffff"},
+		{"-1", func(w *CBORWriter) { w.Int(-1) }, "20"},
+		{"-100", func(w *CBORWriter) { w.Int(-100) }, "3863"},
+		{"-1000", func(w *CBORWriter) { w.Int(-1000) }, "3903e7"},
+		{"MinInt64", func(w *CBORWriter) { w.Int(math.MinInt64) }, "3b7fffffffffffffff"},
+		{"float32", func(w *CBORWriter) { w.Float32(100000.0) }, "fa47c35000"},
+		{"float64", func(w *CBORWriter) { w.Float64(1.1) }, "fb3ff199999999999a"},
+		{"text", func(w *CBORWriter) { w.String("IETF") }, "6449455446"},
+		{"utf8", func(w *CBORWriter) { w.String("ü") }, "62c3bc"},
+		{"bytes", func(w *CBORWriter) { w.Bytes([]byte{1, 2, 3, 4}) }, "4401020304"},
+		{"array", func(w *CBORWriter) { w.ArrayHeader(25) }, "9819"},
+		{"map", func(w *CBORWriter) { w.MapHeader(2) }, "a2"},
+		{"tag", func(w *CBORWriter) { w.Tag(1) }, "c1"},
+	}
+
+	for _, tt := range tests {
+		var b Builder
+		tt.write(b.CBOR())
+		if got := hex.EncodeToString([]byte(b.String())); tt.want != got {
+			t.Errorf("%s: got %s; want %s", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestCBORWriterEnvelope(t *testing.T) {
+	t.Parallel()
+
+	// {"a": 1, "b": [2, 3]}
+	var b Builder
+	w := b.CBOR()
+	w.MapHeader(2)
+	w.String("a")
+	w.Int(1)
+	w.String("b")
+	w.ArrayHeader(2)
+	w.Int(2)
+	w.Int(3)
+	check(t, &b, "\xa2\x61a\x01\x61b\x82\x02\x03")
+}
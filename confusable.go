@@ -0,0 +1,207 @@
+// Copyright 2023 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package builder
+
+import (
+	"errors"
+	"unicode"
+	"unicode/utf8"
+)
+
+// This file implements the mixed-script detection of Unicode Technical
+// Standard #39 at its "highly restrictive" level: an identifier may use a
+// single script, or Latin together with Han and one of the Japanese, Chinese
+// or Korean companion scripts. Characters of the Common and Inherited
+// scripts, such as digits and combining marks, are compatible with any
+// script. Whole-script confusables, such as a name spelled entirely in
+// Cyrillic letters that look Latin, are not detected.
+//
+// Identifiers are maximal runs of letters, marks and digits, so each label
+// of a domain name or each word of a username is checked separately.
+
+// frequentScripts are tried before the full unicode.Scripts table.
+var frequentScripts = [...]string{"Latin", "Cyrillic", "Greek", "Han", "Arabic", "Hebrew", "Hiragana", "Katakana", "Hangul", "Armenian", "Cherokee"}
+
+// scriptOf returns the name of the script of r, or "" if r belongs to the
+// Common or Inherited script and so does not affect mixed-script detection.
+func scriptOf(r rune) string {
+	if r < utf8.RuneSelf {
+		if 'a' <= r && r <= 'z' || 'A' <= r && r <= 'Z' {
+			return "Latin"
+		}
+		return ""
+	}
+	if unicode.In(r, unicode.Common, unicode.Inherited) {
+		return ""
+	}
+	for _, name := range frequentScripts {
+		if unicode.Is(unicode.Scripts[name], r) {
+			return name
+		}
+	}
+	for name, table := range unicode.Scripts {
+		if unicode.Is(table, r) {
+			return name
+		}
+	}
+	return "Unknown"
+}
+
+func isIdentRune(r rune) bool {
+	if r < utf8.RuneSelf {
+		return 'a' <= r && r <= 'z' || 'A' <= r && r <= 'Z' || '0' <= r && r <= '9'
+	}
+	return unicode.In(r, unicode.L, unicode.M, unicode.Nd)
+}
+
+// scriptsAllowed reports whether an identifier may combine the given scripts.
+func scriptsAllowed(scripts []string) bool {
+	if len(scripts) <= 1 {
+		return true
+	}
+	for _, companion := range [...]string{"Hiragana", "Bopomofo", "Hangul"} {
+		ok := true
+		for _, s := range scripts {
+			if s != "Latin" && s != "Han" && s != companion && (companion != "Hiragana" || s != "Katakana") {
+				ok = false
+				break
+			}
+		}
+		if ok {
+			return true
+		}
+	}
+	return false
+}
+
+// addScript adds the script name to scripts unless it is empty or present.
+func addScript(scripts []string, name string) []string {
+	if name == "" {
+		return scripts
+	}
+	for _, s := range scripts {
+		if s == name {
+			return scripts
+		}
+	}
+	return append(scripts, name)
+}
+
+// MixedScript returns a Validator that reports identifiers mixing scripts in
+// a way Unicode Technical Standard #39 considers suspicious, such as a Latin
+// word containing a Cyrillic letter, which may be a spoofing attempt.
+// The reported offset is that of the start of the identifier.
+func MixedScript() Validator {
+	return new(mixedScriptValidator)
+}
+
+type mixedScriptValidator struct {
+	pos     int      // number of bytes validated
+	pending []byte   // incomplete encoding at the end of the previous write
+	inIdent bool     // the last rune belonged to an identifier
+	start   int      // offset of the current identifier
+	scripts []string // scripts of the current identifier
+}
+
+func (v *mixedScriptValidator) Validate(p []byte) (int, error) {
+	base := v.pos
+	v.pos += len(p)
+
+	buf, off := p, base
+	if len(v.pending) > 0 {
+		off -= len(v.pending)
+		buf = append(v.pending, p...)
+		v.pending = v.pending[:0]
+	}
+
+	for i := 0; i < len(buf); {
+		if buf[i] >= utf8.RuneSelf && !utf8.FullRune(buf[i:]) {
+			v.pending = append(v.pending, buf[i:]...)
+			break
+		}
+		r, size := utf8.DecodeRune(buf[i:])
+		if !isIdentRune(r) {
+			v.inIdent = false
+			i += size
+			continue
+		}
+		if !v.inIdent {
+			v.inIdent = true
+			v.start = off + i
+			v.scripts = v.scripts[:0]
+		}
+		v.scripts = addScript(v.scripts, scriptOf(r))
+		if !scriptsAllowed(v.scripts) {
+			return v.start - base, errors.New("identifier mixes " + v.scripts[0] + " and " + v.scripts[len(v.scripts)-1] + " scripts")
+		}
+		i += size
+	}
+	return 0, nil
+}
+
+// WriteMixedScriptEscaped appends s to b's buffer, escaping the characters
+// that make an identifier in s mix scripts suspiciously, as detected by
+// MixedScript. Within such an identifier, characters of a script other than
+// that of its first letter, and not allowed alongside it, are written as Go
+// escapes: "paypal" spelled with a Cyrillic U+0430 in place of its first "a"
+// is written as "p\u0430ypal". Other text is written unchanged.
+// It returns the length of written and a nil error.
+func (b *Builder) WriteMixedScriptEscaped(s string) (int, error) {
+	b.copyCheck()
+	n := len(b.buf)
+	var scratch [4]string
+	for i := 0; i < len(s); {
+		j := i
+		scripts := scratch[:0]
+		for j < len(s) {
+			r, size := utf8.DecodeRuneInString(s[j:])
+			if !isIdentRune(r) {
+				break
+			}
+			scripts = addScript(scripts, scriptOf(r))
+			j += size
+		}
+		if j == i {
+			_, size := utf8.DecodeRuneInString(s[i:])
+			b.buf = append(b.buf, s[i:i+size]...)
+			i += size
+			continue
+		}
+
+		ident := s[i:j]
+		i = j
+		if scriptsAllowed(scripts) {
+			b.buf = append(b.buf, ident...)
+			continue
+		}
+		primary := scripts[0]
+		for _, r := range ident {
+			if sc := scriptOf(r); sc == "" || sc == primary || scriptsAllowed([]string{primary, sc}) {
+				b.buf = utf8.AppendRune(b.buf, r)
+			} else {
+				b.buf = appendRuneEscape(b.buf, r)
+			}
+		}
+	}
+	return b.commit(n), nil
+}
+
+// appendRuneEscape appends r as a Go escape sequence, \uXXXX or \UXXXXXXXX.
+func appendRuneEscape(dst []byte, r rune) []byte {
+	const hex = "0123456789abcdef"
+
+	if r <= 0xFFFF {
+		dst = append(dst, '\\', 'u')
+		for shift := 12; shift >= 0; shift -= 4 {
+			dst = append(dst, hex[r>>uint(shift)&0xF])
+		}
+		return dst
+	}
+	dst = append(dst, '\\', 'U')
+	for shift := 28; shift >= 0; shift -= 4 {
+		dst = append(dst, hex[r>>uint(shift)&0xF])
+	}
+	return dst
+}
@@ -0,0 +1,78 @@
+// Copyright 2023 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package builder_test
+
+import (
+	"errors"
+	"testing"
+
+	. "github.com/weiwenchen2022/builder"
+)
+
+const cyrillicA = "\u0430"
+
+func TestMixedScript(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		writes []string
+		offset int // -1 if valid
+	}{
+		{[]string{"alert: user paypal logged in"}, -1},
+		{[]string{"user p" + cyrillicA + "ypal logged in"}, 5},
+		{[]string{"\u043f\u0440\u0438\u0432\u0435\u0442 world"}, -1},   // Cyrillic word, Latin word
+		{[]string{"Tokyo東京とカタカナ"}, -1},                                 // Latin, Han, Hiragana, Katakana
+		{[]string{"Seoul서울"}, -1},                                      // Latin and Hangul
+		{[]string{"αβc"}, 0},                                           // Greek and Latin
+		{[]string{"cafe\u0301 123"}, -1},                               // combining mark and digits
+		{[]string{"ok ", "pay", "p" + cyrillicA + "l"}, 3},             // identifier spans writes
+		{[]string{"x p", cyrillicA[:1], cyrillicA[1:] + "y"}, 2},       // rune split across writes
+		{[]string{"example.com mail.ex" + cyrillicA + "mple.com"}, 17}, // per label
+	}
+
+	for _, tt := range tests {
+		var b Builder
+		b.AddValidator(MixedScript())
+		for _, w := range tt.writes {
+			b.WriteString(w)
+		}
+		err := b.Err()
+		if tt.offset < 0 {
+			if err != nil {
+				t.Errorf("%q: unexpected error %v", tt.writes, err)
+			}
+			continue
+		}
+		var verr *ValidationError
+		if !errors.As(err, &verr) || verr.Offset != tt.offset {
+			t.Errorf("%q: got error %v; want offset %d", tt.writes, err, tt.offset)
+		}
+	}
+}
+
+func TestBuilderWriteMixedScriptEscaped(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		s, want string
+	}{
+		{"", ""},
+		{"plain paypal.com", "plain paypal.com"},
+		{"p" + cyrillicA + "ypal.com", `p\u0430ypal.com`},
+		{"\u043f\u0440\u0438\u0432\u0435\u0442", "\u043f\u0440\u0438\u0432\u0435\u0442"},
+		{"\u0441at", "\u0441" + `\u0061\u0074`},
+		{"Tokyo東京", "Tokyo東京"},
+		{"bad\xffbytes", "bad\xffbytes"},
+	}
+
+	for _, tt := range tests {
+		var b Builder
+		n, err := b.WriteMixedScriptEscaped(tt.s)
+		if err != nil || len(tt.want) != n {
+			t.Errorf("WriteMixedScriptEscaped(%q): got %d,%v; want %d,nil", tt.s, n, err, len(tt.want))
+		}
+		check(t, &b, tt.want)
+	}
+}
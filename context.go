@@ -0,0 +1,36 @@
+// Copyright 2023 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package builder
+
+import "context"
+
+type contextKey struct{}
+
+// WithBuilder returns a copy of ctx carrying a scratch Builder, which the
+// layers handling a request, such as middleware, can share through
+// FromContext instead of each allocating its own.
+func WithBuilder(ctx context.Context) context.Context {
+	return context.WithValue(ctx, contextKey{}, new(Builder))
+}
+
+// FromContext returns the scratch Builder carried by ctx, emptied and with
+// its write modes disabled, or a new Builder if ctx carries none.
+//
+// The Builder keeps its buffer when emptied, so that its capacity is reused
+// by every layer. As for a Builder created by NewFixed, a string returned by
+// its String method is therefore only valid until the next call to
+// FromContext; callers that keep the result must copy it, as with
+// strings.Clone. The Builder is not safe for concurrent use, and a caller
+// must be done with it before passing ctx to code that may call FromContext.
+func FromContext(ctx context.Context) *Builder {
+	b, ok := ctx.Value(contextKey{}).(*Builder)
+	if !ok {
+		return new(Builder)
+	}
+	b.copyCheck()
+	b.buf = b.buf[:0]
+	b.mode = nil
+	return b
+}
@@ -0,0 +1,65 @@
+// Copyright 2023 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package builder_test
+
+import (
+	"context"
+	"testing"
+
+	. "github.com/weiwenchen2022/builder"
+)
+
+func TestFromContext(t *testing.T) {
+	t.Parallel()
+
+	b := FromContext(context.Background())
+	if b == nil || b.Len() != 0 {
+		t.Fatalf("FromContext without a builder: got %v; want an empty Builder", b)
+	}
+
+	ctx := WithBuilder(context.Background())
+	b1 := FromContext(ctx)
+	b1.Grow(64)
+	b1.SetSep(",")
+	b1.WriteString("first")
+	b1.WriteString("layer")
+	check(t, b1, "first,layer")
+
+	b2 := FromContext(ctx)
+	if b2 != b1 {
+		t.Error("FromContext returned a different Builder for the same context")
+	}
+	check(t, b2, "")
+	b2.WriteString("second")
+	b2.WriteString("layer")
+	check(t, b2, "secondlayer") // write modes were disabled
+
+	// The capacity is kept across any number of reuses.
+	for i := 0; i < 10; i++ {
+		b := FromContext(ctx)
+		b.WriteString("reused layer")
+		if b.Cap() != 64 {
+			t.Fatalf("Cap after %d reuses: got %d; want 64", i+1, b.Cap())
+		}
+	}
+
+	if FromContext(WithBuilder(ctx)) == b1 {
+		t.Error("WithBuilder did not attach a new Builder")
+	}
+}
+
+func TestFromContextAllocs(t *testing.T) {
+	ctx := WithBuilder(context.Background())
+	FromContext(ctx).Grow(4096)
+	allocs := testing.AllocsPerRun(100, func() {
+		b := FromContext(ctx)
+		b.WriteString("request ")
+		b.WriteInt(42, 10)
+		_ = b.String()
+	})
+	if allocs != 0 {
+		t.Errorf("FromContext allocs = %v; want 0", allocs)
+	}
+}
@@ -0,0 +1,124 @@
+// Copyright 2023 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package builder
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// SetCSVDelimiter sets the field delimiter used by WriteCSVField and
+// WriteCSVRecord, which is ',' by default.
+// It panics if comma is a quote, a carriage return or line feed,
+// or not a valid rune, as encoding/csv would reject it.
+func (b *Builder) SetCSVDelimiter(comma rune) {
+	if comma == '"' || comma == '\r' || comma == '\n' || !utf8.ValidRune(comma) || comma == utf8.RuneError {
+		panic("builder.Builder.SetCSVDelimiter: invalid delimiter")
+	}
+	if comma == ',' && b.mode == nil {
+		return
+	}
+	if b.mode == nil {
+		b.mode = new(mode)
+	}
+	b.mode.csvComma = comma
+}
+
+func (b *Builder) csvComma() rune {
+	if b.mode == nil || b.mode.csvComma == 0 {
+		return ','
+	}
+	return b.mode.csvComma
+}
+
+// WriteCSVField appends s to b's buffer as a single CSV field, quoting it as
+// RFC 4180 and encoding/csv do when it contains the delimiter, a quote or a
+// line break, or begins with a space. Quotes within the field are doubled.
+// If lines end in "\r\n" (see SetLineEnding), line breaks within a quoted
+// field are written as "\r\n" too, as csv.Writer does with UseCRLF.
+// It returns the length of written and a nil error.
+func (b *Builder) WriteCSVField(s string) (int, error) {
+	b.copyCheck()
+	n := len(b.buf)
+	b.buf = appendCSVField(b.buf, s, b.csvComma(), b.eol() == "\r\n")
+	return b.commit(n), nil
+}
+
+// WriteCSVRecord appends fields to b's buffer as a CSV record: the fields,
+// quoted as by WriteCSVField and separated by the delimiter, followed by a
+// line ending (see SetLineEnding). The output matches that of a csv.Writer
+// with the same delimiter and line ending.
+// It returns the length of written and a nil error.
+func (b *Builder) WriteCSVRecord(fields ...string) (int, error) {
+	comma, crlf := b.csvComma(), b.eol() == "\r\n"
+
+	b.copyCheck()
+	n := len(b.buf)
+	for i, f := range fields {
+		if i > 0 {
+			b.buf = utf8.AppendRune(b.buf, comma)
+		}
+		b.buf = appendCSVField(b.buf, f, comma, crlf)
+	}
+	b.buf = append(b.buf, b.eol()...)
+	return b.commit(n), nil
+}
+
+// appendCSVField appends s to dst as a CSV field. If crlf is set, line
+// breaks within a quoted field are written as "\r\n", as csv.Writer does with
+// UseCRLF: a carriage return is dropped and a line feed is preceded by one.
+func appendCSVField(dst []byte, s string, comma rune, crlf bool) []byte {
+	if !csvNeedsQuotes(s, comma) {
+		return append(dst, s...)
+	}
+
+	special := `"`
+	if crlf {
+		special = "\"\r\n"
+	}
+	dst = append(dst, '"')
+	for {
+		i := strings.IndexAny(s, special)
+		if i < 0 {
+			break
+		}
+		dst = append(dst, s[:i]...)
+		switch s[i] {
+		case '"':
+			dst = append(dst, '"', '"')
+		case '\n':
+			dst = append(dst, '\r', '\n')
+		}
+		s = s[i+1:]
+	}
+	dst = append(dst, s...)
+	return append(dst, '"')
+}
+
+// csvNeedsQuotes reports whether s must be quoted, using the same rules as
+// encoding/csv. A lone `\.` is quoted because it ends data in Postgres.
+func csvNeedsQuotes(s string, comma rune) bool {
+	if s == "" {
+		return false
+	}
+	if s == `\.` {
+		return true
+	}
+
+	if comma < utf8.RuneSelf {
+		for i := csvSafePrefix(s, byte(comma)); i < len(s); i++ {
+			c := s[i]
+			if c == '\n' || c == '\r' || c == '"' || c == byte(comma) {
+				return true
+			}
+		}
+	} else if strings.ContainsRune(s, comma) || strings.ContainsAny(s, "\"\r\n") {
+		return true
+	}
+
+	r, _ := utf8.DecodeRuneInString(s)
+	return unicode.IsSpace(r)
+}
@@ -0,0 +1,96 @@
+// Copyright 2023 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package builder_test
+
+import (
+	"encoding/csv"
+	"strings"
+	"testing"
+
+	. "github.com/weiwenchen2022/builder"
+	"github.com/weiwenchen2022/builder/buildertest"
+)
+
+var csvRecords = [][]string{
+	{"a", "b", "c"},
+	{""},
+	{"", ""},
+	{"with,comma", `with "quote"`, "multi\nline", "cr\rlf"},
+	{"a\nb", "c"},
+	{"crlf\r\ninside", "\r\n", "\n\n", "\r\r\"x\"\n"},
+	{" leading space", "\tleading tab", "trailing space "},
+	{`\.`, `a\.`},
+	{"semi;colon", "tab\there", "pipe|x", "é§"},
+	buildertest.Adversarial,
+}
+
+func TestBuilderWriteCSVRecord(t *testing.T) {
+	t.Parallel()
+
+	for _, comma := range []rune{',', ';', '\t', '|', '§'} {
+		for _, crlf := range []bool{false, true} {
+			var want strings.Builder
+			w := csv.NewWriter(&want)
+			w.Comma = comma
+			w.UseCRLF = crlf
+
+			var b Builder
+			b.SetCSVDelimiter(comma)
+			if crlf {
+				b.SetLineEnding(CRLF)
+			}
+			for _, rec := range csvRecords {
+				w.Write(rec)
+				b.WriteCSVRecord(rec...)
+			}
+			w.Flush()
+			if err := w.Error(); err != nil {
+				t.Fatal(err)
+			}
+			if want.String() != b.String() {
+				t.Errorf("comma %q, crlf %t:\ngot  %q\nwant %q", comma, crlf, b.String(), want.String())
+			}
+		}
+	}
+}
+
+func TestBuilderWriteCSVField(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		s, want string
+	}{
+		{"", ""},
+		{"plain", "plain"},
+		{"a,b", `"a,b"`},
+		{`say "hi"`, `"say ""hi"""`},
+		{" x", `" x"`},
+	}
+
+	for _, tt := range tests {
+		var b Builder
+		n, err := b.WriteCSVField(tt.s)
+		if err != nil || len(tt.want) != n {
+			t.Errorf("WriteCSVField(%q): got %d,%v; want %d,nil", tt.s, n, err, len(tt.want))
+		}
+		check(t, &b, tt.want)
+	}
+}
+
+func TestBuilderSetCSVDelimiterPanics(t *testing.T) {
+	t.Parallel()
+
+	for _, comma := range []rune{'"', '\n', '\r', -1, 0xFFFD} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("SetCSVDelimiter(%q): did not panic", comma)
+				}
+			}()
+			var b Builder
+			b.SetCSVDelimiter(comma)
+		}()
+	}
+}
@@ -0,0 +1,220 @@
+// Copyright 2023 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package builder
+
+import (
+	"errors"
+	"math"
+	"strconv"
+	"time"
+	"unicode/utf8"
+	"unsafe"
+)
+
+// A CSVKind selects how the values of a CSVColumn are written.
+type CSVKind int
+
+const (
+	CSVString  CSVKind = iota // a string, []byte or value with a String method
+	CSVInt                    // any integer type, in decimal
+	CSVFloat                  // float32 or float64, in 'f' format with Scale digits
+	CSVDecimal                // an integer unscaled value, as by WriteDecimal with Scale
+	CSVTime                   // a time.Time, formatted with Layout
+	CSVBool                   // a bool, as True or False
+)
+
+// A CSVColumn describes a column of a CSVTable.
+type CSVColumn struct {
+	Name string
+	Kind CSVKind
+
+	// Scale is the number of fraction digits of a CSVFloat or CSVDecimal
+	// column. For a CSVFloat column, -1 writes the fewest digits that
+	// represent the value exactly.
+	Scale int
+
+	// Layout is the time layout of a CSVTime column; time.RFC3339 if empty.
+	Layout string
+
+	// True and False are written for the values of a CSVBool column;
+	// "true" and "false" if empty.
+	True, False string
+}
+
+// A CSVTable writes CSV records of typed values directly to a Builder,
+// formatting each value as its column prescribes, so that large exports are
+// consistent and need neither fmt nor a string per field. Fields are
+// delimited and quoted as by WriteCSVRecord.
+type CSVTable struct {
+	b       *Builder
+	columns []CSVColumn
+	scratch []byte
+}
+
+// CSVTable returns a CSVTable writing records with the given columns to b.
+func (b *Builder) CSVTable(columns ...CSVColumn) *CSVTable {
+	return &CSVTable{b: b, columns: columns}
+}
+
+// WriteHeader writes a record of the column names.
+func (t *CSVTable) WriteHeader() {
+	b := t.b
+	comma, crlf := b.csvComma(), b.eol() == "\r\n"
+	b.copyCheck()
+	n := len(b.buf)
+	for i, c := range t.columns {
+		if i > 0 {
+			b.buf = utf8.AppendRune(b.buf, comma)
+		}
+		b.buf = appendCSVField(b.buf, c.Name, comma, crlf)
+	}
+	b.buf = append(b.buf, b.eol()...)
+	b.commit(n)
+}
+
+// WriteRow writes a record of values, one per column, each formatted as its
+// column prescribes. A nil value is written as an empty field. If the number
+// of values does not match the number of columns, or a value does not have a
+// type the kind of its column accepts, WriteRow leaves b unchanged and
+// returns an error.
+func (t *CSVTable) WriteRow(values ...any) error {
+	if len(values) != len(t.columns) {
+		return errors.New("builder: CSV row has " + strconv.Itoa(len(values)) + " values; want " + strconv.Itoa(len(t.columns)))
+	}
+
+	b := t.b
+	comma, crlf := b.csvComma(), b.eol() == "\r\n"
+	b.copyCheck()
+	n := len(b.buf)
+	for i, v := range values {
+		if i > 0 {
+			b.buf = utf8.AppendRune(b.buf, comma)
+		}
+		buf, err := t.appendValue(b.buf, &t.columns[i], v, comma, crlf)
+		if err != nil {
+			b.rollback(n)
+			return err
+		}
+		b.buf = buf
+	}
+	b.buf = append(b.buf, b.eol()...)
+	b.commit(n)
+	return nil
+}
+
+// appendValue appends v as a field of column c to dst.
+func (t *CSVTable) appendValue(dst []byte, c *CSVColumn, v any, comma rune, crlf bool) ([]byte, error) {
+	if v == nil {
+		return dst, nil
+	}
+
+	start := len(dst)
+	ok := true
+	switch c.Kind {
+	case CSVString:
+		switch v := v.(type) {
+		case string:
+			return appendCSVField(dst, v, comma, crlf), nil
+		case []byte:
+			dst = append(dst, v...)
+		case interface{ String() string }:
+			return appendCSVField(dst, v.String(), comma, crlf), nil
+		default:
+			ok = false
+		}
+	case CSVInt:
+		dst, ok = appendAnyInt(dst, v, 0)
+	case CSVDecimal:
+		dst, ok = appendAnyInt(dst, v, c.Scale)
+	case CSVFloat:
+		switch v := v.(type) {
+		case float64:
+			dst = t.b.appendFloat(dst, v, 'f', c.Scale, 64)
+		case float32:
+			dst = t.b.appendFloat(dst, float64(v), 'f', c.Scale, 32)
+		default:
+			ok = false
+		}
+	case CSVTime:
+		tm, isTime := v.(time.Time)
+		if ok = isTime; ok {
+			layout := c.Layout
+			if layout == "" {
+				layout = time.RFC3339
+			}
+			dst = tm.AppendFormat(dst, layout)
+		}
+	case CSVBool:
+		x, isBool := v.(bool)
+		if ok = isBool; ok {
+			switch {
+			case x && c.True != "":
+				dst = append(dst, c.True...)
+			case !x && c.False != "":
+				dst = append(dst, c.False...)
+			default:
+				dst = strconv.AppendBool(dst, x)
+			}
+		}
+	default:
+		panic("builder.CSVTable.WriteRow: unknown column kind " + strconv.Itoa(int(c.Kind)))
+	}
+	if !ok {
+		return dst, errors.New("builder: CSV column " + strconv.Quote(c.Name) + ": unsupported value type")
+	}
+
+	// Formatted values, such as times with a custom layout, may still need
+	// quoting. dst[start:] has not been committed, so it can be rewritten.
+	if field := dst[start:]; csvNeedsQuotes(unsafe.String(unsafe.SliceData(field), len(field)), comma) {
+		t.scratch = append(t.scratch[:0], field...)
+		dst = appendCSVField(dst[:start], unsafe.String(unsafe.SliceData(t.scratch), len(t.scratch)), comma, crlf)
+	}
+	return dst, nil
+}
+
+// appendAnyInt appends v, which must have an integer type, to dst as by
+// appendDecimal with scale. It reports whether v is an integer.
+func appendAnyInt(dst []byte, v any, scale int) ([]byte, bool) {
+	var i int64
+	switch v := v.(type) {
+	case int:
+		i = int64(v)
+	case int8:
+		i = int64(v)
+	case int16:
+		i = int64(v)
+	case int32:
+		i = int64(v)
+	case int64:
+		i = v
+	case uint8:
+		i = int64(v)
+	case uint16:
+		i = int64(v)
+	case uint32:
+		i = int64(v)
+	case uint:
+		return appendUintDecimal(dst, uint64(v), scale)
+	case uint64:
+		return appendUintDecimal(dst, v, scale)
+	case uintptr:
+		return appendUintDecimal(dst, uint64(v), scale)
+	default:
+		return dst, false
+	}
+	return appendDecimal(dst, i, scale), true
+}
+
+// appendUintDecimal is like appendAnyInt for an unsigned integer u, which
+// must fit in an int64 unless scale is zero.
+func appendUintDecimal(dst []byte, u uint64, scale int) ([]byte, bool) {
+	switch {
+	case u <= math.MaxInt64:
+		return appendDecimal(dst, int64(u), scale), true
+	case scale == 0:
+		return strconv.AppendUint(dst, u, 10), true
+	}
+	return dst, false
+}
@@ -0,0 +1,104 @@
+// Copyright 2023 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package builder_test
+
+import (
+	"math"
+	"net/netip"
+	"testing"
+	"time"
+
+	. "github.com/weiwenchen2022/builder"
+)
+
+var csvColumns = []CSVColumn{
+	{Name: "id", Kind: CSVInt},
+	{Name: "name", Kind: CSVString},
+	{Name: "price", Kind: CSVDecimal, Scale: 2},
+	{Name: "ratio", Kind: CSVFloat, Scale: -1},
+	{Name: "when", Kind: CSVTime, Layout: "Jan 2, 2006"},
+	{Name: "active", Kind: CSVBool, True: "Y", False: "N"},
+}
+
+func TestCSVTable(t *testing.T) {
+	t.Parallel()
+
+	when := time.Date(2023, 3, 4, 5, 6, 7, 0, time.UTC)
+	var b Builder
+	tab := b.CSVTable(csvColumns...)
+	tab.WriteHeader()
+	rows := [][]any{
+		{1, "plain", int64(12345), 0.5, when, true},
+		{uint8(2), `say "hi", bye`, -5, float32(1.25), when, false},
+		{uint64(math.MaxUint64), []byte("a\nb"), uint16(7), 1e21, when, nil},
+		{nil, netip.MustParseAddr("::1"), nil, nil, nil, nil},
+	}
+	for _, row := range rows {
+		if err := tab.WriteRow(row...); err != nil {
+			t.Fatalf("WriteRow(%v): %v", row, err)
+		}
+	}
+	check(t, &b, "id,name,price,ratio,when,active\n"+
+		`1,plain,123.45,0.5,"Mar 4, 2023",Y`+"\n"+
+		`2,"say ""hi"", bye",-0.05,1.25,"Mar 4, 2023",N`+"\n"+
+		"18446744073709551615,\"a\nb\",0.07,1000000000000000000000,\"Mar 4, 2023\",\n"+
+		",::1,,,,\n")
+}
+
+func TestCSVTableDelimiter(t *testing.T) {
+	t.Parallel()
+
+	var b Builder
+	b.SetCSVDelimiter(';')
+	b.SetLineEnding(CRLF)
+	tab := b.CSVTable(
+		CSVColumn{Name: "amount;eur", Kind: CSVFloat, Scale: 2},
+		CSVColumn{Name: "day", Kind: CSVTime},
+		CSVColumn{Name: "ok", Kind: CSVBool},
+	)
+	tab.WriteHeader()
+	if err := tab.WriteRow(3.14159, time.Date(2023, 1, 2, 3, 4, 5, 0, time.UTC), false); err != nil {
+		t.Fatal(err)
+	}
+	check(t, &b, "\"amount;eur\";day;ok\r\n3.14;2023-01-02T03:04:05Z;false\r\n")
+}
+
+func TestCSVTableError(t *testing.T) {
+	t.Parallel()
+
+	tests := [][]any{
+		{1, "x"},
+		{1, "x", 1, 1.5, time.Time{}, true, "extra"},
+		{"1", "x", 1, 1.5, time.Time{}, true},
+		{1, 2, 1, 1.5, time.Time{}, true},
+		{1, "x", 1.5, 1.5, time.Time{}, true},
+		{1, "x", uint64(math.MaxUint64), 1.5, time.Time{}, true},
+		{1, "x", 1, 1, time.Time{}, true},
+		{1, "x", 1, 1.5, "2023", true},
+		{1, "x", 1, 1.5, time.Time{}, 1},
+	}
+	for _, row := range tests {
+		var b Builder
+		b.WriteString("keep\n")
+		if err := b.CSVTable(csvColumns...).WriteRow(row...); err == nil {
+			t.Errorf("WriteRow(%v): got nil error", row)
+		}
+		check(t, &b, "keep\n")
+	}
+}
+
+func TestCSVTableAllocs(t *testing.T) {
+	var b Builder
+	b.Grow(1024)
+	tab := b.CSVTable(csvColumns...)
+	row := []any{42, "name, quoted", int64(999), 2.5, time.Date(2023, 3, 4, 0, 0, 0, 0, time.UTC), true}
+	tab.WriteRow(row...)
+	allocs := testing.AllocsPerRun(100, func() {
+		tab.WriteRow(row...)
+	})
+	if allocs != 0 {
+		t.Errorf("WriteRow allocs = %v; want 0", allocs)
+	}
+}
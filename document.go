@@ -0,0 +1,177 @@
+// Copyright 2023 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package builder
+
+import (
+	"errors"
+	"sort"
+	"strconv"
+	"unsafe"
+)
+
+// A Document builds a string containing placeholders whose content is only
+// known later, such as a total written above the lines it sums. Its embedded
+// Builder accumulates the text around the placeholders; WritePlaceholder
+// reserves a slot, and the content of each slot is spliced in by Render.
+// The zero value is ready to use. Do not copy a non-zero Document.
+type Document struct {
+	Builder
+	slots  []docSlot
+	values map[string]*Builder
+}
+
+type docSlot struct {
+	name   string
+	region *SortedRegion // content of the slot, if it is a sorted region
+	off    int           // offset in the Builder's content
+}
+
+// WritePlaceholder reserves a slot named name at the current end of the
+// document. A name may be used for several slots, which all receive the
+// same content.
+func (d *Document) WritePlaceholder(name string) {
+	d.copyCheck()
+	d.dropCutSlots()
+	d.slots = append(d.slots, docSlot{name: name, off: len(d.buf)})
+}
+
+// dropCutSlots forgets the slots whose offset was cut away from the content
+// since they were reserved, as by TruncateTo, RebuildFrom, UnmarshalText or
+// Builder.Reset, and starts tracking cuts anew.
+func (d *Document) dropCutSlots() {
+	cutTo := len(d.buf)
+	if m := d.mode; m == nil || !m.trackCuts {
+		// The mode was reset with the content, or never tracked any slot.
+		if len(d.slots) > 0 {
+			cutTo = 0
+		}
+	} else if m.cutTo < cutTo {
+		cutTo = m.cutTo
+	}
+	i := len(d.slots)
+	for i > 0 && d.slots[i-1].off > cutTo {
+		i--
+	}
+	d.slots = d.slots[:i]
+
+	if d.mode == nil {
+		d.mode = new(mode)
+	}
+	d.mode.trackCuts = true
+	d.mode.cutTo = maxInt
+}
+
+// A SortedRegion is a slot of a Document holding lines that are kept sorted
+// and free of duplicates, such as the imports of a Go file.
+type SortedRegion struct {
+	lines []string
+}
+
+// SortedRegion reserves a slot at the current end of the document whose
+// content is the lines added to the returned SortedRegion, each followed by
+// a line ending as set by SetLineEnding.
+func (d *Document) SortedRegion() *SortedRegion {
+	d.copyCheck()
+	d.dropCutSlots()
+	r := new(SortedRegion)
+	d.slots = append(d.slots, docSlot{region: r, off: len(d.buf)})
+	return r
+}
+
+// Add adds line, which should not contain a line ending, to r unless r
+// already holds it.
+func (r *SortedRegion) Add(line string) {
+	i := sort.SearchStrings(r.lines, line)
+	if i < len(r.lines) && r.lines[i] == line {
+		return
+	}
+	r.lines = append(r.lines, "")
+	copy(r.lines[i+1:], r.lines[i:])
+	r.lines[i] = line
+}
+
+// Lines returns the lines of r in sorted order. The caller must not modify
+// the returned slice.
+func (r *SortedRegion) Lines() []string {
+	return r.lines
+}
+
+// size returns the length of the content of r with lines ending in eol.
+func (r *SortedRegion) size(eol string) int {
+	size := len(r.lines) * len(eol)
+	for _, line := range r.lines {
+		size += len(line)
+	}
+	return size
+}
+
+// Slot returns the Builder holding the content of the slots named name.
+// It may be written to at any time before Render, including before the
+// slots are reserved.
+func (d *Document) Slot(name string) *Builder {
+	v := d.values[name]
+	if v == nil {
+		if d.values == nil {
+			d.values = make(map[string]*Builder)
+		}
+		v = new(Builder)
+		d.values[name] = v
+	}
+	return v
+}
+
+// Set sets the content of the slots named name to value, replacing any
+// content set before.
+func (d *Document) Set(name, value string) {
+	v := d.Slot(name)
+	v.Reset()
+	v.WriteString(value)
+}
+
+// Render returns the document with the content of every slot spliced in,
+// making a single allocation. If the content of a slot has not been set,
+// Render returns an error. The document may be extended and rendered again.
+// Slots that were cut away from the content, as by TruncateTo, are dropped.
+func (d *Document) Render() (string, error) {
+	d.copyCheck()
+	d.dropCutSlots()
+	size := len(d.buf)
+	for _, s := range d.slots {
+		if s.region != nil {
+			size += s.region.size(d.eol())
+			continue
+		}
+		v := d.values[s.name]
+		if v == nil {
+			return "", errors.New("builder: placeholder " + strconv.Quote(s.name) + " not set")
+		}
+		size += v.Len()
+	}
+
+	buf := make([]byte, 0, size)
+	last := 0
+	for _, s := range d.slots {
+		buf = append(buf, d.buf[last:s.off]...)
+		if s.region != nil {
+			for _, line := range s.region.lines {
+				buf = append(buf, line...)
+				buf = append(buf, d.eol()...)
+			}
+		} else {
+			buf = append(buf, d.values[s.name].buf...)
+		}
+		last = s.off
+	}
+	buf = append(buf, d.buf[last:]...)
+	return unsafe.String(unsafe.SliceData(buf), len(buf)), nil
+}
+
+// Reset resets the Document to be empty, forgetting its slots and their
+// content.
+func (d *Document) Reset() {
+	d.Builder.Reset()
+	d.slots = nil
+	d.values = nil
+}
@@ -0,0 +1,147 @@
+// Copyright 2023 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package builder_test
+
+import (
+	"testing"
+
+	. "github.com/weiwenchen2022/builder"
+)
+
+func TestDocument(t *testing.T) {
+	t.Parallel()
+
+	var d Document
+	d.WriteString("Total: ")
+	d.WritePlaceholder("total")
+	d.WriteString(" (")
+	d.WritePlaceholder("count")
+	d.WriteString(" items)\n")
+	total := 0
+	for i, price := range []int{3, 5, 9} {
+		d.WriteInt(int64(i), 10)
+		d.WriteString(": ")
+		d.WriteInt(int64(price), 10)
+		d.WriteByte('\n')
+		total += price
+	}
+	d.WriteString("Total again: ")
+	d.WritePlaceholder("total")
+
+	if _, err := d.Render(); err == nil {
+		t.Error("Render with unset placeholders: got nil error")
+	}
+
+	d.Slot("total").WriteInt(int64(total), 10)
+	d.Set("count", "three")
+	want := "Total: 17 (three items)\n0: 3\n1: 5\n2: 9\nTotal again: 17"
+	got, err := d.Render()
+	if err != nil || got != want {
+		t.Errorf("Render: got %q,%v; want %q,nil", got, err, want)
+	}
+
+	// Content can be replaced, and the document extended, before rendering again.
+	d.Set("count", "3")
+	d.WritePlaceholder("count")
+	got, err = d.Render()
+	if want := "Total: 17 (3 items)\n0: 3\n1: 5\n2: 9\nTotal again: 173"; err != nil || got != want {
+		t.Errorf("Render: got %q,%v; want %q,nil", got, err, want)
+	}
+
+	d.Reset()
+	d.WriteString("empty")
+	if got, err := d.Render(); err != nil || got != "empty" {
+		t.Errorf("Render after Reset: got %q,%v; want %q,nil", got, err, "empty")
+	}
+}
+
+func TestDocumentCut(t *testing.T) {
+	t.Parallel()
+
+	var d Document
+	d.Set("x", "X")
+	d.WriteString("0123456789")
+	d.WritePlaceholder("x")
+	d.WriteString("tail")
+	d.TruncateRunes(5)
+	if got, err := d.Render(); err != nil || got != "01234" {
+		t.Errorf("Render after TruncateRunes: got %q,%v; want %q,nil", got, err, "01234")
+	}
+
+	// A slot cut away stays dropped even if the content grows back past it.
+	d.WriteString("56789abc")
+	if got, err := d.Render(); err != nil || got != "0123456789abc" {
+		t.Errorf("Render after regrowing: got %q,%v; want %q,nil", got, err, "0123456789abc")
+	}
+
+	// A slot at the cut point is kept.
+	d.WritePlaceholder("x")
+	d.WriteString("def")
+	d.TruncateTo(d.Len()-3, "")
+	if got, err := d.Render(); err != nil || got != "0123456789abcX" {
+		t.Errorf("Render after cut at slot: got %q,%v; want %q,nil", got, err, "0123456789abcX")
+	}
+
+	d.WritePlaceholder("x")
+	if err := d.UnmarshalText([]byte("new")); err != nil {
+		t.Fatal(err)
+	}
+	if got, err := d.Render(); err != nil || got != "new" {
+		t.Errorf("Render after UnmarshalText: got %q,%v; want %q,nil", got, err, "new")
+	}
+
+	d.WritePlaceholder("x")
+	d.RebuildFrom(d.Snapshot())
+	d.WriteString("frame")
+	if got, err := d.Render(); err != nil || got != "frame" {
+		t.Errorf("Render after RebuildFrom: got %q,%v; want %q,nil", got, err, "frame")
+	}
+}
+
+func TestDocumentRenderAllocs(t *testing.T) {
+	var d Document
+	d.WritePlaceholder("a")
+	d.WriteString(" and ")
+	d.WritePlaceholder("b")
+	d.Set("a", "first")
+	d.Set("b", "second")
+	allocs := testing.AllocsPerRun(100, func() {
+		d.Render()
+	})
+	if allocs != 1 {
+		t.Errorf("Render allocs = %v; want 1", allocs)
+	}
+}
+
+func TestDocumentSortedRegion(t *testing.T) {
+	t.Parallel()
+
+	var d Document
+	d.WriteString("import (\n")
+	imports := d.SortedRegion()
+	d.WriteString(")\n")
+	for _, pkg := range []string{"os", "fmt", "strings", "fmt", "bytes", "os"} {
+		imports.Add("\t\"" + pkg + "\"")
+		d.WriteString("// uses " + pkg + "\n")
+	}
+	want := "import (\n\t\"bytes\"\n\t\"fmt\"\n\t\"os\"\n\t\"strings\"\n)\n" +
+		"// uses os\n// uses fmt\n// uses strings\n// uses fmt\n// uses bytes\n// uses os\n"
+	got, err := d.Render()
+	if err != nil || got != want {
+		t.Errorf("Render: got %q,%v; want %q,nil", got, err, want)
+	}
+	if n := len(imports.Lines()); n != 4 {
+		t.Errorf("Lines: got %d lines; want 4", n)
+	}
+
+	var e Document
+	e.SetLineEnding(CRLF)
+	r := e.SortedRegion()
+	r.Add("b")
+	r.Add("a")
+	if got, err := e.Render(); err != nil || got != "a\r\nb\r\n" {
+		t.Errorf("Render with CRLF: got %q,%v; want %q,nil", got, err, "a\r\nb\r\n")
+	}
+}
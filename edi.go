@@ -0,0 +1,159 @@
+// Copyright 2023 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package builder
+
+import (
+	"errors"
+	"strconv"
+)
+
+// EDIDelimiters are the service characters of an EDI interchange.
+// A zero Release means the syntax has no release (escape) character,
+// so data containing a delimiter cannot be represented.
+type EDIDelimiters struct {
+	Element   byte // separates the data elements of a segment
+	Component byte // separates the components of a composite element
+	Release   byte // escapes a service character occurring in data
+	Segment   byte // terminates a segment
+}
+
+var (
+	// EDIFACTDelimiters are the default UN/EDIFACT service characters.
+	EDIFACTDelimiters = EDIDelimiters{Element: '+', Component: ':', Release: '?', Segment: '\''}
+
+	// X12Delimiters are the service characters commonly used in ASC X12
+	// interchanges, which have no release character.
+	X12Delimiters = EDIDelimiters{Element: '*', Component: ':', Segment: '~'}
+)
+
+// An EDIWriter writes EDIFACT or X12 segments to a Builder, separating
+// elements and components and escaping service characters in data.
+type EDIWriter struct {
+	b    *Builder
+	d    EDIDelimiters
+	open bool
+}
+
+// EDI returns an EDIWriter that writes segments to b using the delimiters d.
+func (b *Builder) EDI(d EDIDelimiters) *EDIWriter {
+	return &EDIWriter{b: b, d: d}
+}
+
+func (w *EDIWriter) isDelim(c byte) bool {
+	d := w.d
+	return c == d.Element || c == d.Component || c == d.Segment || (c == d.Release && c != 0)
+}
+
+// check reports an error if s contains a service character that cannot be
+// escaped.
+func (w *EDIWriter) check(s string) error {
+	if w.d.Release != 0 {
+		return nil
+	}
+	for i := 0; i < len(s); i++ {
+		if w.isDelim(s[i]) {
+			return errors.New("builder: EDI data " + strconv.Quote(s) + " contains delimiter " + strconv.QuoteRune(rune(s[i])))
+		}
+	}
+	return nil
+}
+
+func (w *EDIWriter) appendData(dst []byte, s string) []byte {
+	last := 0
+	for i := 0; i < len(s); i++ {
+		if w.isDelim(s[i]) {
+			dst = append(dst, s[last:i]...)
+			dst = append(dst, w.d.Release, s[i])
+			last = i + 1
+		}
+	}
+	return append(dst, s[last:]...)
+}
+
+// Segment writes a complete segment with the given tag and simple data
+// elements. If an element cannot be represented with w's delimiters, Segment
+// writes nothing and returns an error.
+// It panics if a segment opened by StartSegment has not been ended.
+func (w *EDIWriter) Segment(tag string, elements ...string) error {
+	if w.open {
+		panic("builder.EDIWriter.Segment: segment already open")
+	}
+	for _, e := range elements {
+		if err := w.check(e); err != nil {
+			return err
+		}
+	}
+
+	b := w.b
+	b.copyCheck()
+	n := len(b.buf)
+	b.buf = append(b.buf, tag...)
+	for _, e := range elements {
+		b.buf = append(b.buf, w.d.Element)
+		b.buf = w.appendData(b.buf, e)
+	}
+	b.buf = append(b.buf, w.d.Segment)
+	b.commit(n)
+	return nil
+}
+
+// StartSegment opens a segment with the given tag, to be followed by calls to
+// Element and a call to EndSegment.
+// It panics if a segment is already open.
+func (w *EDIWriter) StartSegment(tag string) {
+	if w.open {
+		panic("builder.EDIWriter.StartSegment: segment already open")
+	}
+	w.open = true
+
+	b := w.b
+	b.copyCheck()
+	n := len(b.buf)
+	b.buf = append(b.buf, tag...)
+	b.commit(n)
+}
+
+// Element writes a data element of the open segment. More than one component
+// makes a composite element; no components make an empty element. If a
+// component cannot be represented with w's delimiters, Element writes
+// nothing and returns an error.
+// It panics if no segment is open.
+func (w *EDIWriter) Element(components ...string) error {
+	if !w.open {
+		panic("builder.EDIWriter.Element: no open segment")
+	}
+	for _, c := range components {
+		if err := w.check(c); err != nil {
+			return err
+		}
+	}
+
+	b := w.b
+	b.copyCheck()
+	n := len(b.buf)
+	b.buf = append(b.buf, w.d.Element)
+	for i, c := range components {
+		if i > 0 {
+			b.buf = append(b.buf, w.d.Component)
+		}
+		b.buf = w.appendData(b.buf, c)
+	}
+	b.commit(n)
+	return nil
+}
+
+// EndSegment terminates the open segment. It panics if there is none.
+func (w *EDIWriter) EndSegment() {
+	if !w.open {
+		panic("builder.EDIWriter.EndSegment: no open segment")
+	}
+	w.open = false
+
+	b := w.b
+	b.copyCheck()
+	n := len(b.buf)
+	b.buf = append(b.buf, w.d.Segment)
+	b.commit(n)
+}
@@ -0,0 +1,75 @@
+// Copyright 2023 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package builder_test
+
+import (
+	"testing"
+
+	. "github.com/weiwenchen2022/builder"
+)
+
+func TestEDIWriterEDIFACT(t *testing.T) {
+	t.Parallel()
+
+	var b Builder
+	w := b.EDI(EDIFACTDelimiters)
+	if err := w.Segment("UNH", "1", "ORDERS:D:96A:UN"); err != nil {
+		t.Fatal(err)
+	}
+	w.StartSegment("NAD")
+	w.Element("BY")
+	w.Element()
+	w.Element("Smith+Sons", "Ltd: what?", "O'Neil")
+	w.EndSegment()
+	check(t, &b, "UNH+1+ORDERS?:D?:96A?:UN'NAD+BY++Smith?+Sons:Ltd?: what??:O?'Neil'")
+}
+
+func TestEDIWriterX12(t *testing.T) {
+	t.Parallel()
+
+	var b Builder
+	w := b.EDI(X12Delimiters)
+	if err := w.Segment("ST", "850", "0001"); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.Segment("N1", "BY", "A*B"); err == nil {
+		t.Error("Segment: got nil error for data containing a delimiter")
+	}
+	w.StartSegment("PO1")
+	w.Element("1")
+	if err := w.Element("EA", "x~y"); err == nil {
+		t.Error("Element: got nil error for data containing a delimiter")
+	}
+	w.Element("EA", "12")
+	w.EndSegment()
+	check(t, &b, "ST*850*0001~PO1*1*EA:12~")
+}
+
+func TestEDIWriterPanics(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		f    func(w *EDIWriter)
+	}{
+		{"EndSegment", func(w *EDIWriter) { w.EndSegment() }},
+		{"Element", func(w *EDIWriter) { w.Element("x") }},
+		{"StartSegment", func(w *EDIWriter) { w.StartSegment("A"); w.StartSegment("B") }},
+		{"Segment", func(w *EDIWriter) { w.StartSegment("A"); w.Segment("B") }},
+	}
+
+	for _, tt := range tests {
+		var b Builder
+		w := b.EDI(EDIFACTDelimiters)
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("%s: did not panic", tt.name)
+				}
+			}()
+			tt.f(w)
+		}()
+	}
+}
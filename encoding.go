@@ -0,0 +1,100 @@
+// Copyright 2023 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package builder
+
+import (
+	"encoding"
+	"encoding/json"
+)
+
+// WriteTextAppender appends the textual representation of v, as produced by
+// its AppendText method, directly to b's buffer.
+// It returns the length of written. If AppendText fails, b is left unchanged
+// and the error is returned.
+func (b *Builder) WriteTextAppender(v interface{ AppendText([]byte) ([]byte, error) }) (int, error) {
+	b.copyCheck()
+	n := len(b.buf)
+	buf, err := v.AppendText(b.buf)
+	if err != nil {
+		return 0, err
+	}
+	b.buf = buf
+	return b.commit(n), nil
+}
+
+// WriteTextMarshaler appends the textual representation of v to b's buffer.
+// If v also implements AppendText, it is used to avoid the intermediate
+// allocation of MarshalText.
+// It returns the length of written. If marshaling fails, b is left unchanged
+// and the error is returned.
+func (b *Builder) WriteTextMarshaler(v encoding.TextMarshaler) (int, error) {
+	if a, ok := v.(interface{ AppendText([]byte) ([]byte, error) }); ok {
+		return b.WriteTextAppender(a)
+	}
+
+	text, err := v.MarshalText()
+	if err != nil {
+		return 0, err
+	}
+	return b.Write(text)
+}
+
+// MarshalText implements the encoding.TextMarshaler interface.
+// It returns a copy of the accumulated bytes.
+func (b *Builder) MarshalText() ([]byte, error) {
+	return append([]byte(nil), b.buf...), nil
+}
+
+// UnmarshalText implements the encoding.TextUnmarshaler interface.
+// It replaces the contents of b with a copy of text. Strings previously
+// returned by String are not affected, unless b was created by NewFixed, in
+// which case text is copied into its buffer, or ErrFull is returned and b is
+// left unchanged if text does not fit. Enabled write modes continue from the
+// new content: no separator precedes the next write, and line prefixes
+// resume according to whether text ends with a newline.
+func (b *Builder) UnmarshalText(text []byte) error {
+	b.copyCheck()
+	if m := b.mode; m != nil && m.fixed != nil {
+		if len(text) > cap(m.fixed) {
+			return ErrFull
+		}
+		b.buf = append(m.fixed[:0], text...)
+		m.replaced(b)
+		return nil
+	}
+	b.buf = append([]byte(nil), text...)
+	if b.mode != nil {
+		b.mode.replaced(b)
+	}
+	return nil
+}
+
+// replaced resets the write-mode state that depends on b's content after
+// UnmarshalText replaced it.
+func (m *mode) replaced(b *Builder) {
+	m.sepArmed = false
+	m.midLine = len(b.buf) > 0 && b.buf[len(b.buf)-1] != '\n'
+	if m.rebuilding {
+		m.same = commonPrefix(b.buf, m.prev)
+	}
+	m.cutContent(b, 0)
+}
+
+// MarshalJSON implements the json.Marshaler interface.
+// The accumulated string is encoded as a JSON string.
+func (b *Builder) MarshalJSON() ([]byte, error) {
+	return json.Marshal(b.String())
+}
+
+// GobEncode implements the gob.GobEncoder interface.
+func (b *Builder) GobEncode() ([]byte, error) {
+	return b.MarshalText()
+}
+
+// GobDecode implements the gob.GobDecoder interface.
+// Like UnmarshalText, it replaces the contents of b.
+func (b *Builder) GobDecode(data []byte) error {
+	return b.UnmarshalText(data)
+}
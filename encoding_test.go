@@ -0,0 +1,199 @@
+// Copyright 2023 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package builder_test
+
+import (
+	"bytes"
+	"encoding/gob"
+	"encoding/json"
+	"errors"
+	"strconv"
+	"testing"
+
+	. "github.com/weiwenchen2022/builder"
+)
+
+type point struct{ x, y int }
+
+func (p point) AppendText(b []byte) ([]byte, error) {
+	if p.x < 0 {
+		// Leave partial output behind to check that it is discarded.
+		return append(b, "bad"...), errors.New("negative x")
+	}
+	b = strconv.AppendInt(b, int64(p.x), 10)
+	b = append(b, ',')
+	return strconv.AppendInt(b, int64(p.y), 10), nil
+}
+
+func (p point) MarshalText() ([]byte, error) {
+	return p.AppendText(nil)
+}
+
+type marshalOnly string
+
+func (m marshalOnly) MarshalText() ([]byte, error) {
+	if m == "" {
+		return nil, errors.New("empty")
+	}
+	return []byte("<" + m + ">"), nil
+}
+
+func TestBuilderWriteTextAppender(t *testing.T) {
+	t.Parallel()
+
+	var b Builder
+	b.WriteString("p=")
+	n, err := b.WriteTextAppender(point{1, 20})
+	if err != nil || n != 4 {
+		t.Errorf("WriteTextAppender: got %d,%v; want 4,nil", n, err)
+	}
+	check(t, &b, "p=1,20")
+
+	n, err = b.WriteTextAppender(point{-1, 0})
+	if err == nil || n != 0 {
+		t.Errorf("WriteTextAppender: got %d,%v; want 0,error", n, err)
+	}
+	check(t, &b, "p=1,20")
+}
+
+func TestBuilderWriteTextMarshaler(t *testing.T) {
+	t.Parallel()
+
+	var b Builder
+	if _, err := b.WriteTextMarshaler(point{3, 4}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := b.WriteTextMarshaler(marshalOnly("m")); err != nil {
+		t.Fatal(err)
+	}
+	check(t, &b, "3,4<m>")
+
+	if _, err := b.WriteTextMarshaler(marshalOnly("")); err == nil {
+		t.Error("WriteTextMarshaler: got nil error")
+	}
+	if _, err := b.WriteTextMarshaler(point{-1, 0}); err == nil {
+		t.Error("WriteTextMarshaler: got nil error")
+	}
+	check(t, &b, "3,4<m>")
+}
+
+func TestBuilderWriteTextAppenderAllocs(t *testing.T) {
+	var b Builder
+	b.Grow(1024)
+	var v interface{ AppendText([]byte) ([]byte, error) } = point{12, 34}
+	allocs := testing.AllocsPerRun(100, func() {
+		b.WriteTextAppender(v)
+	})
+	if allocs != 0 {
+		t.Errorf("WriteTextAppender allocs = %v; want 0", allocs)
+	}
+}
+
+func TestBuilderMarshalText(t *testing.T) {
+	t.Parallel()
+
+	var b Builder
+	b.WriteString("hello")
+	text, err := b.MarshalText()
+	if err != nil || string(text) != "hello" {
+		t.Errorf("MarshalText: got %q,%v; want %q,nil", text, err, "hello")
+	}
+	text[0] = 'j' // must not alias the builder's buffer
+	check(t, &b, "hello")
+
+	s := b.String()
+	if err := b.UnmarshalText([]byte("world")); err != nil {
+		t.Fatal(err)
+	}
+	check(t, &b, "world")
+	if s != "hello" {
+		t.Errorf("previous String result changed after UnmarshalText: got %q", s)
+	}
+}
+
+func TestBuilderUnmarshalTextModes(t *testing.T) {
+	t.Parallel()
+
+	var b Builder
+	b.SetSep(", ")
+	b.WriteString("a")
+	b.WriteString("b")
+	if err := b.UnmarshalText([]byte("x")); err != nil {
+		t.Fatal(err)
+	}
+	b.WriteString("y")
+	b.WriteString("z")
+	check(t, &b, "xy, z")
+
+	b.Reset()
+	b.PushLinePrefix("> ")
+	b.WriteString("partial")
+	if err := b.UnmarshalText([]byte("done\n")); err != nil {
+		t.Fatal(err)
+	}
+	b.WriteString("next\n")
+	check(t, &b, "done\n> next\n")
+
+	b.WriteString("line\n")
+	if err := b.UnmarshalText([]byte("open")); err != nil {
+		t.Fatal(err)
+	}
+	b.WriteString(" end\n")
+	check(t, &b, "open end\n")
+}
+
+func TestBuilderJSON(t *testing.T) {
+	t.Parallel()
+
+	type snapshot struct {
+		Name string
+		Body Builder
+	}
+
+	var in snapshot
+	in.Name = "x"
+	in.Body.WriteString("line 1\nline \"2\"")
+	data, err := json.Marshal(&in)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := `{"Name":"x","Body":"line 1\nline \"2\""}`; want != string(data) {
+		t.Errorf("Marshal: got %s; want %s", data, want)
+	}
+
+	var out snapshot
+	if err := json.Unmarshal(data, &out); err != nil {
+		t.Fatal(err)
+	}
+	check(t, &out.Body, in.Body.String())
+}
+
+func TestBuilderGob(t *testing.T) {
+	t.Parallel()
+
+	type message struct {
+		ID   int
+		Body Builder
+	}
+
+	var in message
+	in.ID = 7
+	in.Body.WriteString("héllo\x00world")
+
+	var network bytes.Buffer
+	if err := gob.NewEncoder(&network).Encode(&in); err != nil {
+		t.Fatal(err)
+	}
+
+	var out message
+	out.Body.WriteString("stale")
+	if err := gob.NewDecoder(&network).Decode(&out); err != nil {
+		t.Fatal(err)
+	}
+	if out.ID != 7 {
+		t.Errorf("ID: got %d; want 7", out.ID)
+	}
+	check(t, &out.Body, in.Body.String())
+}
@@ -0,0 +1,42 @@
+// Copyright 2023 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package builder
+
+import "strconv"
+
+// An Enum holds the names of the values of an integer enumeration type T,
+// so that high-volume output can spell them out with a slice index rather
+// than a map lookup or a call to a String method.
+type Enum[T ~int] struct {
+	names []string
+}
+
+// RegisterEnum returns an Enum naming each value v of T in [0, len(names))
+// names[v]. An empty name leaves its value unnamed. names is copied.
+func RegisterEnum[T ~int](names []string) *Enum[T] {
+	return &Enum[T]{names: append([]string(nil), names...)}
+}
+
+// Name returns the name of v, or the decimal form of v if it has none.
+func (e *Enum[T]) Name(v T) string {
+	if uint(v) < uint(len(e.names)) && e.names[v] != "" {
+		return e.names[v]
+	}
+	return strconv.Itoa(int(v))
+}
+
+// Write appends the name of v to b's buffer, or the decimal form of v if it
+// has none.
+// It returns the length of written and a nil error.
+func (e *Enum[T]) Write(b *Builder, v T) (int, error) {
+	b.copyCheck()
+	n := len(b.buf)
+	if uint(v) < uint(len(e.names)) && e.names[v] != "" {
+		b.buf = append(b.buf, e.names[v]...)
+	} else {
+		b.buf = strconv.AppendInt(b.buf, int64(v), 10)
+	}
+	return b.commit(n), nil
+}
@@ -0,0 +1,73 @@
+// Copyright 2023 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package builder_test
+
+import (
+	"testing"
+
+	. "github.com/weiwenchen2022/builder"
+)
+
+type severity int
+
+const (
+	debug severity = iota
+	info
+	warn
+	unnamed
+	fatal
+)
+
+var severities = RegisterEnum[severity]([]string{"DEBUG", "INFO", "WARN", "", "FATAL"})
+
+func TestEnum(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		v    severity
+		want string
+	}{
+		{debug, "DEBUG"},
+		{warn, "WARN"},
+		{fatal, "FATAL"},
+		{unnamed, "3"},
+		{5, "5"},
+		{-1, "-1"},
+	}
+	for _, tt := range tests {
+		var b Builder
+		n, err := severities.Write(&b, tt.v)
+		if err != nil || n != len(tt.want) {
+			t.Errorf("Write(%d): got %d,%v; want %d,nil", tt.v, n, err, len(tt.want))
+		}
+		check(t, &b, tt.want)
+		if got := severities.Name(tt.v); got != tt.want {
+			t.Errorf("Name(%d): got %q; want %q", tt.v, got, tt.want)
+		}
+	}
+}
+
+func TestEnumCopiesNames(t *testing.T) {
+	t.Parallel()
+
+	names := []string{"a", "b"}
+	e := RegisterEnum[severity](names)
+	names[0] = "changed"
+	if got := e.Name(0); got != "a" {
+		t.Errorf("Name(0): got %q; want %q", got, "a")
+	}
+}
+
+func TestEnumAllocs(t *testing.T) {
+	var b Builder
+	b.Grow(1024)
+	allocs := testing.AllocsPerRun(100, func() {
+		severities.Write(&b, info)
+		severities.Write(&b, 42)
+	})
+	if allocs != 0 {
+		t.Errorf("Write allocs = %v; want 0", allocs)
+	}
+}
@@ -0,0 +1,163 @@
+// Copyright 2023 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package builder
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+)
+
+// ExcerptOptions controls how WriteSourceExcerptOptions renders an excerpt.
+type ExcerptOptions struct {
+	// Context is the number of lines shown before and after the line
+	// holding the position.
+	Context int
+
+	// Color highlights the line numbers and the underline with ANSI
+	// escape sequences, for display in a terminal.
+	Color bool
+}
+
+const (
+	ansiBlue    = "\x1b[34m"
+	ansiBoldRed = "\x1b[1;31m"
+	ansiReset   = "\x1b[0m"
+)
+
+// WriteSourceExcerpt appends the line of src holding a position, with its line
+// number, to b's buffer, followed by a line underlining span bytes from the
+// position with carets, as compilers do in diagnostics:
+//
+//	12 | x := foo(bar)
+//	   |          ^^^
+//
+// line and col are 1-based, and col counts bytes, as in go/token.Position.
+// A col past the end of the line points just after it, and the underline is
+// at least one caret wide. Tabs before the position are repeated in the
+// underline to keep it aligned. Lines end as set by SetLineEnding.
+// It returns the length of written, or an error if src has no line line,
+// in which case b is left unchanged.
+func (b *Builder) WriteSourceExcerpt(src string, line, col, span int) (int, error) {
+	return b.WriteSourceExcerptOptions(src, line, col, span, ExcerptOptions{})
+}
+
+// WriteSourceExcerptOptions is like WriteSourceExcerpt but renders according
+// to opts.
+func (b *Builder) WriteSourceExcerptOptions(src string, line, col, span int, opts ExcerptOptions) (int, error) {
+	if line < 1 {
+		return 0, errExcerptLine(line)
+	}
+	if opts.Context < 0 {
+		opts.Context = 0
+	}
+	first, last := line-opts.Context, maxInt
+	if opts.Context < maxInt-line {
+		last = line + opts.Context
+	}
+	if first < 1 {
+		first = 1
+	}
+	var lines []string
+	for i := 1; i <= last; i++ {
+		s, rest, more := strings.Cut(src, "\n")
+		if i >= first {
+			lines = append(lines, strings.TrimSuffix(s, "\r"))
+		}
+		if !more {
+			break
+		}
+		src = rest
+	}
+	if first+len(lines) <= line {
+		return 0, errExcerptLine(line)
+	}
+	gutter := len(strconv.Itoa(first + len(lines) - 1))
+
+	b.copyCheck()
+	n := len(b.buf)
+	for i, s := range lines {
+		b.buf = appendExcerptGutter(b.buf, strconv.Itoa(first+i), gutter, opts.Color)
+		if s != "" {
+			b.buf = append(b.buf, ' ')
+			b.buf = append(b.buf, s...)
+		}
+		b.buf = append(b.buf, b.eol()...)
+		if first+i == line {
+			b.buf = appendExcerptGutter(b.buf, "", gutter, opts.Color)
+			b.buf = append(b.buf, ' ')
+			b.buf = b.appendUnderline(b.buf, s, col, span, opts.Color)
+			b.buf = append(b.buf, b.eol()...)
+		}
+	}
+	return b.commit(n), nil
+}
+
+func errExcerptLine(line int) error {
+	return errors.New("builder: source excerpt line " + strconv.Itoa(line) + " out of range")
+}
+
+// appendExcerptGutter appends num, right-aligned in width columns, and the
+// separating bar.
+func appendExcerptGutter(dst []byte, num string, width int, color bool) []byte {
+	if color {
+		dst = append(dst, ansiBlue...)
+	}
+	for i := len(num); i < width; i++ {
+		dst = append(dst, ' ')
+	}
+	dst = append(dst, num...)
+	dst = append(dst, " |"...)
+	if color {
+		dst = append(dst, ansiReset...)
+	}
+	return dst
+}
+
+// appendUnderline appends carets under span bytes of line starting at the
+// 1-based byte column col.
+func (b *Builder) appendUnderline(dst []byte, line string, col, span int, color bool) []byte {
+	start := col - 1
+	if start < 0 {
+		start = 0
+	}
+	if start > len(line) {
+		start = len(line)
+	}
+	end := start + span
+	if end > len(line) || span < 0 {
+		end = len(line)
+	}
+
+	for _, r := range line[:start] {
+		if r == '\t' {
+			dst = append(dst, '\t')
+			continue
+		}
+		for w := b.runeWidth(r); w > 0; w-- {
+			dst = append(dst, ' ')
+		}
+	}
+	if color {
+		dst = append(dst, ansiBoldRed...)
+	}
+	k := len(dst)
+	for _, r := range line[start:end] {
+		w := b.runeWidth(r)
+		if r == '\t' {
+			w = 1
+		}
+		for ; w > 0; w-- {
+			dst = append(dst, '^')
+		}
+	}
+	if len(dst) == k {
+		dst = append(dst, '^')
+	}
+	if color {
+		dst = append(dst, ansiReset...)
+	}
+	return dst
+}
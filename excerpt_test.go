@@ -0,0 +1,68 @@
+// Copyright 2023 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package builder_test
+
+import (
+	"math"
+	"testing"
+
+	. "github.com/weiwenchen2022/builder"
+)
+
+const excerptSrc = "package main\n\nfunc main() {\n\tx := foo(bar)\n\tprintln(\"héllo\", x)\n}\n"
+
+func TestBuilderWriteSourceExcerpt(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		line, col, span int
+		opts            ExcerptOptions
+		want            string
+	}{
+		{4, 11, 3, ExcerptOptions{}, "4 | \tx := foo(bar)\n  | \t         ^^^\n"},
+		{1, 1, 7, ExcerptOptions{}, "1 | package main\n  | ^^^^^^^\n"},
+		// Zero span and a column past the end of the line.
+		{1, 1, 0, ExcerptOptions{}, "1 | package main\n  | ^\n"},
+		{1, 50, 2, ExcerptOptions{}, "1 | package main\n  |             ^\n"},
+		// Columns count bytes; the underline counts runes.
+		{5, 12, 6, ExcerptOptions{}, "5 | \tprintln(\"héllo\", x)\n  | \t          ^^^^^\n"},
+		{2, 1, 1, ExcerptOptions{Context: 1}, "1 | package main\n2 |\n  | ^\n3 | func main() {\n"},
+		{6, 1, 1, ExcerptOptions{Context: 4}, "2 |\n3 | func main() {\n4 | \tx := foo(bar)\n5 | \tprintln(\"héllo\", x)\n6 | }\n  | ^\n7 |\n"},
+		{1, 9, 4, ExcerptOptions{Color: true}, "\x1b[34m1 |\x1b[0m package main\n\x1b[34m  |\x1b[0m         \x1b[1;31m^^^^\x1b[0m\n"},
+	}
+	for _, tt := range tests {
+		var b Builder
+		n, err := b.WriteSourceExcerptOptions(excerptSrc, tt.line, tt.col, tt.span, tt.opts)
+		if err != nil || n != len(tt.want) {
+			t.Errorf("WriteSourceExcerptOptions(%d, %d, %d, %+v): got %d,%v; want %d,nil", tt.line, tt.col, tt.span, tt.opts, n, err, len(tt.want))
+		}
+		check(t, &b, tt.want)
+	}
+
+	var b Builder
+	b.WriteSourceExcerptOptions("1\n2\n3\n4\n5\n6\n7\n8\n9\n10\n11", 9, 1, 1, ExcerptOptions{Context: 1})
+	check(t, &b, " 8 | 8\n 9 | 9\n   | ^\n10 | 10\n")
+
+	b.Reset()
+	b.SetLineEnding(CRLF)
+	b.WriteSourceExcerpt("a\r\nbcd", 2, 2, 1)
+	check(t, &b, "2 | bcd\r\n  |  ^\r\n")
+
+	for _, line := range []int{0, -5, 8, 100, math.MaxInt} {
+		var b Builder
+		if n, err := b.WriteSourceExcerpt(excerptSrc, line, 1, 1); n != 0 || err == nil {
+			t.Errorf("WriteSourceExcerpt(line %d): got %d,%v; want 0,error", line, n, err)
+		}
+		if n, err := b.WriteSourceExcerptOptions(excerptSrc, line, 1, 1, ExcerptOptions{Context: math.MaxInt}); n != 0 || err == nil {
+			t.Errorf("WriteSourceExcerptOptions(line %d, huge context): got %d,%v; want 0,error", line, n, err)
+		}
+		check(t, &b, "")
+	}
+
+	// A huge context shows the whole source.
+	b.Reset()
+	b.WriteSourceExcerptOptions("a\nb", 2, 1, 1, ExcerptOptions{Context: math.MaxInt})
+	check(t, &b, "1 | a\n2 | b\n  | ^\n")
+}
@@ -0,0 +1,199 @@
+// Copyright 2023 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package builder
+
+import (
+	"strconv"
+	"strings"
+)
+
+// An ExpandError reports a failed expansion by WriteExpand or
+// WriteExpandStrict.
+type ExpandError struct {
+	Offset int    // offset of the "$" of the failed expansion in the template
+	Name   string // variable being expanded, empty if there is none
+	Msg    string // description of the failure
+}
+
+func (e *ExpandError) Error() string {
+	s := "builder: expand at offset " + strconv.Itoa(e.Offset) + ": "
+	if e.Name != "" {
+		s += e.Name + ": "
+	}
+	return s + e.Msg
+}
+
+// WriteExpand appends s to b's buffer with variables replaced as by a POSIX
+// shell, using mapping to look up their values: $NAME and ${NAME} are
+// replaced by mapping(NAME), and "$$" by a single "$". A variable name is an
+// ASCII letter or underscore followed by ASCII letters, digits and
+// underscores; a "$" not followed by one, a brace or another "$" is written
+// as is. The forms with a word, which may itself contain expansions, are:
+//
+//	${NAME:-word}  the value, or the expansion of word if it is unset or empty
+//	${NAME-word}   the value, or the expansion of word if it is unset
+//	${NAME:?word}  the value, or an error with the expansion of word as its
+//	               message if it is unset or empty
+//	${NAME?word}   the value, or that error if it is unset
+//
+// WriteExpand treats every variable as set, so only the forms with a colon
+// ever use their word.
+// It returns the length of written. If s is malformed or an expansion fails,
+// b is left unchanged and an *ExpandError is returned.
+func (b *Builder) WriteExpand(s string, mapping func(string) string) (int, error) {
+	return b.writeExpand(s, func(name string) (string, bool) {
+		return mapping(name), true
+	}, false)
+}
+
+// WriteExpandStrict is like WriteExpand but looks up variables with lookup,
+// which reports whether a variable is set, as os.LookupEnv does. A variable
+// that is not set is an error, with its offset in s, unless a form with a
+// default word is used, rather than being written as empty.
+func (b *Builder) WriteExpandStrict(s string, lookup func(string) (string, bool)) (int, error) {
+	return b.writeExpand(s, lookup, true)
+}
+
+func (b *Builder) writeExpand(s string, lookup func(string) (string, bool), strict bool) (int, error) {
+	b.copyCheck()
+	n := len(b.buf)
+	buf, err := appendExpand(b.buf, s, 0, lookup, strict)
+	if err != nil {
+		b.rollback(n)
+		return 0, err
+	}
+	b.buf = buf
+	return b.commit(n), nil
+}
+
+// appendExpand appends the expansion of s, found at offset off in the
+// template, to dst.
+func appendExpand(dst []byte, s string, off int, lookup func(string) (string, bool), strict bool) ([]byte, error) {
+	for i := 0; i < len(s); {
+		j := strings.IndexByte(s[i:], '$')
+		if j < 0 {
+			return append(dst, s[i:]...), nil
+		}
+		dst = append(dst, s[i:i+j]...)
+		i += j
+		if i+1 == len(s) {
+			return append(dst, '$'), nil
+		}
+
+		switch c := s[i+1]; {
+		case c == '$':
+			dst = append(dst, '$')
+			i += 2
+		case c == '{':
+			end := closingBrace(s, i+2)
+			if end < 0 {
+				return dst, &ExpandError{off + i, "", "missing '}'"}
+			}
+			var err error
+			if dst, err = appendBraced(dst, s[i+2:end], off+i, lookup, strict); err != nil {
+				return dst, err
+			}
+			i = end + 1
+		case isNameStart(c):
+			k := 1 + nameLen(s[i+1:])
+			name := s[i+1 : i+k]
+			v, ok := lookup(name)
+			if !ok && strict {
+				return dst, &ExpandError{off + i, name, "not set"}
+			}
+			dst = append(dst, v...)
+			i += k
+		default:
+			dst = append(dst, '$')
+			i++
+		}
+	}
+	return dst, nil
+}
+
+// appendBraced appends the expansion of ${body}, found at offset off in the
+// template, to dst.
+func appendBraced(dst []byte, body string, off int, lookup func(string) (string, bool), strict bool) ([]byte, error) {
+	k := nameLen(body)
+	if k == 0 {
+		return dst, &ExpandError{off, "", "bad substitution " + strconv.Quote("${"+body+"}")}
+	}
+	name, op := body[:k], body[k:]
+	v, ok := lookup(name)
+	if op == "" {
+		if !ok && strict {
+			return dst, &ExpandError{off, name, "not set"}
+		}
+		return append(dst, v...), nil
+	}
+
+	colon := op[0] == ':'
+	if colon {
+		op = op[1:]
+	}
+	if op == "" || op[0] != '-' && op[0] != '?' {
+		return dst, &ExpandError{off, name, "bad substitution " + strconv.Quote("${"+body+"}")}
+	}
+	if ok && (v != "" || !colon) {
+		return append(dst, v...), nil
+	}
+
+	word := op[1:]
+	wordOff := off + 2 + len(body) - len(word)
+	if op[0] == '-' {
+		return appendExpand(dst, word, wordOff, lookup, strict)
+	}
+	msg, err := appendExpand(nil, word, wordOff, lookup, strict)
+	if err != nil {
+		return dst, err
+	}
+	if len(msg) == 0 {
+		msg = []byte("not set")
+		if colon {
+			msg = []byte("not set or empty")
+		}
+	}
+	return dst, &ExpandError{off, name, string(msg)}
+}
+
+// closingBrace returns the index in s of the '}' closing a "${" that ends
+// at i, skipping nested expansions, or -1 if there is none.
+func closingBrace(s string, i int) int {
+	depth := 0
+	for ; i < len(s); i++ {
+		switch s[i] {
+		case '$':
+			if i+1 < len(s) && (s[i+1] == '{' || s[i+1] == '$') {
+				if s[i+1] == '{' {
+					depth++
+				}
+				i++
+			}
+		case '}':
+			if depth == 0 {
+				return i
+			}
+			depth--
+		}
+	}
+	return -1
+}
+
+// nameLen returns the length of the variable name at the start of s,
+// or 0 if there is none.
+func nameLen(s string) int {
+	if s == "" || !isNameStart(s[0]) {
+		return 0
+	}
+	i := 1
+	for i < len(s) && (isNameStart(s[i]) || '0' <= s[i] && s[i] <= '9') {
+		i++
+	}
+	return i
+}
+
+func isNameStart(c byte) bool {
+	return 'a' <= c && c <= 'z' || 'A' <= c && c <= 'Z' || c == '_'
+}
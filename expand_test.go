@@ -0,0 +1,99 @@
+// Copyright 2023 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package builder_test
+
+import (
+	"errors"
+	"testing"
+
+	. "github.com/weiwenchen2022/builder"
+)
+
+var expandEnv = map[string]string{
+	"HOME":  "/home/gopher",
+	"USER":  "gopher",
+	"EMPTY": "",
+	"_x1":   "one",
+}
+
+func lookupEnv(name string) (string, bool) {
+	v, ok := expandEnv[name]
+	return v, ok
+}
+
+func TestBuilderWriteExpandStrict(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		in, want string
+	}{
+		{"", ""},
+		{"plain text", "plain text"},
+		{"$HOME/bin", "/home/gopher/bin"},
+		{"${USER}s", "gophers"},
+		{"$_x1$USER", "onegopher"},
+		{"cost: $$5, $ 1, $1, end$", "cost: $5, $ 1, $1, end$"},
+		{"${EMPTY:-none}", "none"},
+		{"${EMPTY-none}", ""},
+		{"${MISSING-none}", "none"},
+		{"${MISSING:-$HOME/${USER}}", "/home/gopher/gopher"},
+		{"${MISSING:-${OTHER:-x}}y", "xy"},
+		{"${USER:?who?}", "gopher"},
+		{"${EMPTY?unset}", ""},
+		{"${MISSING:-a}}", "a}"},
+	}
+	for _, tt := range tests {
+		var b Builder
+		n, err := b.WriteExpandStrict(tt.in, lookupEnv)
+		if err != nil || n != len(tt.want) {
+			t.Errorf("WriteExpandStrict(%q): got %d,%v; want %d,nil", tt.in, n, err, len(tt.want))
+		}
+		check(t, &b, tt.want)
+	}
+}
+
+func TestBuilderWriteExpandStrictError(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		in   string
+		want ExpandError
+	}{
+		{"x $MISSING", ExpandError{2, "MISSING", "not set"}},
+		{"${MISSING}", ExpandError{0, "MISSING", "not set"}},
+		{"ab${EMPTY:?need a value for $USER}", ExpandError{2, "EMPTY", "need a value for gopher"}},
+		{"${MISSING?}", ExpandError{0, "MISSING", "not set"}},
+		{"${EMPTY:?}", ExpandError{0, "EMPTY", "not set or empty"}},
+		{"${X:-${MISSING}}", ExpandError{5, "MISSING", "not set"}},
+		{"${X:-12$MISSING}", ExpandError{7, "MISSING", "not set"}},
+		{"a ${HOME", ExpandError{2, "", "missing '}'"}},
+		{"${1x}", ExpandError{0, "", `bad substitution "${1x}"`}},
+		{"${HOME:+x}", ExpandError{0, "HOME", `bad substitution "${HOME:+x}"`}},
+	}
+	for _, tt := range tests {
+		var b Builder
+		b.WriteString("keep")
+		n, err := b.WriteExpandStrict(tt.in, lookupEnv)
+		var e *ExpandError
+		if !errors.As(err, &e) || n != 0 || *e != tt.want {
+			t.Errorf("WriteExpandStrict(%q): got %d,%v; want 0,%v", tt.in, n, err, &tt.want)
+		}
+		check(t, &b, "keep")
+	}
+}
+
+func TestBuilderWriteExpand(t *testing.T) {
+	t.Parallel()
+
+	var b Builder
+	n, err := b.WriteExpand("$USER:$MISSING:${EMPTY:-d}:${MISSING-d}", func(name string) string {
+		return expandEnv[name]
+	})
+	want := "gopher::d:"
+	if err != nil || n != len(want) {
+		t.Errorf("WriteExpand: got %d,%v; want %d,nil", n, err, len(want))
+	}
+	check(t, &b, want)
+}
@@ -0,0 +1,82 @@
+// Copyright 2023 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package builder
+
+import (
+	"errors"
+	"unsafe"
+)
+
+// ErrFull is reported when a write does not fit in the buffer of a Builder
+// created by NewFixed.
+var ErrFull = errors.New("builder: fixed buffer full")
+
+// NewFixed returns a Builder that builds into buf[:cap(buf)] and never grows
+// beyond it, for contexts where allocation is unacceptable. The content of
+// buf is ignored.
+//
+// A write that does not fit leaves the content unchanged. Write, WriteByte,
+// WriteRune, WriteString and the methods that know the length of their output
+// in advance, such as WriteByteN, WriteRepeat, WriteJoin, WriteRunes, WriteOps
+// and the hex, base-N and fixed-size binary encoders, check for room first
+// and return ErrFull. The other write methods return a count of zero and
+// record ErrFull, which Err reports. Those may allocate a temporary buffer
+// before discarding the write, so callers that must not allocate at all
+// should leave room for them.
+//
+// Grow does nothing on a fixed Builder, and UnmarshalText and GobDecode copy
+// into buf, returning ErrFull if the data does not fit.
+//
+// The string returned by String aliases buf: it is only valid until buf is
+// modified or b is Reset. Reset empties b but keeps it building into buf.
+func NewFixed(buf []byte) *Builder {
+	b := &Builder{buf: buf[:0], mode: &mode{fixed: buf[:0]}}
+	b.copyCheck()
+	return b
+}
+
+// full reports whether b is fixed and has no room for k more bytes,
+// recording ErrFull if so.
+func (b *Builder) full(k int) bool {
+	if b.mode == nil || b.mode.fixed == nil || cap(b.buf)-len(b.buf) >= k {
+		return false
+	}
+	b.mode.full = true
+	return true
+}
+
+// reserve prepares b for a write of k bytes, growing it if needed. It reports
+// false, recording ErrFull, if b is fixed and has no room for them.
+func (b *Builder) reserve(k int) bool {
+	b.copyCheck()
+	if b.full(k) {
+		return false
+	}
+	if cap(b.buf)-len(b.buf) < k {
+		b.grow(k)
+	}
+	return true
+}
+
+// rollback discards a failed write that started at offset n. The write may
+// have moved a fixed Builder off its buffer, so it is restored from there.
+func (b *Builder) rollback(n int) {
+	if m := b.mode; m != nil && m.fixed != nil {
+		b.buf = m.fixed[:n]
+		return
+	}
+	b.buf = b.buf[:n]
+}
+
+// overflowed reports whether a write that started at offset n outgrew the
+// fixed buffer, in which case it discards the write and records ErrFull.
+func (m *mode) overflowed(b *Builder, n int) bool {
+	if m.fixed == nil || unsafe.SliceData(b.buf) == unsafe.SliceData(m.fixed) {
+		return false
+	}
+	b.buf = m.fixed[:n]
+	m.full = true
+	return true
+}
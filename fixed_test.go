@@ -0,0 +1,234 @@
+// Copyright 2023 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package builder_test
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	. "github.com/weiwenchen2022/builder"
+)
+
+func TestNewFixed(t *testing.T) {
+	t.Parallel()
+
+	buf := make([]byte, 3, 16)
+	b := NewFixed(buf)
+	check(t, b, "")
+	if b.Cap() != 16 {
+		t.Errorf("Cap: got %d; want 16", b.Cap())
+	}
+
+	b.WriteString("hello, ")
+	b.WriteByte('w')
+	b.WriteRune('ö')
+	b.Write([]byte("rld"))
+	check(t, b, "hello, wörld")
+	if err := b.Err(); err != nil {
+		t.Errorf("Err: got %v; want nil", err)
+	}
+	if string(buf[:b.Len()]) != b.String() {
+		t.Errorf("buf: got %q; want %q", buf[:b.Len()], b.String())
+	}
+
+	if n, err := b.WriteString("!!!!"); n != 0 || err != ErrFull {
+		t.Errorf("WriteString: got %d,%v; want 0,ErrFull", n, err)
+	}
+	if n, err := b.Write([]byte("!!!!")); n != 0 || err != ErrFull {
+		t.Errorf("Write: got %d,%v; want 0,ErrFull", n, err)
+	}
+	if n, err := b.WriteRune('\U0001F600'); n != 0 || err != ErrFull {
+		t.Errorf("WriteRune: got %d,%v; want 0,ErrFull", n, err)
+	}
+	if n, err := b.WriteInt(12345, 10); n != 0 || err != nil {
+		t.Errorf("WriteInt: got %d,%v; want 0,nil", n, err)
+	}
+	check(t, b, "hello, wörld")
+	if b.Cap() != 16 {
+		t.Errorf("Cap after overflow: got %d; want 16", b.Cap())
+	}
+	if err := b.Err(); !errors.Is(err, ErrFull) {
+		t.Errorf("Err: got %v; want ErrFull", err)
+	}
+
+	// Writes that fit still succeed after one that did not.
+	b.WriteString("!!!")
+	if err := b.WriteByte('!'); err == nil {
+		t.Error("WriteByte: got nil error; want ErrFull")
+	}
+	check(t, b, "hello, wörld!!!")
+
+	b.Reset()
+	check(t, b, "")
+	if err := b.Err(); err != nil {
+		t.Errorf("Err after Reset: got %v; want nil", err)
+	}
+	fmt.Fprintf(b, "%d-%s", 42, "x")
+	check(t, b, "42-x")
+	if b.Cap() != 16 {
+		t.Errorf("Cap after Reset: got %d; want 16", b.Cap())
+	}
+}
+
+func TestNewFixedModes(t *testing.T) {
+	t.Parallel()
+
+	b := NewFixed(make([]byte, 0, 8))
+	b.SetSep(", ")
+	b.WriteString("ab")
+	b.WriteString("cd")
+	// The separator and "ef" would need 10 bytes.
+	if n, err := b.WriteString("ef"); n != 0 || err != ErrFull {
+		t.Errorf("WriteString: got %d,%v; want 0,ErrFull", n, err)
+	}
+	check(t, b, "ab, cd")
+}
+
+func TestNewFixedGrow(t *testing.T) {
+	t.Parallel()
+
+	b := NewFixed(make([]byte, 0, 16))
+	b.Grow(32)
+	if b.Cap() != 16 {
+		t.Errorf("Cap after Grow: got %d; want 16", b.Cap())
+	}
+	if n, err := b.WriteString("hi"); n != 2 || err != nil {
+		t.Errorf("WriteString after Grow: got %d,%v; want 2,nil", n, err)
+	}
+	check(t, b, "hi")
+}
+
+func TestNewFixedSized(t *testing.T) {
+	tests := []struct {
+		name string
+		fn   func(b *Builder) (int, error)
+	}{
+		{"WriteByteN", func(b *Builder) (int, error) { return b.WriteByteN('-', 8) }},
+		{"WriteRepeat", func(b *Builder) (int, error) { return b.WriteRepeat("ab", 4) }},
+		{"WriteJoin", func(b *Builder) (int, error) { return b.WriteJoin(",", "abcd", "efgh") }},
+		{"WriteRunes", func(b *Builder) (int, error) { return b.WriteRunes([]rune("abcdefgh")) }},
+		{"WriteHex", func(b *Builder) (int, error) { return b.WriteHex([]byte("abcd")) }},
+		{"WriteBase32", func(b *Builder) (int, error) { return b.WriteBase32([]byte("abc"), nil) }},
+		{"WriteBase64", func(b *Builder) (int, error) { return b.WriteBase64([]byte("abcdef"), nil) }},
+		{"WriteBase58", func(b *Builder) (int, error) { return b.WriteBase58([]byte("abcdef")) }},
+		{"WriteBinaryUint64", func(b *Builder) (int, error) { return b.WriteBinaryUint64(1, binary.BigEndian) }},
+	}
+	for _, tt := range tests {
+		b := NewFixed(make([]byte, 0, 8))
+		b.WriteString("x")
+		allocs := testing.AllocsPerRun(1, func() {
+			if n, err := tt.fn(b); n != 0 || err != ErrFull {
+				t.Errorf("%s: got %d,%v; want 0,ErrFull", tt.name, n, err)
+			}
+		})
+		if allocs != 0 {
+			t.Errorf("%s allocs = %v; want 0", tt.name, allocs)
+		}
+		check(t, b, "x")
+		if b.Cap() != 8 {
+			t.Errorf("%s: Cap got %d; want 8", tt.name, b.Cap())
+		}
+	}
+}
+
+func TestNewFixedUnmarshalText(t *testing.T) {
+	t.Parallel()
+
+	buf := make([]byte, 0, 8)
+	b := NewFixed(buf)
+	if err := b.UnmarshalText([]byte("hello")); err != nil {
+		t.Fatal(err)
+	}
+	check(t, b, "hello")
+	if string(buf[:5]) != "hello" {
+		t.Errorf("buf: got %q; want %q", buf[:5], "hello")
+	}
+	b.WriteString("!")
+	check(t, b, "hello!")
+
+	if err := b.UnmarshalText([]byte("hello world")); err != ErrFull {
+		t.Errorf("UnmarshalText: got %v; want ErrFull", err)
+	}
+	check(t, b, "hello!")
+	b.WriteString("x")
+	check(t, b, "hello!x")
+	if b.Cap() != 8 {
+		t.Errorf("Cap: got %d; want 8", b.Cap())
+	}
+}
+
+func TestNewFixedRollback(t *testing.T) {
+	t.Parallel()
+
+	// A failed write that outgrew the buffer before failing must leave b
+	// building into it, with no ErrFull recorded.
+	buf := make([]byte, 0, 8)
+	b := NewFixed(buf)
+	b.WriteString("ab")
+	long := strings.Repeat("x", 16)
+	if _, err := MustFragment("%s%d").Write(b, long, "not a number"); err == nil {
+		t.Error("Fragment.Write with bad argument: got nil error")
+	}
+	if _, err := b.WriteKV(long, badText{}); err == nil {
+		t.Error("WriteKV with failing MarshalText: got nil error")
+	}
+	if n, err := b.WriteString("cd"); n != 2 || err != nil {
+		t.Errorf("WriteString after failed writes: got %d,%v; want 2,nil", n, err)
+	}
+	if err := b.Err(); err != nil {
+		t.Errorf("Err: got %v; want nil", err)
+	}
+	check(t, b, "abcd")
+	if string(buf[:4]) != "abcd" {
+		t.Errorf("buf: got %q; want %q", buf[:4], "abcd")
+	}
+}
+
+func TestNewFixedRebuildFrom(t *testing.T) {
+	t.Parallel()
+
+	buf := make([]byte, 0, 64)
+	b := NewFixed(buf)
+	b.WriteString("frame 1: ok\n")
+	prev := b.Snapshot()
+	b.RebuildFrom(prev)
+	b.WriteString("frame 2: ok\n")
+	if got, want := b.Unchanged(), len("frame "); got != want {
+		t.Errorf("Unchanged: got %d; want %d", got, want)
+	}
+	if got, want := b.ChangedTail(), "2: ok\n"; got != want {
+		t.Errorf("ChangedTail: got %q; want %q", got, want)
+	}
+	if n, err := b.WriteString("more"); n != 4 || err != nil {
+		t.Errorf("WriteString after RebuildFrom: got %d,%v; want 4,nil", n, err)
+	}
+	if err := b.Err(); err != nil {
+		t.Errorf("Err: got %v; want nil", err)
+	}
+	if string(buf[:b.Len()]) != b.String() {
+		t.Errorf("buf: got %q; want %q", buf[:b.Len()], b.String())
+	}
+	if b.Cap() != 64 {
+		t.Errorf("Cap: got %d; want 64", b.Cap())
+	}
+}
+
+func TestNewFixedAllocs(t *testing.T) {
+	b := NewFixed(make([]byte, 0, 64))
+	allocs := testing.AllocsPerRun(100, func() {
+		b.Reset()
+		b.WriteString("status=")
+		b.WriteInt(200, 10)
+		b.WriteByte(' ')
+		b.WriteRune('✓')
+		b.WriteString("this does not fit in the sixty-four byte buffer at all")
+	})
+	if allocs != 0 {
+		t.Errorf("NewFixed allocs = %v; want 0", allocs)
+	}
+}
@@ -0,0 +1,83 @@
+// Copyright 2023 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package builder
+
+import (
+	"math"
+	"strconv"
+)
+
+// A FloatPolicy overrides how a Builder formats floating-point numbers,
+// so that output used as a cache key or signed payload does not depend on
+// the formats chosen at each call site. The zero FloatPolicy changes nothing.
+type FloatPolicy struct {
+	// Fmt and Prec, if Fmt is non-zero, replace the format and precision
+	// requested by the caller, as in strconv.FormatFloat. For example, Fmt 'g'
+	// with Prec -1 always writes the shortest representation that round-trips.
+	Fmt  byte
+	Prec int
+
+	// NormalizeZero writes negative zero as positive zero.
+	NormalizeZero bool
+
+	// NaN, PosInf and NegInf, if not empty, are written in place of NaN,
+	// positive infinity and negative infinity.
+	NaN, PosInf, NegInf string
+}
+
+// SetFloatPolicy sets the policy applied to the floating-point numbers
+// written by WriteFloat, WriteComplex, WriteBigFloat, WriteOps, WriteKV,
+// Fragment verbs, CSVTable float columns, WriteGeoJSONPoint and WriteLatLon
+// in decimal degrees. WriteSI applies only the replacements and
+// NormalizeZero, as it picks its digits by its own precision rules.
+//
+// A few writers are exempt because their format already fixes a single
+// representation of each number: the JSON writers follow RFC 8785, whose
+// output would no longer be canonical JSON; WriteHumanBytes formats an
+// integer count; and WriteLatLon in degrees, minutes and seconds derives
+// every field from the seconds rounded to prec digits.
+//
+// The zero FloatPolicy restores the default formatting.
+func (b *Builder) SetFloatPolicy(p FloatPolicy) {
+	if p == (FloatPolicy{}) {
+		if b.mode != nil {
+			b.mode.floats = nil
+		}
+		return
+	}
+	if b.mode == nil {
+		b.mode = new(mode)
+	}
+	b.mode.floats = &p
+}
+
+// appendFloat is like strconv.AppendFloat but applies b's FloatPolicy.
+func (b *Builder) appendFloat(dst []byte, f float64, fmt byte, prec, bitSize int) []byte {
+	if b.mode != nil && b.mode.floats != nil && b.mode.floats.Fmt != 0 {
+		fmt, prec = b.mode.floats.Fmt, b.mode.floats.Prec
+	}
+	return b.appendFloatValue(dst, f, fmt, prec, bitSize)
+}
+
+// appendFloatValue is like appendFloat but keeps fmt and prec, applying only
+// the replacements and NormalizeZero of b's FloatPolicy.
+func (b *Builder) appendFloatValue(dst []byte, f float64, fmt byte, prec, bitSize int) []byte {
+	if b.mode == nil || b.mode.floats == nil {
+		return strconv.AppendFloat(dst, f, fmt, prec, bitSize)
+	}
+
+	p := b.mode.floats
+	switch {
+	case math.IsNaN(f) && p.NaN != "":
+		return append(dst, p.NaN...)
+	case math.IsInf(f, 1) && p.PosInf != "":
+		return append(dst, p.PosInf...)
+	case math.IsInf(f, -1) && p.NegInf != "":
+		return append(dst, p.NegInf...)
+	case f == 0 && p.NormalizeZero:
+		f = 0
+	}
+	return strconv.AppendFloat(dst, f, fmt, prec, bitSize)
+}
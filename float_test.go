@@ -0,0 +1,98 @@
+// Copyright 2023 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package builder_test
+
+import (
+	"math"
+	"math/big"
+	"testing"
+
+	. "github.com/weiwenchen2022/builder"
+)
+
+func TestBuilderSetFloatPolicy(t *testing.T) {
+	t.Parallel()
+
+	negZero := math.Copysign(0, -1)
+	tests := []struct {
+		policy FloatPolicy
+		f      float64
+		fmt    byte
+		prec   int
+		want   string
+	}{
+		{FloatPolicy{}, 0.1, 'f', 3, "0.100"},
+		{FloatPolicy{}, negZero, 'g', -1, "-0"},
+		{FloatPolicy{Fmt: 'g', Prec: -1}, 0.1, 'f', 3, "0.1"},
+		{FloatPolicy{Fmt: 'f', Prec: 2}, 1.0 / 3, 'g', -1, "0.33"},
+		{FloatPolicy{NormalizeZero: true}, negZero, 'g', -1, "0"},
+		{FloatPolicy{NormalizeZero: true}, -1e-300, 'f', 2, "-0.00"},
+		{FloatPolicy{NaN: "NaN!"}, math.NaN(), 'g', -1, "NaN!"},
+		{FloatPolicy{PosInf: "inf", NegInf: "-inf"}, math.Inf(1), 'g', -1, "inf"},
+		{FloatPolicy{PosInf: "inf", NegInf: "-inf"}, math.Inf(-1), 'g', -1, "-inf"},
+		{FloatPolicy{PosInf: "inf"}, math.Inf(-1), 'g', -1, "-Inf"},
+	}
+
+	for _, tt := range tests {
+		var b Builder
+		b.SetFloatPolicy(tt.policy)
+		n, err := b.WriteFloat(tt.f, tt.fmt, tt.prec, 64)
+		if err != nil || len(tt.want) != n {
+			t.Errorf("%+v: WriteFloat(%v, %c, %d): got %d,%v; want %d,nil", tt.policy, tt.f, tt.fmt, tt.prec, n, err, len(tt.want))
+		}
+		check(t, &b, tt.want)
+	}
+}
+
+func TestBuilderFloatPolicyHigherLevelWriters(t *testing.T) {
+	t.Parallel()
+
+	var b Builder
+	b.SetFloatPolicy(FloatPolicy{Fmt: 'f', Prec: 1, NormalizeZero: true})
+
+	var ops WriteOps
+	ops.AddFloat(2.25, 'e', -1, 64)
+	b.WriteOps(&ops)
+	b.WriteByte(' ')
+	MustFragment("%v|%e").Write(&b, math.Copysign(0, -1), 1.04)
+	b.WriteByte(' ')
+	b.WriteKV("ratio", 0.75)
+	b.WriteByte(' ')
+	b.WriteComplex(complex(1.25, math.Copysign(0, -1)), 'e', -1, 128)
+
+	check(t, &b, "2.2 0.0|1.0 ratio=0.8 (1.2+0.0i)")
+
+	b.SetFloatPolicy(FloatPolicy{})
+	b.WriteByte(' ')
+	b.WriteFloat(0.75, 'g', -1, 64)
+	check(t, &b, "2.2 0.0|1.0 ratio=0.8 (1.2+0.0i) 0.75")
+}
+
+func TestBuilderFloatPolicyFormatWriters(t *testing.T) {
+	t.Parallel()
+
+	var b Builder
+	b.SetFloatPolicy(FloatPolicy{Fmt: 'g', Prec: -1, NormalizeZero: true, NaN: "nan", PosInf: "inf", NegInf: "-inf"})
+
+	b.WriteLatLon(48.5, math.Copysign(0, -1), DecimalDegrees, 3)
+	b.WriteByte(' ')
+	b.WriteGeoJSONPoint(1.25, 2, 6)
+	b.WriteByte(' ')
+	b.WriteBigFloat(big.NewFloat(0.5), 'e', 3)
+	b.WriteByte(' ')
+	b.WriteBigFloat(big.NewFloat(math.Inf(-1)), 'g', -1)
+	b.WriteByte(' ')
+	b.WriteBigFloat(new(big.Float).Neg(new(big.Float)), 'f', 2)
+	b.WriteByte(' ')
+	b.WriteSI(math.NaN(), "V", 2)
+	b.WriteByte(' ')
+	b.WriteSI(math.Copysign(0, -1), "V", 2)
+	b.WriteByte(' ')
+	b.WriteSI(1500, "V", 3)
+	b.WriteByte('\n')
+	b.CSVTable(CSVColumn{Name: "x", Kind: CSVFloat, Scale: 2}).WriteRow(0.25)
+
+	check(t, &b, `48.5, 0 {"type":"Point","coordinates":[2,1.25]} 0.5 -inf 0 nan V 0 V 1.50 kV`+"\n0.25\n")
+}
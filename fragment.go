@@ -0,0 +1,314 @@
+// Copyright 2023 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package builder
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"sync"
+	"unicode/utf8"
+)
+
+// A Fragment is a compiled format string. Its Write method appends formatted
+// arguments to a Builder with typed writes rather than through package fmt,
+// and without parsing the format again.
+//
+// Formats may contain literal text and the verbs %v, %s, %q, %d, %x, %X, %t,
+// %c, %e, %f and %g, as well as %% for a literal percent sign. Flags, widths
+// and precisions are not supported.
+//
+// A Fragment is safe for concurrent use by multiple goroutines.
+type Fragment struct {
+	format string
+	segs   []fragmentSeg
+}
+
+// A fragmentSeg is literal text followed by a verb, if verb is not zero.
+type fragmentSeg struct {
+	lit  string
+	verb byte
+}
+
+var fragments sync.Map // map[string]*Fragment
+
+// CompileFragment compiles format into a Fragment. Compiled fragments are
+// cached, so compiling the same format again returns the same Fragment
+// without parsing it.
+func CompileFragment(format string) (*Fragment, error) {
+	if f, ok := fragments.Load(format); ok {
+		return f.(*Fragment), nil
+	}
+
+	f := &Fragment{format: format}
+	var lit []byte
+	for i := 0; i < len(format); i++ {
+		c := format[i]
+		if c != '%' {
+			lit = append(lit, c)
+			continue
+		}
+		if i++; i == len(format) {
+			return nil, errors.New("builder: fragment " + strconv.Quote(format) + " ends with %")
+		}
+		switch verb := format[i]; verb {
+		case '%':
+			lit = append(lit, '%')
+		case 'v', 's', 'q', 'd', 'x', 'X', 't', 'c', 'e', 'f', 'g':
+			f.segs = append(f.segs, fragmentSeg{string(lit), verb})
+			lit = lit[:0]
+		default:
+			return nil, errors.New("builder: fragment " + strconv.Quote(format) + " has unsupported verb %" + string(rune(verb)))
+		}
+	}
+	if len(lit) > 0 {
+		f.segs = append(f.segs, fragmentSeg{lit: string(lit)})
+	}
+
+	actual, _ := fragments.LoadOrStore(format, f)
+	return actual.(*Fragment), nil
+}
+
+// MustFragment is like CompileFragment but panics if the format cannot be
+// compiled. It simplifies safe initialization of global variables holding
+// compiled fragments.
+func MustFragment(format string) *Fragment {
+	f, err := CompileFragment(format)
+	if err != nil {
+		panic(err)
+	}
+	return f
+}
+
+// String returns the format the fragment was compiled from.
+func (f *Fragment) String() string { return f.format }
+
+// Write appends the fragment's literal text and args, formatted according to
+// the corresponding verbs, to b's buffer.
+// It returns the length of written. If the number of args does not match the
+// number of verbs, or an argument is not valid for its verb, b is left
+// unchanged and an error is returned.
+func (f *Fragment) Write(b *Builder, args ...any) (int, error) {
+	b.copyCheck()
+	n := len(b.buf)
+	next := 0
+	for _, seg := range f.segs {
+		b.buf = append(b.buf, seg.lit...)
+		if seg.verb == 0 {
+			continue
+		}
+		if next == len(args) {
+			b.rollback(n)
+			return 0, fmt.Errorf("builder: fragment %q: missing argument for verb %%%c", f.format, seg.verb)
+		}
+
+		var ok bool
+		if b.buf, ok = b.appendArg(b.buf, seg.verb, args[next]); !ok {
+			b.rollback(n)
+			return 0, fmt.Errorf("builder: fragment %q: bad argument of type %T for verb %%%c", f.format, args[next], seg.verb)
+		}
+		next++
+	}
+	if next < len(args) {
+		b.rollback(n)
+		return 0, fmt.Errorf("builder: fragment %q: %d extra arguments", f.format, len(args)-next)
+	}
+	return b.commit(n), nil
+}
+
+// appendArg appends arg formatted according to verb, and to b's FloatPolicy
+// for floating-point numbers, to dst. It reports whether arg could be
+// formatted with verb.
+func (b *Builder) appendArg(dst []byte, verb byte, arg any) ([]byte, bool) {
+	switch v := arg.(type) {
+	case nil:
+		return append(dst, "<nil>"...), verb == 'v' || verb == 's'
+	case string:
+		return appendStringArg(dst, verb, v)
+	case []byte:
+		return appendStringArg(dst, verb, string(v))
+	case bool:
+		return strconv.AppendBool(dst, v), verb == 'v' || verb == 't'
+	case int:
+		return appendIntArg(dst, verb, int64(v))
+	case int8:
+		return appendIntArg(dst, verb, int64(v))
+	case int16:
+		return appendIntArg(dst, verb, int64(v))
+	case int32:
+		if verb == 'c' {
+			return utf8.AppendRune(dst, v), true
+		}
+		return appendIntArg(dst, verb, int64(v))
+	case int64:
+		return appendIntArg(dst, verb, v)
+	case uint:
+		return appendUintArg(dst, verb, uint64(v))
+	case uint8:
+		return appendUintArg(dst, verb, uint64(v))
+	case uint16:
+		return appendUintArg(dst, verb, uint64(v))
+	case uint32:
+		return appendUintArg(dst, verb, uint64(v))
+	case uint64:
+		return appendUintArg(dst, verb, v)
+	case uintptr:
+		return appendUintArg(dst, verb, uint64(v))
+	case float32:
+		return b.appendFloatArg(dst, verb, float64(v), 32)
+	case float64:
+		return b.appendFloatArg(dst, verb, v, 64)
+	case error:
+		return appendStringArg(dst, verb, v.Error())
+	case fmt.Stringer:
+		return appendStringArg(dst, verb, v.String())
+	}
+	if verb != 'v' {
+		return dst, false
+	}
+	return fmt.Append(dst, arg), true
+}
+
+func appendStringArg(dst []byte, verb byte, s string) ([]byte, bool) {
+	switch verb {
+	case 'v', 's':
+		return append(dst, s...), true
+	case 'q':
+		return strconv.AppendQuote(dst, s), true
+	case 'x', 'X':
+		return appendHex(dst, s, verb == 'X'), true
+	}
+	return dst, false
+}
+
+func appendHex(dst []byte, s string, upper bool) []byte {
+	digits := "0123456789abcdef"
+	if upper {
+		digits = "0123456789ABCDEF"
+	}
+	for i := 0; i < len(s); i++ {
+		dst = append(dst, digits[s[i]>>4], digits[s[i]&0xF])
+	}
+	return dst
+}
+
+func appendIntArg(dst []byte, verb byte, i int64) ([]byte, bool) {
+	switch verb {
+	case 'v', 'd':
+		return strconv.AppendInt(dst, i, 10), true
+	case 'x':
+		return strconv.AppendInt(dst, i, 16), true
+	case 'X':
+		return upperHex(strconv.AppendInt(dst, i, 16), len(dst)), true
+	case 'c':
+		return utf8.AppendRune(dst, rune(i)), true
+	case 'q':
+		return strconv.AppendQuoteRune(dst, rune(i)), true
+	}
+	return dst, false
+}
+
+func appendUintArg(dst []byte, verb byte, i uint64) ([]byte, bool) {
+	switch verb {
+	case 'v', 'd':
+		return strconv.AppendUint(dst, i, 10), true
+	case 'x':
+		return strconv.AppendUint(dst, i, 16), true
+	case 'X':
+		return upperHex(strconv.AppendUint(dst, i, 16), len(dst)), true
+	case 'c':
+		return utf8.AppendRune(dst, rune(i)), true
+	case 'q':
+		return strconv.AppendQuoteRune(dst, rune(i)), true
+	}
+	return dst, false
+}
+
+// upperHex converts the lower-case hex digits in dst[n:] to upper case.
+func upperHex(dst []byte, n int) []byte {
+	for i := n; i < len(dst); i++ {
+		if c := dst[i]; 'a' <= c && c <= 'f' {
+			dst[i] = c - 'a' + 'A'
+		}
+	}
+	return dst
+}
+
+func (b *Builder) appendFloatArg(dst []byte, verb byte, f float64, bitSize int) ([]byte, bool) {
+	switch verb {
+	case 'v', 'g':
+		return b.appendFloat(dst, f, 'g', -1, bitSize), true
+	case 'e', 'f':
+		return b.appendFloat(dst, f, verb, 6, bitSize), true
+	}
+	return dst, false
+}
+
+// Segments is a template split into static text and the holes between it,
+// as returned by Split. Its WriteWith method interleaves the precomputed text
+// with typed writes of the arguments.
+//
+// Segments is safe for concurrent use by multiple goroutines.
+type Segments struct {
+	template string
+	static   []string // text around the holes; one more than the holes
+}
+
+// Split splits template at each hole "{}", as in
+//
+//	var query = builder.Split("SELECT * FROM t WHERE id = {} AND name = {}")
+//
+// "{{" and "}}" stand for literal braces; any other brace is literal text.
+// Split does the parsing once, typically when a package variable is
+// initialized.
+func Split(template string) *Segments {
+	s := &Segments{template: template}
+	var lit []byte
+	for i := 0; i < len(template); i++ {
+		c := template[i]
+		if i+1 < len(template) {
+			switch template[i : i+2] {
+			case "{}":
+				s.static = append(s.static, string(lit))
+				lit = lit[:0]
+				i++
+				continue
+			case "{{", "}}":
+				i++
+			}
+		}
+		lit = append(lit, c)
+	}
+	s.static = append(s.static, string(lit))
+	return s
+}
+
+// String returns the template s was split from.
+func (s *Segments) String() string { return s.template }
+
+// NumArgs returns the number of holes in the template, which is the number of
+// arguments WriteWith expects.
+func (s *Segments) NumArgs() int { return len(s.static) - 1 }
+
+// WriteWith appends the template to b's buffer with each hole replaced by the
+// corresponding argument, formatted as by the %v verb of a Fragment: strings,
+// booleans and numbers are written with typed writes, and other values with
+// package fmt.
+// It returns the length of written. If the number of args does not match the
+// number of holes, b is left unchanged and an error is returned.
+func (s *Segments) WriteWith(b *Builder, args ...any) (int, error) {
+	if len(args) != s.NumArgs() {
+		return 0, fmt.Errorf("builder: template %q: got %d arguments, want %d", s.template, len(args), s.NumArgs())
+	}
+
+	b.copyCheck()
+	n := len(b.buf)
+	b.buf = append(b.buf, s.static[0]...)
+	for i, arg := range args {
+		b.buf, _ = b.appendArg(b.buf, 'v', arg)
+		b.buf = append(b.buf, s.static[i+1]...)
+	}
+	return b.commit(n), nil
+}
@@ -0,0 +1,140 @@
+// Copyright 2023 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package builder_test
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	. "github.com/weiwenchen2022/builder"
+)
+
+func TestFragment(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		format string
+		args   []any
+	}{
+		{"user=%s id=%d", []any{"gopher", 42}},
+		{"no verbs", nil},
+		{"100%% of %v", []any{3.5}},
+		{"%q %x %X", []any{"hi", "hi", uint16(0xbeef)}},
+		{"%x %X %d", []any{-42, 255, uint8(7)}},
+		{"%t %v %c %c", []any{true, false, 'é', byte('a')}},
+		{"%e %f %g %v", []any{1234.5678, 1234.5678, float32(0.1), 1e21}},
+		{"%v %s %v", []any{nil, errors.New("boom"), time.Second}},
+		{"%v", []any{[]int{1, 2}}},
+		{"%s", []any{[]byte("bytes")}},
+	}
+
+	for _, tt := range tests {
+		f := MustFragment(tt.format)
+		if f.String() != tt.format {
+			t.Errorf("String: got %q; want %q", f.String(), tt.format)
+		}
+
+		want := fmt.Sprintf(tt.format, tt.args...)
+		var b Builder
+		n, err := f.Write(&b, tt.args...)
+		if err != nil || len(want) != n {
+			t.Errorf("%q.Write: got %d,%v; want %d,nil", tt.format, n, err, len(want))
+		}
+		check(t, &b, want)
+	}
+}
+
+func TestFragmentCache(t *testing.T) {
+	t.Parallel()
+
+	if MustFragment("cached %d") != MustFragment("cached %d") {
+		t.Error("MustFragment did not return the cached fragment")
+	}
+}
+
+func TestFragmentErrors(t *testing.T) {
+	t.Parallel()
+
+	for _, format := range []string{"trailing %", "width %5d", "%w"} {
+		if _, err := CompileFragment(format); err == nil {
+			t.Errorf("CompileFragment(%q): got nil error", format)
+		}
+	}
+
+	func() {
+		defer func() {
+			if recover() == nil {
+				t.Error("MustFragment with a bad format should panic")
+			}
+		}()
+		MustFragment("%y")
+	}()
+
+	f := MustFragment("a=%d b=%d")
+	for _, args := range [][]any{{1}, {1, 2, 3}, {1, "two"}} {
+		var b Builder
+		b.WriteString("x")
+		if _, err := f.Write(&b, args...); err == nil {
+			t.Errorf("Write(%v): got nil error", args)
+		}
+		check(t, &b, "x")
+	}
+}
+
+func TestSplit(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		template string
+		args     []any
+		want     string
+	}{
+		{"SELECT * FROM t WHERE id = {} AND name = {}", []any{42, "gopher"}, "SELECT * FROM t WHERE id = 42 AND name = gopher"},
+		{"no holes", nil, "no holes"},
+		{"", nil, ""},
+		{"{}", []any{true}, "true"},
+		{"{}{}", []any{1.5, uint8(7)}, "1.57"},
+		{"{{}} {{{}}} { } }{", []any{nil}, "{} {<nil>} { } }{"},
+		{"{} {}", []any{errors.New("boom"), []int{1, 2}}, "boom [1 2]"},
+		{"trailing {", nil, "trailing {"},
+	}
+	for _, tt := range tests {
+		s := Split(tt.template)
+		if s.String() != tt.template || s.NumArgs() != len(tt.args) {
+			t.Errorf("Split(%q): got %q with %d args; want %d args", tt.template, s.String(), s.NumArgs(), len(tt.args))
+		}
+		var b Builder
+		n, err := s.WriteWith(&b, tt.args...)
+		if err != nil || len(tt.want) != n {
+			t.Errorf("%q.WriteWith: got %d,%v; want %d,nil", tt.template, n, err, len(tt.want))
+		}
+		check(t, &b, tt.want)
+	}
+
+	s := Split("a={} b={}")
+	for _, args := range [][]any{nil, {1}, {1, 2, 3}} {
+		var b Builder
+		b.WriteString("x")
+		if n, err := s.WriteWith(&b, args...); n != 0 || err == nil {
+			t.Errorf("WriteWith(%v): got %d,%v; want 0,error", args, n, err)
+		}
+		check(t, &b, "x")
+	}
+}
+
+func TestSplitAllocs(t *testing.T) {
+	s := Split("id={} name={} ok={}")
+	var b Builder
+	b.Grow(1024)
+	allocs := testing.AllocsPerRun(100, func() {
+		s.WriteWith(&b, 42, "gopher", true)
+	})
+	// The arguments escape into the variadic slice, which is the only allocation.
+	if allocs > 1 {
+		t.Errorf("WriteWith allocs = %v; want <= 1", allocs)
+	}
+}
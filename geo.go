@@ -0,0 +1,110 @@
+// Copyright 2023 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package builder
+
+import (
+	"math"
+	"strconv"
+)
+
+// A CoordFormat selects how WriteLatLon formats coordinates.
+type CoordFormat int
+
+const (
+	// DecimalDegrees formats coordinates as signed decimal degrees,
+	// as in "48.858370, 2.294481".
+	DecimalDegrees CoordFormat = iota
+
+	// DegreesMinutesSeconds formats coordinates as degrees, minutes and
+	// seconds with a hemisphere letter, as in `48°51'30.1"N 2°17'40.1"E`.
+	DegreesMinutesSeconds
+)
+
+// maxDMSPrec is the largest precision of the seconds written for
+// DegreesMinutesSeconds; a float64 angle carries no further digits.
+const maxDMSPrec = 9
+
+// WriteLatLon appends the coordinates lat and lon, in degrees, to b's buffer
+// in the given format. prec is the number of digits after the decimal point
+// of the degrees for DecimalDegrees; the special precision -1 uses the
+// smallest number of digits necessary to represent the degrees exactly.
+// For DegreesMinutesSeconds, prec is the number of digits after the decimal
+// point of the seconds, between 0 and 9, a precision outside that range
+// being clamped to it. NaN and infinite coordinates are written as by
+// WriteFloat, without minutes, seconds or hemisphere.
+// It returns the length of written and a nil error.
+func (b *Builder) WriteLatLon(lat, lon float64, format CoordFormat, prec int) (int, error) {
+	b.copyCheck()
+	n := len(b.buf)
+	switch format {
+	case DegreesMinutesSeconds:
+		b.buf = appendDMS(b.buf, lat, prec, 'N', 'S')
+		b.buf = append(b.buf, ' ')
+		b.buf = appendDMS(b.buf, lon, prec, 'E', 'W')
+	default:
+		b.buf = b.appendFloat(b.buf, lat, 'f', prec, 64)
+		b.buf = append(b.buf, ", "...)
+		b.buf = b.appendFloat(b.buf, lon, 'f', prec, 64)
+	}
+	return b.commit(n), nil
+}
+
+// appendDMS appends the angle deg in degrees, minutes and seconds with prec
+// digits after the decimal point of the seconds, followed by pos or neg
+// depending on its sign.
+func appendDMS(dst []byte, deg float64, prec int, pos, neg byte) []byte {
+	if math.IsNaN(deg) || math.IsInf(deg, 0) {
+		return strconv.AppendFloat(dst, deg, 'g', -1, 64)
+	}
+	if prec < 0 {
+		prec = 0
+	} else if prec > maxDMSPrec {
+		prec = maxDMSPrec
+	}
+	hemisphere := pos
+	if deg < 0 {
+		hemisphere = neg
+		deg = -deg
+	}
+
+	// Round once, in units of the last digit of the seconds, so that
+	// rounding carries into minutes and degrees. The arithmetic is exact
+	// below 2^53 units; beyond, only whole degrees are meaningful.
+	unit := math.Pow10(prec)
+	var degs, mins, secs float64
+	if total := math.Round(deg * 3600 * unit); total < 1<<53 {
+		perMinute := 60 * unit
+		secs = math.Mod(total, perMinute)
+		total = (total - secs) / perMinute
+		mins = math.Mod(total, 60)
+		degs = (total - mins) / 60
+	} else {
+		degs = math.Round(deg)
+	}
+
+	dst = strconv.AppendFloat(dst, degs, 'f', 0, 64)
+	dst = append(dst, "°"...)
+	dst = strconv.AppendFloat(dst, mins, 'f', 0, 64)
+	dst = append(dst, '\'')
+	dst = strconv.AppendFloat(dst, secs/unit, 'f', prec, 64)
+	return append(dst, '"', hemisphere)
+}
+
+// WriteGeoJSONPoint appends a GeoJSON Point geometry (RFC 7946) at lat and
+// lon, in degrees, to b's buffer. As GeoJSON requires, the longitude comes
+// first in the coordinates. prec is the number of digits after the decimal
+// point; the special precision -1 uses the smallest number of digits
+// necessary to represent the coordinates exactly.
+// It returns the length of written and a nil error.
+func (b *Builder) WriteGeoJSONPoint(lat, lon float64, prec int) (int, error) {
+	b.copyCheck()
+	n := len(b.buf)
+	b.buf = append(b.buf, `{"type":"Point","coordinates":[`...)
+	b.buf = b.appendFloat(b.buf, lon, 'f', prec, 64)
+	b.buf = append(b.buf, ',')
+	b.buf = b.appendFloat(b.buf, lat, 'f', prec, 64)
+	b.buf = append(b.buf, "]}"...)
+	return b.commit(n), nil
+}
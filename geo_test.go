@@ -0,0 +1,54 @@
+// Copyright 2023 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package builder_test
+
+import (
+	"math"
+	"testing"
+
+	. "github.com/weiwenchen2022/builder"
+)
+
+func TestBuilderWriteLatLon(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		lat, lon float64
+		format   CoordFormat
+		prec     int
+		want     string
+	}{
+		{48.8583701, 2.2944813, DecimalDegrees, 6, "48.858370, 2.294481"},
+		{-33.8567844, 151.213108, DecimalDegrees, -1, "-33.8567844, 151.213108"},
+		{48.8583701, 2.2944813, DegreesMinutesSeconds, 1, `48°51'30.1"N 2°17'40.1"E`},
+		{-33.8567844, -70.6482, DegreesMinutesSeconds, 0, `33°51'24"S 70°38'54"W`},
+		{10.999999, 0, DegreesMinutesSeconds, 1, `11°0'0.0"N 0°0'0.0"E`},
+		{0.5, -0.25, DegreesMinutesSeconds, -1, `0°30'0"N 0°15'0"W`},
+		{math.NaN(), math.Inf(-1), DegreesMinutesSeconds, 1, "NaN -Inf"},
+		{0.5, 1, DegreesMinutesSeconds, 20, `0°30'0.000000000"N 1°0'0.000000000"E`},
+		{1e20, 0, DegreesMinutesSeconds, 0, `100000000000000000000°0'0"N 0°0'0"E`},
+	}
+
+	for _, tt := range tests {
+		var b Builder
+		n, err := b.WriteLatLon(tt.lat, tt.lon, tt.format, tt.prec)
+		if err != nil || len(tt.want) != n {
+			t.Errorf("WriteLatLon(%v, %v, %v, %d): got %d,%v; want %d,nil", tt.lat, tt.lon, tt.format, tt.prec, n, err, len(tt.want))
+		}
+		check(t, &b, tt.want)
+	}
+}
+
+func TestBuilderWriteGeoJSONPoint(t *testing.T) {
+	t.Parallel()
+
+	var b Builder
+	b.WriteGeoJSONPoint(48.8583701, 2.2944813, 5)
+	check(t, &b, `{"type":"Point","coordinates":[2.29448,48.85837]}`)
+
+	b.Reset()
+	b.WriteGeoJSONPoint(-1.5, 100, -1)
+	check(t, &b, `{"type":"Point","coordinates":[100,-1.5]}`)
+}
@@ -1,3 +1,5 @@
 module github.com/weiwenchen2022/builder
 
 go 1.20
+
+require golang.org/x/text v0.14.0
@@ -0,0 +1,186 @@
+// Copyright 2023 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package builder
+
+import (
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// WriteHCLString appends a double-quoted HCL2 string literal representing s
+// to b's buffer. Besides the usual backslash escapes, template sequences are
+// escaped ("${" as "$${" and "%{" as "%%{") so that s is reproduced literally.
+// It returns the length of written and a nil error.
+func (b *Builder) WriteHCLString(s string) (int, error) {
+	b.copyCheck()
+	n := len(b.buf)
+	b.buf = appendHCLString(b.buf, s)
+	return b.commit(n), nil
+}
+
+func appendHCLString(dst []byte, s string) []byte {
+	const hex = "0123456789abcdef"
+
+	dst = append(dst, '"')
+	for i := 0; i < len(s); {
+		c := s[i]
+		if c >= utf8.RuneSelf {
+			r, size := utf8.DecodeRuneInString(s[i:])
+			if r == utf8.RuneError && size == 1 {
+				dst = append(dst, "\uFFFD"...)
+			} else {
+				dst = append(dst, s[i:i+size]...)
+			}
+			i += size
+			continue
+		}
+
+		switch {
+		case c == '"' || c == '\\':
+			dst = append(dst, '\\', c)
+		case c == '\n':
+			dst = append(dst, '\\', 'n')
+		case c == '\r':
+			dst = append(dst, '\\', 'r')
+		case c == '\t':
+			dst = append(dst, '\\', 't')
+		case c < 0x20 || c == 0x7f:
+			dst = append(dst, '\\', 'u', '0', '0', hex[c>>4], hex[c&0xF])
+		case (c == '$' || c == '%') && i+1 < len(s) && s[i+1] == '{':
+			dst = append(dst, c, c, '{')
+			i++
+		default:
+			dst = append(dst, c)
+		}
+		i++
+	}
+	return append(dst, '"')
+}
+
+// An HCLWriter writes HCL2 native syntax, attributes and nested blocks,
+// to a Builder. Nested content is indented by two spaces per level.
+type HCLWriter struct {
+	b     *Builder
+	depth int
+}
+
+// HCL returns an HCLWriter that writes to b.
+func (b *Builder) HCL() *HCLWriter {
+	return &HCLWriter{b: b}
+}
+
+func (w *HCLWriter) indent(dst []byte) []byte {
+	for i := 0; i < w.depth; i++ {
+		dst = append(dst, "  "...)
+	}
+	return dst
+}
+
+// Attribute writes an attribute assigning the expression expr, which is
+// written verbatim, to name.
+func (w *HCLWriter) Attribute(name, expr string) {
+	b := w.b
+	b.copyCheck()
+	n := len(b.buf)
+	b.buf = w.indent(b.buf)
+	b.buf = append(b.buf, name...)
+	b.buf = append(b.buf, " = "...)
+	b.buf = append(b.buf, expr...)
+	b.buf = append(b.buf, b.eol()...)
+	b.commit(n)
+}
+
+// StringAttribute writes an attribute assigning the string literal value to name.
+func (w *HCLWriter) StringAttribute(name, value string) {
+	b := w.b
+	b.copyCheck()
+	n := len(b.buf)
+	b.buf = w.indent(b.buf)
+	b.buf = append(b.buf, name...)
+	b.buf = append(b.buf, " = "...)
+	b.buf = appendHCLString(b.buf, value)
+	b.buf = append(b.buf, b.eol()...)
+	b.commit(n)
+}
+
+// HeredocAttribute writes an attribute assigning text to name using heredoc
+// syntax. The delimiter is chosen so that it does not occur as a line of text,
+// and template sequences in text are escaped.
+func (w *HCLWriter) HeredocAttribute(name, text string) {
+	delim := "EOT"
+	for i := 1; containsLine(text, delim); i++ {
+		delim = "EOT" + strconv.Itoa(i)
+	}
+
+	b := w.b
+	b.copyCheck()
+	n := len(b.buf)
+	b.buf = w.indent(b.buf)
+	b.buf = append(b.buf, name...)
+	b.buf = append(b.buf, " = <<"...)
+	b.buf = append(b.buf, delim...)
+	b.buf = append(b.buf, b.eol()...)
+	for i := 0; i < len(text); i++ {
+		c := text[i]
+		if (c == '$' || c == '%') && i+1 < len(text) && text[i+1] == '{' {
+			b.buf = append(b.buf, c)
+		}
+		b.buf = append(b.buf, c)
+	}
+	if text != "" && !strings.HasSuffix(text, "\n") {
+		b.buf = append(b.buf, b.eol()...)
+	}
+	b.buf = append(b.buf, delim...)
+	b.buf = append(b.buf, b.eol()...)
+	b.commit(n)
+}
+
+// containsLine reports whether s has a line equal to line once surrounding
+// whitespace is removed.
+func containsLine(s, line string) bool {
+	for s != "" {
+		var l string
+		l, s, _ = strings.Cut(s, "\n")
+		if strings.TrimSpace(l) == line {
+			return true
+		}
+	}
+	return false
+}
+
+// BlockStart opens a block of the given type with the given labels, which are
+// written as string literals. Each BlockStart must be matched by a BlockEnd.
+func (w *HCLWriter) BlockStart(typ string, labels ...string) {
+	b := w.b
+	b.copyCheck()
+	n := len(b.buf)
+	b.buf = w.indent(b.buf)
+	b.buf = append(b.buf, typ...)
+	for _, l := range labels {
+		b.buf = append(b.buf, ' ')
+		b.buf = appendHCLString(b.buf, l)
+	}
+	b.buf = append(b.buf, " {"...)
+	b.buf = append(b.buf, b.eol()...)
+	w.depth++
+	b.commit(n)
+}
+
+// BlockEnd closes the innermost open block. It panics if there is none.
+func (w *HCLWriter) BlockEnd() {
+	if w.depth == 0 {
+		panic("builder.HCLWriter.BlockEnd: no open block")
+	}
+	w.depth--
+
+	b := w.b
+	b.copyCheck()
+	n := len(b.buf)
+	b.buf = w.indent(b.buf)
+	b.buf = append(b.buf, '}')
+	b.buf = append(b.buf, b.eol()...)
+	b.commit(n)
+}
@@ -0,0 +1,85 @@
+// Copyright 2023 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package builder_test
+
+import (
+	"testing"
+
+	. "github.com/weiwenchen2022/builder"
+)
+
+func TestBuilderWriteHCLString(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		in, want string
+	}{
+		{"", `""`},
+		{"plain", `"plain"`},
+		{`a "quoted" \ path`, `"a \"quoted\" \\ path"`},
+		{"tab\tnl\ncr\r\x01\x7f", `"tab\tnl\ncr\r\u0001\u007f"`},
+		{"${var.x} and %{if}", `"$${var.x} and %%{if}"`},
+		{"$ and % alone", `"$ and % alone"`},
+		{"héllo\xff", "\"héllo\uFFFD\""},
+	}
+
+	for _, tt := range tests {
+		var b Builder
+		n, err := b.WriteHCLString(tt.in)
+		if err != nil || len(tt.want) != n {
+			t.Errorf("WriteHCLString(%q): got %d,%v; want %d,nil", tt.in, n, err, len(tt.want))
+		}
+		check(t, &b, tt.want)
+	}
+}
+
+func TestHCLWriter(t *testing.T) {
+	t.Parallel()
+
+	var b Builder
+	w := b.HCL()
+	w.BlockStart("resource", "aws_instance", "web")
+	w.StringAttribute("ami", "ami-${id}")
+	w.Attribute("count", "2")
+	w.BlockStart("tags")
+	w.StringAttribute("Name", `say "hi"`)
+	w.BlockEnd()
+	w.HeredocAttribute("user_data", "#!/bin/sh\necho ${HOME}\nEOT\n")
+	w.BlockEnd()
+
+	const want = `resource "aws_instance" "web" {
+  ami = "ami-$${id}"
+  count = 2
+  tags {
+    Name = "say \"hi\""
+  }
+  user_data = <<EOT1
+#!/bin/sh
+echo $${HOME}
+EOT
+EOT1
+}
+`
+	check(t, &b, want)
+
+	defer func() {
+		if r := recover(); r == nil {
+			t.Error("BlockEnd without BlockStart should panic")
+		}
+	}()
+	w.BlockEnd()
+}
+
+func TestHCLWriterCRLF(t *testing.T) {
+	t.Parallel()
+
+	var b Builder
+	b.SetLineEnding(CRLF)
+	w := b.HCL()
+	w.BlockStart("locals")
+	w.HeredocAttribute("script", "echo hi")
+	w.BlockEnd()
+	check(t, &b, "locals {\r\n  script = <<EOT\r\necho hi\r\nEOT\r\n}\r\n")
+}
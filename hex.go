@@ -0,0 +1,88 @@
+// Copyright 2023 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package builder
+
+const (
+	lowerHexDigits = "0123456789abcdef"
+	upperHexDigits = "0123456789ABCDEF"
+)
+
+// WriteHex appends the lowercase hexadecimal encoding of p, as produced by
+// hex.EncodeToString, to b's buffer, growing it at most once.
+// It returns the length of written and a nil error.
+func (b *Builder) WriteHex(p []byte) (int, error) {
+	return b.writeHex(p, lowerHexDigits)
+}
+
+// WriteHexUpper is like WriteHex but uses uppercase letters.
+// It returns the length of written and a nil error.
+func (b *Builder) WriteHexUpper(p []byte) (int, error) {
+	return b.writeHex(p, upperHexDigits)
+}
+
+func (b *Builder) writeHex(p []byte, digits string) (int, error) {
+	if !b.reserve(2 * len(p)) {
+		return 0, ErrFull
+	}
+	n := len(b.buf)
+	b.buf = b.buf[:n+2*len(p)]
+	dst := b.buf[n:]
+	for i, c := range p {
+		dst[2*i] = digits[c>>4]
+		dst[2*i+1] = digits[c&0xF]
+	}
+	return b.commit(n), nil
+}
+
+// WriteByteHex appends the two lowercase hexadecimal digits of c to b's
+// buffer.
+// It returns 2 and a nil error.
+func (b *Builder) WriteByteHex(c byte) (int, error) {
+	b.copyCheck()
+	n := len(b.buf)
+	b.buf = append(b.buf, lowerHexDigits[c>>4], lowerHexDigits[c&0xF])
+	return b.commit(n), nil
+}
+
+// WriteHexDump appends a hex dump of p to b's buffer in the format of
+// hex.Dump: lines of sixteen bytes, each starting with the offset and
+// ending with the printable ASCII characters of the bytes. Lines end as
+// set by SetLineEnding.
+// It returns the length of written and a nil error.
+func (b *Builder) WriteHexDump(p []byte) (int, error) {
+	b.copyCheck()
+	n := len(b.buf)
+	for off := 0; off < len(p); off += 16 {
+		line := p[off:]
+		if len(line) > 16 {
+			line = line[:16]
+		}
+
+		for shift := 28; shift >= 0; shift -= 4 {
+			b.buf = append(b.buf, lowerHexDigits[off>>uint(shift)&0xF])
+		}
+		b.buf = append(b.buf, ' ', ' ')
+		for i := 0; i < 16; i++ {
+			if i < len(line) {
+				b.buf = append(b.buf, lowerHexDigits[line[i]>>4], lowerHexDigits[line[i]&0xF], ' ')
+			} else {
+				b.buf = append(b.buf, "   "...)
+			}
+			if i == 7 {
+				b.buf = append(b.buf, ' ')
+			}
+		}
+		b.buf = append(b.buf, ' ', '|')
+		for _, c := range line {
+			if c < 32 || c > 126 {
+				c = '.'
+			}
+			b.buf = append(b.buf, c)
+		}
+		b.buf = append(b.buf, '|')
+		b.buf = append(b.buf, b.eol()...)
+	}
+	return b.commit(n), nil
+}
@@ -0,0 +1,83 @@
+// Copyright 2023 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package builder_test
+
+import (
+	"encoding/hex"
+	"strings"
+	"testing"
+
+	. "github.com/weiwenchen2022/builder"
+)
+
+func TestBuilderWriteHex(t *testing.T) {
+	t.Parallel()
+
+	inputs := [][]byte{nil, {0}, {0xde, 0xad, 0xbe, 0xef}, []byte("hello"), {0x01, 0x23, 0x45, 0x67, 0x89, 0xab, 0xcd, 0xef}}
+
+	for _, p := range inputs {
+		want := hex.EncodeToString(p)
+
+		var b Builder
+		n, err := b.WriteHex(p)
+		if err != nil || len(want) != n {
+			t.Errorf("WriteHex(%x): got %d,%v; want %d,nil", p, n, err, len(want))
+		}
+		check(t, &b, want)
+
+		b.Reset()
+		n, err = b.WriteHexUpper(p)
+		if err != nil || len(want) != n {
+			t.Errorf("WriteHexUpper(%x): got %d,%v; want %d,nil", p, n, err, len(want))
+		}
+		check(t, &b, strings.ToUpper(want))
+	}
+}
+
+func TestBuilderWriteByteHex(t *testing.T) {
+	t.Parallel()
+
+	var b Builder
+	for _, c := range []byte{0x00, 0x0f, 0xa0, 0xff} {
+		if n, err := b.WriteByteHex(c); err != nil || n != 2 {
+			t.Errorf("WriteByteHex(%#x): got %d,%v; want 2,nil", c, n, err)
+		}
+	}
+	check(t, &b, "000fa0ff")
+}
+
+func TestBuilderWriteHexAllocs(t *testing.T) {
+	var b Builder
+	b.Grow(1024)
+	digest := []byte{0xde, 0xad, 0xbe, 0xef}
+	allocs := testing.AllocsPerRun(100, func() {
+		b.WriteHex(digest)
+		b.WriteByteHex(0x2a)
+	})
+	if allocs != 0 {
+		t.Errorf("WriteHex allocs = %v; want 0", allocs)
+	}
+}
+
+func TestBuilderWriteHexDump(t *testing.T) {
+	t.Parallel()
+
+	var all [300]byte
+	for i := range all {
+		all[i] = byte(i)
+	}
+	inputs := [][]byte{nil, []byte("hello"), []byte("exactly 16 bytes"), []byte("seventeen bytes!!"), []byte("0123456"), []byte("01234567"), all[:]}
+
+	for _, p := range inputs {
+		want := hex.Dump(p)
+
+		var b Builder
+		n, err := b.WriteHexDump(p)
+		if err != nil || len(want) != n {
+			t.Errorf("WriteHexDump(%q): got %d,%v; want %d,nil", p, n, err, len(want))
+		}
+		check(t, &b, want)
+	}
+}
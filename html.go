@@ -0,0 +1,46 @@
+// Copyright 2023 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package builder
+
+// WriteHTMLEscaped appends s to b's buffer with the special characters
+// <, >, &, ' and " escaped, producing the same output as html.EscapeString
+// without building an intermediate string.
+// It returns the length of written and a nil error.
+func (b *Builder) WriteHTMLEscaped(s string) (int, error) {
+	b.copyCheck()
+	n := len(b.buf)
+	b.buf = appendHTMLEscaped(b.buf, s)
+	return b.commit(n), nil
+}
+
+func appendHTMLEscaped(dst []byte, s string) []byte {
+	last := 0
+	for i := 0; i < len(s); i++ {
+		if i += htmlSafePrefix(s[i:]); i == len(s) {
+			break
+		}
+		var esc string
+		switch s[i] {
+		case '<':
+			esc = "&lt;"
+		case '>':
+			esc = "&gt;"
+		case '&':
+			esc = "&amp;"
+		case '\'':
+			// "&#39;" is shorter than "&apos;" and apos was not in HTML until HTML5.
+			esc = "&#39;"
+		case '"':
+			// "&#34;" is shorter than "&quot;".
+			esc = "&#34;"
+		default:
+			continue
+		}
+		dst = append(dst, s[last:i]...)
+		dst = append(dst, esc...)
+		last = i + 1
+	}
+	return append(dst, s[last:]...)
+}
@@ -0,0 +1,47 @@
+// Copyright 2023 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package builder_test
+
+import (
+	"html"
+	"testing"
+
+	. "github.com/weiwenchen2022/builder"
+	"github.com/weiwenchen2022/builder/buildertest"
+)
+
+func TestBuilderWriteHTMLEscaped(t *testing.T) {
+	t.Parallel()
+
+	tests := []string{
+		"",
+		"plain text",
+		`<a href="x">Tom & Jerry's</a>`,
+		"<<>>&&''\"\"",
+		"héllo <世界>",
+	}
+	tests = append(tests, buildertest.Adversarial...)
+
+	for _, s := range tests {
+		var b Builder
+		want := html.EscapeString(s)
+		n, err := b.WriteHTMLEscaped(s)
+		if err != nil || len(want) != n {
+			t.Errorf("WriteHTMLEscaped(%q): got %d,%v; want %d,nil", s, n, err, len(want))
+		}
+		check(t, &b, want)
+	}
+}
+
+func TestBuilderWriteHTMLEscapedAllocs(t *testing.T) {
+	var b Builder
+	b.Grow(1024)
+	allocs := testing.AllocsPerRun(100, func() {
+		b.WriteHTMLEscaped(`<p class="x">a & b</p>`)
+	})
+	if allocs != 0 {
+		t.Errorf("WriteHTMLEscaped allocs = %v; want 0", allocs)
+	}
+}
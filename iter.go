@@ -0,0 +1,54 @@
+// Copyright 2023 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build go1.23
+
+package builder
+
+import (
+	"bytes"
+	"iter"
+	"unicode/utf8"
+	"unsafe"
+)
+
+// Runes returns an iterator over the runes of b's content and their byte
+// offsets, like ranging over b.String(), without allocating. Invalid UTF-8
+// yields utf8.RuneError for each invalid byte. The iterator sees the content
+// as it is when each rune is reached, so it may be used while b is written
+// to, but must not be used concurrently with writes.
+func (b *Builder) Runes() iter.Seq2[int, rune] {
+	return func(yield func(int, rune) bool) {
+		for i := 0; i < len(b.buf); {
+			r, size := rune(b.buf[i]), 1
+			if r >= utf8.RuneSelf {
+				r, size = utf8.DecodeRune(b.buf[i:])
+			}
+			if !yield(i, r) {
+				return
+			}
+			i += size
+		}
+	}
+}
+
+// Lines returns an iterator over the lines of b's content, like the one
+// strings.Lines returns for b.String(): each line includes its terminating
+// newline, if any, and empty content yields no lines. The lines share memory
+// with b's buffer, like the result of String, so no copies are made. The
+// iterator must not be used concurrently with writes.
+func (b *Builder) Lines() iter.Seq[string] {
+	return func(yield func(string) bool) {
+		for i := 0; i < len(b.buf); {
+			k := bytes.IndexByte(b.buf[i:], '\n') + 1
+			if k == 0 {
+				k = len(b.buf) - i
+			}
+			if !yield(unsafe.String(&b.buf[i], k)) {
+				return
+			}
+			i += k
+		}
+	}
+}
@@ -0,0 +1,122 @@
+// Copyright 2023 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build go1.23
+
+package builder_test
+
+import (
+	"strings"
+	"testing"
+
+	. "github.com/weiwenchen2022/builder"
+	"github.com/weiwenchen2022/builder/buildertest"
+)
+
+func TestBuilderRunes(t *testing.T) {
+	t.Parallel()
+
+	inputs := append([]string{"", "abc", "h\u00e9llo, \u4e16\u754c \U0001F600", "bad\xff\xe4\xb8"}, buildertest.Adversarial...)
+	for _, s := range inputs {
+		var b Builder
+		b.WriteString(s)
+
+		type pos struct {
+			i int
+			r rune
+		}
+		var want, got []pos
+		for i, r := range s {
+			want = append(want, pos{i, r})
+		}
+		for i, r := range b.Runes() {
+			got = append(got, pos{i, r})
+		}
+		if len(got) != len(want) {
+			t.Errorf("Runes of %q: got %d runes; want %d", s, len(got), len(want))
+			continue
+		}
+		for k := range want {
+			if got[k] != want[k] {
+				t.Errorf("Runes of %q: rune %d: got %v; want %v", s, k, got[k], want[k])
+			}
+		}
+	}
+}
+
+func TestBuilderRunesBreak(t *testing.T) {
+	t.Parallel()
+
+	var b Builder
+	b.WriteString("ab\u00e9cd")
+	var got []rune
+	for i, r := range b.Runes() {
+		if i > 2 {
+			break
+		}
+		got = append(got, r)
+	}
+	if string(got) != "ab\u00e9" {
+		t.Errorf("Runes with break: got %q; want %q", string(got), "ab\u00e9")
+	}
+}
+
+func TestBuilderRunesAllocs(t *testing.T) {
+	var b Builder
+	b.WriteString("h\u00e9llo, \u4e16\u754c")
+	allocs := testing.AllocsPerRun(100, func() {
+		n := 0
+		for range b.Runes() {
+			n++
+		}
+	})
+	if allocs != 0 {
+		t.Errorf("Runes allocs = %v; want 0", allocs)
+	}
+}
+
+func TestBuilderLines(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		in   string
+		want []string
+	}{
+		{"", nil},
+		{"one", []string{"one"}},
+		{"one\n", []string{"one\n"}},
+		{"one\r\ntwo\n\nlast", []string{"one\r\n", "two\n", "\n", "last"}},
+		{"\n\n", []string{"\n", "\n"}},
+	}
+	for _, tt := range tests {
+		var b Builder
+		b.WriteString(tt.in)
+		var got []string
+		for line := range b.Lines() {
+			got = append(got, line)
+		}
+		if strings.Join(got, "|") != strings.Join(tt.want, "|") || len(got) != len(tt.want) {
+			t.Errorf("Lines of %q: got %q; want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestBuilderLinesShared(t *testing.T) {
+	var b Builder
+	b.WriteString("alpha\nbeta\n")
+	var first string
+	allocs := testing.AllocsPerRun(100, func() {
+		for line := range b.Lines() {
+			first = line
+			break
+		}
+	})
+	if allocs != 0 {
+		t.Errorf("Lines allocs = %v; want 0", allocs)
+	}
+	b.WriteString("gamma\n")
+	if first != "alpha\n" {
+		t.Errorf("line changed after a later write: got %q", first)
+	}
+}
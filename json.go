@@ -0,0 +1,309 @@
+// Copyright 2023 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package builder
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"math"
+	"sort"
+	"strconv"
+	"unicode/utf16"
+	"unicode/utf8"
+)
+
+// WriteCanonicalJSON appends the canonical JSON encoding of v, as defined by
+// the JSON Canonicalization Scheme (RFC 8785), to b's buffer.
+// v is first encoded with encoding/json, so json.Marshaler implementations and
+// struct tags are honored. Object members are then sorted by the UTF-16 code
+// units of their names, numbers are written in their shortest ECMAScript form
+// and strings are escaped minimally.
+// It returns the length of written. If v cannot be encoded, b is left
+// unchanged and the error is returned.
+func (b *Builder) WriteCanonicalJSON(v any) (int, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return 0, err
+	}
+
+	d := json.NewDecoder(bytes.NewReader(data))
+	d.UseNumber()
+	var x any
+	if err := d.Decode(&x); err != nil {
+		return 0, err
+	}
+
+	b.copyCheck()
+	n := len(b.buf)
+	buf, err := appendCanonicalJSON(b.buf, x)
+	if err != nil {
+		return 0, err
+	}
+	b.buf = buf
+	return b.commit(n), nil
+}
+
+// WriteJSONString appends s to b's buffer as a quoted JSON string.
+// The quotation mark, the reverse solidus and control characters are escaped,
+// as are U+2028 and U+2029 so the output is also valid JavaScript.
+// Invalid UTF-8 is replaced by the escaped replacement character \ufffd,
+// so the output is always valid JSON.
+// It returns the length of written and a nil error.
+func (b *Builder) WriteJSONString(s string) (int, error) {
+	b.copyCheck()
+	n := len(b.buf)
+	b.buf = appendJSONString(b.buf, s)
+	return b.commit(n), nil
+}
+
+// WriteJSONValue appends the JSON encoding of v, as produced by json.Marshal,
+// to b's buffer. The encoder writes directly into b, avoiding the copy
+// that appending the result of json.Marshal would make.
+// It returns the length of written. If v cannot be encoded, b is left
+// unchanged and the error is returned.
+func (b *Builder) WriteJSONValue(v any) (int, error) {
+	b.copyCheck()
+	n := len(b.buf)
+	if err := json.NewEncoder((*appendWriter)(b)).Encode(v); err != nil {
+		b.rollback(n)
+		return 0, err
+	}
+	// Encode terminates each value with a newline, which Marshal does not.
+	b.buf = b.buf[:len(b.buf)-1]
+	return b.commit(n), nil
+}
+
+// An appendWriter appends to a Builder's buffer without committing,
+// for use by encoders whose output is committed as a whole.
+type appendWriter Builder
+
+func (w *appendWriter) Write(p []byte) (int, error) {
+	w.buf = append(w.buf, p...)
+	return len(p), nil
+}
+
+var errJSONNumber = errors.New("builder: JSON number out of range for canonical encoding")
+
+// appendCanonicalJSON appends the RFC 8785 form of x, a value produced by a
+// json.Decoder using UseNumber, to dst.
+func appendCanonicalJSON(dst []byte, x any) ([]byte, error) {
+	switch x := x.(type) {
+	case nil:
+		return append(dst, "null"...), nil
+	case bool:
+		return strconv.AppendBool(dst, x), nil
+	case json.Number:
+		f, err := strconv.ParseFloat(string(x), 64)
+		if err != nil || math.IsInf(f, 0) {
+			return dst, errJSONNumber
+		}
+		return appendES6Number(dst, f), nil
+	case string:
+		return appendCanonicalJSONString(dst, x), nil
+	case []any:
+		dst = append(dst, '[')
+		for i, v := range x {
+			if i > 0 {
+				dst = append(dst, ',')
+			}
+			var err error
+			if dst, err = appendCanonicalJSON(dst, v); err != nil {
+				return dst, err
+			}
+		}
+		return append(dst, ']'), nil
+	case map[string]any:
+		keys := make([]string, 0, len(x))
+		for k := range x {
+			keys = append(keys, k)
+		}
+		sort.Slice(keys, func(i, j int) bool { return utf16Less(keys[i], keys[j]) })
+
+		dst = append(dst, '{')
+		for i, k := range keys {
+			if i > 0 {
+				dst = append(dst, ',')
+			}
+			dst = appendCanonicalJSONString(dst, k)
+			dst = append(dst, ':')
+			var err error
+			if dst, err = appendCanonicalJSON(dst, x[k]); err != nil {
+				return dst, err
+			}
+		}
+		return append(dst, '}'), nil
+	}
+	panic("builder: unexpected JSON value")
+}
+
+// appendCanonicalJSONString appends s as a JSON string, escaping only the
+// quotation mark, the reverse solidus and control characters.
+func appendCanonicalJSONString(dst []byte, s string) []byte {
+	dst = append(dst, '"')
+	start := 0
+	for i := 0; i < len(s); i++ {
+		if i += jsonSafePrefix(s[i:], false); i == len(s) {
+			break
+		}
+		c := s[i]
+		if c >= 0x20 && c != '"' && c != '\\' {
+			continue
+		}
+
+		dst = append(dst, s[start:i]...)
+		dst = appendJSONEscape(dst, c)
+		start = i + 1
+	}
+	dst = append(dst, s[start:]...)
+	return append(dst, '"')
+}
+
+// appendJSONString appends s as a JSON string, like appendCanonicalJSONString
+// but also handling invalid UTF-8 and the JavaScript line terminators.
+func appendJSONString(dst []byte, s string) []byte {
+	dst = append(dst, '"')
+	start := 0
+	for i := 0; i < len(s); {
+		if i += jsonSafePrefix(s[i:], true); i == len(s) {
+			break
+		}
+		c := s[i]
+		if c < utf8.RuneSelf {
+			if c >= 0x20 && c != '"' && c != '\\' {
+				i++
+				continue
+			}
+			dst = append(dst, s[start:i]...)
+			dst = appendJSONEscape(dst, c)
+			i++
+			start = i
+			continue
+		}
+
+		r, size := utf8.DecodeRuneInString(s[i:])
+		if r == utf8.RuneError && size == 1 {
+			dst = append(dst, s[start:i]...)
+			dst = append(dst, `\ufffd`...)
+			i += size
+			start = i
+			continue
+		}
+		if r == '\u2028' || r == '\u2029' {
+			dst = append(dst, s[start:i]...)
+			dst = append(dst, `\u202`...)
+			dst = append(dst, "89"[r&1])
+			i += size
+			start = i
+			continue
+		}
+		i += size
+	}
+	dst = append(dst, s[start:]...)
+	return append(dst, '"')
+}
+
+// appendJSONEscape appends the JSON escape sequence for c, which is the
+// quotation mark, the reverse solidus or a control character.
+func appendJSONEscape(dst []byte, c byte) []byte {
+	const hex = "0123456789abcdef"
+
+	switch c {
+	case '"', '\\':
+		return append(dst, '\\', c)
+	case '\b':
+		return append(dst, '\\', 'b')
+	case '\f':
+		return append(dst, '\\', 'f')
+	case '\n':
+		return append(dst, '\\', 'n')
+	case '\r':
+		return append(dst, '\\', 'r')
+	case '\t':
+		return append(dst, '\\', 't')
+	}
+	return append(dst, '\\', 'u', '0', '0', hex[c>>4], hex[c&0xF])
+}
+
+// appendES6Number appends f formatted as ECMAScript's Number.prototype.toString
+// would, which is the number serialization mandated by RFC 8785.
+func appendES6Number(dst []byte, f float64) []byte {
+	if f == 0 {
+		return append(dst, '0')
+	}
+	if f < 0 {
+		dst = append(dst, '-')
+		f = -f
+	}
+
+	// Shortest round-trip digits in the form d.ddde±xx.
+	var scratch [32]byte
+	e := strconv.AppendFloat(scratch[:0], f, 'e', -1, 64)
+	i := bytes.IndexByte(e, 'e')
+	exp, _ := strconv.Atoi(string(e[i+1:]))
+	var d [24]byte
+	digits := append(d[:0], e[0])
+	if i > 1 {
+		digits = append(digits, e[2:i]...)
+	}
+
+	k := len(digits)
+	n := exp + 1 // position of the decimal point relative to digits
+	switch {
+	case k <= n && n <= 21:
+		dst = append(dst, digits...)
+		for ; k < n; k++ {
+			dst = append(dst, '0')
+		}
+	case 0 < n && n <= 21:
+		dst = append(dst, digits[:n]...)
+		dst = append(dst, '.')
+		dst = append(dst, digits[n:]...)
+	case -6 < n && n <= 0:
+		dst = append(dst, '0', '.')
+		for ; n < 0; n++ {
+			dst = append(dst, '0')
+		}
+		dst = append(dst, digits...)
+	default:
+		dst = append(dst, digits[0])
+		if k > 1 {
+			dst = append(dst, '.')
+			dst = append(dst, digits[1:]...)
+		}
+		dst = append(dst, 'e')
+		if n-1 >= 0 {
+			dst = append(dst, '+')
+		}
+		dst = strconv.AppendInt(dst, int64(n-1), 10)
+	}
+	return dst
+}
+
+// utf16Less reports whether a sorts before b when both are compared as
+// sequences of UTF-16 code units.
+func utf16Less(a, b string) bool {
+	for a != "" && b != "" {
+		ra, na := utf8.DecodeRuneInString(a)
+		rb, nb := utf8.DecodeRuneInString(b)
+		if ra != rb {
+			a1, a2 := utf16Units(ra)
+			b1, b2 := utf16Units(rb)
+			if a1 != b1 {
+				return a1 < b1
+			}
+			return a2 < b2
+		}
+		a, b = a[na:], b[nb:]
+	}
+	return len(a) < len(b)
+}
+
+func utf16Units(r rune) (rune, rune) {
+	if r < 0x10000 {
+		return r, 0
+	}
+	return utf16.EncodeRune(r)
+}
@@ -0,0 +1,169 @@
+// Copyright 2023 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package builder_test
+
+import (
+	"encoding/json"
+	"math"
+	"testing"
+	"unicode/utf8"
+
+	. "github.com/weiwenchen2022/builder"
+	"github.com/weiwenchen2022/builder/buildertest"
+)
+
+func TestBuilderWriteCanonicalJSON(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		v    any
+		want string
+	}{
+		{"Null", nil, "null"},
+		{"Bool", true, "true"},
+		{"String", "a\"b\\c\n\u001f€", `"a\"b\\c\n\u001f€"`},
+		{"Array", []any{1, "x", false}, `[1,"x",false]`},
+		{
+			"Struct",
+			struct {
+				B int    `json:"b"`
+				A string `json:"a"`
+			}{2, "x"},
+			`{"a":"x","b":2}`,
+		},
+		{
+			"Nested",
+			map[string]any{"z": []any{map[string]any{"y": 1, "x": 2}}, "a": nil},
+			`{"a":null,"z":[{"x":2,"y":1}]}`,
+		},
+		{
+			// Sorting example from RFC 8785, section 3.2.3.
+			"SortUTF16",
+			json.RawMessage(`{"\u20ac":1,"\r":2,"\ufb33":3,"1":4,"\ud83d\ude00":5,"\u0080":6,"\u00f6":7}`),
+			"{\"\\r\":2,\"1\":4,\"\u0080\":6,\"ö\":7,\"€\":1,\"😀\":5,\"\ufb33\":3}",
+		},
+		{
+			// Number examples from RFC 8785, section 3.2.2.3.
+			"Numbers",
+			json.RawMessage(`[333333333.33333329, 1E30, 4.50, 2e-3, 0.000000000000000000000000001, -0, 1e21, 1e20, 1e-6, 1e-7, -12.5, 100]`),
+			`[333333333.3333333,1e+30,4.5,0.002,1e-27,0,1e+21,100000000000000000000,0.000001,1e-7,-12.5,100]`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var b Builder
+			b.WriteString("x")
+			n, err := b.WriteCanonicalJSON(tt.v)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(tt.want) != n {
+				t.Errorf("got n=%d; want %d", n, len(tt.want))
+			}
+			check(t, &b, "x"+tt.want)
+		})
+	}
+}
+
+func TestBuilderWriteCanonicalJSONError(t *testing.T) {
+	t.Parallel()
+
+	for _, v := range []any{math.Inf(1), json.RawMessage(`1e400`), make(chan int)} {
+		var b Builder
+		b.WriteString("x")
+		if _, err := b.WriteCanonicalJSON(v); err == nil {
+			t.Errorf("WriteCanonicalJSON(%v): got nil error", v)
+		}
+		check(t, &b, "x")
+	}
+}
+
+func TestBuilderWriteJSONString(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		in, want string
+	}{
+		{"", `""`},
+		{"plain", `"plain"`},
+		{`quote " and \ backslash`, `"quote \" and \\ backslash"`},
+		{"\b\f\n\r\t\x00\x1f", `"\b\f\n\r\t\u0000\u001f"`},
+		{"<html> & \x7f", "\"<html> & \x7f\""},
+		{"héllo, 世界", `"héllo, 世界"`},
+		{"bad \xff utf8 \xe2\x82", `"bad \ufffd utf8 \ufffd\ufffd"`},
+		{"line\u2028para\u2029", `"line\u2028para\u2029"`},
+	}
+
+	for _, tt := range tests {
+		var b Builder
+		n, err := b.WriteJSONString(tt.in)
+		if err != nil || len(tt.want) != n {
+			t.Errorf("WriteJSONString(%q): got %d,%v; want %d,nil", tt.in, n, err, len(tt.want))
+		}
+		check(t, &b, tt.want)
+	}
+
+	for _, s := range buildertest.Adversarial {
+		var b Builder
+		b.WriteJSONString(s)
+		var got string
+		if err := json.Unmarshal([]byte(b.String()), &got); err != nil {
+			t.Errorf("WriteJSONString(%q) = %s: %v", s, b.String(), err)
+			continue
+		}
+		if utf8.ValidString(s) && s != got {
+			t.Errorf("WriteJSONString(%q) round trip: got %q", s, got)
+		}
+	}
+}
+
+func TestBuilderWriteJSONValue(t *testing.T) {
+	t.Parallel()
+
+	type item struct {
+		Name  string `json:"name"`
+		Count int    `json:"count,omitempty"`
+		HTML  string
+	}
+
+	tests := []any{
+		nil,
+		42,
+		"a<b>&c",
+		[]int{1, 2, 3},
+		map[string]int{"b": 2, "a": 1},
+		item{Name: "x", HTML: "<p>"},
+		json.RawMessage(`{"raw":true}`),
+	}
+
+	for _, v := range tests {
+		want, err := json.Marshal(v)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		var b Builder
+		b.WriteString("v=")
+		n, err := b.WriteJSONValue(v)
+		if err != nil || len(want) != n {
+			t.Errorf("WriteJSONValue(%#v): got %d,%v; want %d,nil", v, n, err, len(want))
+		}
+		check(t, &b, "v="+string(want))
+	}
+}
+
+func TestBuilderWriteJSONValueError(t *testing.T) {
+	t.Parallel()
+
+	var b Builder
+	b.WriteString("keep")
+	n, err := b.WriteJSONValue(map[string]any{"f": func() {}})
+	if err == nil || n != 0 {
+		t.Errorf("WriteJSONValue: got %d,%v; want 0,error", n, err)
+	}
+	check(t, &b, "keep")
+}
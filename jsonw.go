@@ -0,0 +1,196 @@
+// Copyright 2023 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package builder
+
+import (
+	"math"
+	"strconv"
+)
+
+// A JSONWriter writes JSON values to a Builder as a stream of tokens,
+// inserting the commas and colons between them, so that simple producers
+// such as log and metrics emitters need not go through encoding/json.
+//
+// Inside an object every value must be preceded by a call to Field.
+// Misuse that would produce invalid JSON, such as a value without a field
+// name or an End with no open container, panics.
+// Values written at the top level are not separated.
+type JSONWriter struct {
+	b      *Builder
+	stack  []jsonLevel
+	hasKey bool // Field has been written and awaits its value
+}
+
+type jsonLevel struct {
+	object bool
+	count  int
+}
+
+// JSON returns a JSONWriter that writes to b.
+func (b *Builder) JSON() *JSONWriter {
+	return &JSONWriter{b: b}
+}
+
+// value appends the separator required before a value to dst.
+func (w *JSONWriter) value(dst []byte, method string) []byte {
+	if len(w.stack) == 0 {
+		return dst
+	}
+	top := &w.stack[len(w.stack)-1]
+	if top.object {
+		if !w.hasKey {
+			panic("builder.JSONWriter." + method + ": value in object without Field")
+		}
+		w.hasKey = false
+	} else if top.count > 0 {
+		dst = append(dst, ',')
+	}
+	top.count++
+	return dst
+}
+
+// ObjectStart begins an object. It must be matched by a call to End.
+func (w *JSONWriter) ObjectStart() {
+	w.start("ObjectStart", true)
+}
+
+// ArrayStart begins an array. It must be matched by a call to End.
+func (w *JSONWriter) ArrayStart() {
+	w.start("ArrayStart", false)
+}
+
+func (w *JSONWriter) start(method string, object bool) {
+	b := w.b
+	b.copyCheck()
+	n := len(b.buf)
+	b.buf = w.value(b.buf, method)
+	if object {
+		b.buf = append(b.buf, '{')
+	} else {
+		b.buf = append(b.buf, '[')
+	}
+	w.stack = append(w.stack, jsonLevel{object: object})
+	b.commit(n)
+}
+
+// End ends the innermost open object or array.
+// It panics if there is none or if a field is awaiting its value.
+func (w *JSONWriter) End() {
+	if len(w.stack) == 0 {
+		panic("builder.JSONWriter.End: no open object or array")
+	}
+	if w.hasKey {
+		panic("builder.JSONWriter.End: field without value")
+	}
+	top := w.stack[len(w.stack)-1]
+	w.stack = w.stack[:len(w.stack)-1]
+
+	b := w.b
+	b.copyCheck()
+	n := len(b.buf)
+	if top.object {
+		b.buf = append(b.buf, '}')
+	} else {
+		b.buf = append(b.buf, ']')
+	}
+	b.commit(n)
+}
+
+// Field writes the name of the next member of the innermost open object.
+// It panics if that is not an object or if the previous field has no value.
+func (w *JSONWriter) Field(name string) {
+	if len(w.stack) == 0 || !w.stack[len(w.stack)-1].object {
+		panic("builder.JSONWriter.Field: not in an object")
+	}
+	if w.hasKey {
+		panic("builder.JSONWriter.Field: previous field without value")
+	}
+	w.hasKey = true
+
+	b := w.b
+	b.copyCheck()
+	n := len(b.buf)
+	if w.stack[len(w.stack)-1].count > 0 {
+		b.buf = append(b.buf, ',')
+	}
+	b.buf = appendJSONString(b.buf, name)
+	b.buf = append(b.buf, ':')
+	b.commit(n)
+}
+
+// String writes s as a JSON string, escaped as by WriteJSONString.
+func (w *JSONWriter) String(s string) {
+	b := w.b
+	b.copyCheck()
+	n := len(b.buf)
+	b.buf = w.value(b.buf, "String")
+	b.buf = appendJSONString(b.buf, s)
+	b.commit(n)
+}
+
+// Int writes i.
+func (w *JSONWriter) Int(i int64) {
+	b := w.b
+	b.copyCheck()
+	n := len(b.buf)
+	b.buf = w.value(b.buf, "Int")
+	b.buf = strconv.AppendInt(b.buf, i, 10)
+	b.commit(n)
+}
+
+// Uint writes u.
+func (w *JSONWriter) Uint(u uint64) {
+	b := w.b
+	b.copyCheck()
+	n := len(b.buf)
+	b.buf = w.value(b.buf, "Uint")
+	b.buf = strconv.AppendUint(b.buf, u, 10)
+	b.commit(n)
+}
+
+// Float writes f in its shortest form. As JSON cannot represent them,
+// NaN and infinities are written as null, like JavaScript's JSON.stringify.
+func (w *JSONWriter) Float(f float64) {
+	b := w.b
+	b.copyCheck()
+	n := len(b.buf)
+	b.buf = w.value(b.buf, "Float")
+	if math.IsNaN(f) || math.IsInf(f, 0) {
+		b.buf = append(b.buf, "null"...)
+	} else {
+		b.buf = appendES6Number(b.buf, f)
+	}
+	b.commit(n)
+}
+
+// Bool writes v.
+func (w *JSONWriter) Bool(v bool) {
+	b := w.b
+	b.copyCheck()
+	n := len(b.buf)
+	b.buf = w.value(b.buf, "Bool")
+	b.buf = strconv.AppendBool(b.buf, v)
+	b.commit(n)
+}
+
+// Null writes null.
+func (w *JSONWriter) Null() {
+	b := w.b
+	b.copyCheck()
+	n := len(b.buf)
+	b.buf = w.value(b.buf, "Null")
+	b.buf = append(b.buf, "null"...)
+	b.commit(n)
+}
+
+// Raw writes the pre-encoded JSON value raw verbatim. It is not validated.
+func (w *JSONWriter) Raw(raw string) {
+	b := w.b
+	b.copyCheck()
+	n := len(b.buf)
+	b.buf = w.value(b.buf, "Raw")
+	b.buf = append(b.buf, raw...)
+	b.commit(n)
+}
@@ -0,0 +1,91 @@
+// Copyright 2023 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package builder_test
+
+import (
+	"encoding/json"
+	"math"
+	"testing"
+
+	. "github.com/weiwenchen2022/builder"
+)
+
+func TestJSONWriter(t *testing.T) {
+	t.Parallel()
+
+	var b Builder
+	w := b.JSON()
+	w.ObjectStart()
+	w.Field("level")
+	w.String("info")
+	w.Field("n")
+	w.Int(-3)
+	w.Field("big")
+	w.Uint(math.MaxUint64)
+	w.Field("ratio")
+	w.Float(0.25)
+	w.Field("bad")
+	w.Float(math.NaN())
+	w.Field("ok")
+	w.Bool(true)
+	w.Field("tags")
+	w.ArrayStart()
+	w.String("a\"b")
+	w.Null()
+	w.ArrayStart()
+	w.End()
+	w.ObjectStart()
+	w.End()
+	w.Raw(`{"pre":1}`)
+	w.End()
+	w.End()
+
+	const want = `{"level":"info","n":-3,"big":18446744073709551615,"ratio":0.25,"bad":null,"ok":true,"tags":["a\"b",null,[],{},{"pre":1}]}`
+	check(t, &b, want)
+	if !json.Valid([]byte(b.String())) {
+		t.Errorf("output is not valid JSON: %s", b.String())
+	}
+}
+
+func TestJSONWriterTopLevel(t *testing.T) {
+	t.Parallel()
+
+	var b Builder
+	w := b.JSON()
+	w.Int(1)
+	b.WriteByte('\n')
+	w.ObjectStart()
+	w.End()
+	check(t, &b, "1\n{}")
+}
+
+func TestJSONWriterPanics(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		f    func(w *JSONWriter)
+	}{
+		{"End", func(w *JSONWriter) { w.End() }},
+		{"FieldAtTop", func(w *JSONWriter) { w.Field("x") }},
+		{"FieldInArray", func(w *JSONWriter) { w.ArrayStart(); w.Field("x") }},
+		{"ValueWithoutField", func(w *JSONWriter) { w.ObjectStart(); w.Int(1) }},
+		{"FieldTwice", func(w *JSONWriter) { w.ObjectStart(); w.Field("x"); w.Field("y") }},
+		{"EndAfterField", func(w *JSONWriter) { w.ObjectStart(); w.Field("x"); w.End() }},
+	}
+
+	for _, tt := range tests {
+		var b Builder
+		w := b.JSON()
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("%s: did not panic", tt.name)
+				}
+			}()
+			tt.f(w)
+		}()
+	}
+}
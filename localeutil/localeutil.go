@@ -0,0 +1,37 @@
+// Copyright 2023 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package localeutil writes numbers to a builder.Builder formatted for
+// a language, with its digit grouping, decimal separator and digits, using
+// golang.org/x/text. It is a separate package so that programs that do not
+// need localized output do not depend on golang.org/x/text.
+package localeutil
+
+import (
+	"golang.org/x/text/language"
+	"golang.org/x/text/message"
+	"golang.org/x/text/number"
+
+	"github.com/weiwenchen2022/builder"
+)
+
+// WriteInt appends i to b formatted for tag, such as "1,234,567" for
+// language.English and "1.234.567" for language.German.
+// It returns the length of written and a nil error.
+func WriteInt(b *builder.Builder, tag language.Tag, i int64) (int, error) {
+	return message.NewPrinter(tag).Fprint(b, number.Decimal(i))
+}
+
+// WriteFloat appends f to b formatted for tag with exactly prec fraction
+// digits, such as "1,234.50" for language.English and prec 2. A negative
+// prec uses the default of the language, at most three fraction digits
+// for most of them, omitting trailing zeros.
+// It returns the length of written and a nil error.
+func WriteFloat(b *builder.Builder, tag language.Tag, f float64, prec int) (int, error) {
+	if prec < 0 {
+		return message.NewPrinter(tag).Fprint(b, number.Decimal(f))
+	}
+	return message.NewPrinter(tag).Fprint(b, number.Decimal(f,
+		number.MinFractionDigits(prec), number.MaxFractionDigits(prec)))
+}
@@ -0,0 +1,70 @@
+// Copyright 2023 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package localeutil_test
+
+import (
+	"testing"
+
+	"golang.org/x/text/language"
+
+	"github.com/weiwenchen2022/builder"
+	. "github.com/weiwenchen2022/builder/localeutil"
+)
+
+func TestWriteInt(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		tag  language.Tag
+		i    int64
+		want string
+	}{
+		{language.English, 0, "0"},
+		{language.English, -1234567, "-1,234,567"},
+		{language.German, 1234567, "1.234.567"},
+		{language.French, 1234567, "1\u00a0234\u00a0567"},
+		{language.MustParse("de-CH"), 1234567, "1\u2019234\u2019567"},
+		{language.Arabic, 1234, "\u0661\u066c\u0662\u0663\u0664"},
+	}
+	for _, tt := range tests {
+		var b builder.Builder
+		b.WriteString("n=")
+		n, err := WriteInt(&b, tt.tag, tt.i)
+		if err != nil || n != len(tt.want) {
+			t.Errorf("WriteInt(%v, %d): got %d,%v; want %d,nil", tt.tag, tt.i, n, err, len(tt.want))
+		}
+		if got := b.String(); got != "n="+tt.want {
+			t.Errorf("WriteInt(%v, %d): got %q; want %q", tt.tag, tt.i, got, "n="+tt.want)
+		}
+	}
+}
+
+func TestWriteFloat(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		tag  language.Tag
+		f    float64
+		prec int
+		want string
+	}{
+		{language.English, 1234.5, 2, "1,234.50"},
+		{language.English, 0.123456, -1, "0.123"},
+		{language.English, 1.5, 0, "2"},
+		{language.German, 1234.5, 2, "1.234,50"},
+		{language.French, -1234.5, 1, "-1\u00a0234,5"},
+		{language.Arabic, 0.5, 1, "\u0660\u066b\u0665"},
+	}
+	for _, tt := range tests {
+		var b builder.Builder
+		n, err := WriteFloat(&b, tt.tag, tt.f, tt.prec)
+		if err != nil || n != len(tt.want) {
+			t.Errorf("WriteFloat(%v, %v, %d): got %d,%v; want %d,nil", tt.tag, tt.f, tt.prec, n, err, len(tt.want))
+		}
+		if got := b.String(); got != tt.want {
+			t.Errorf("WriteFloat(%v, %v, %d): got %q; want %q", tt.tag, tt.f, tt.prec, got, tt.want)
+		}
+	}
+}
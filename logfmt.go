@@ -0,0 +1,188 @@
+// Copyright 2023 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package builder
+
+import (
+	"encoding"
+	"errors"
+	"unicode/utf8"
+)
+
+var (
+	errLogfmtKey     = errors.New("builder: invalid logfmt key")
+	errLogfmtKeyvals = errors.New("builder: WriteKVs needs alternating string keys and values")
+)
+
+// WriteKV appends the logfmt pair key=value to b's buffer, preceded by a
+// space unless b is empty or ends with a space or newline.
+//
+// The value is formatted like the %v verb, except that nil is written as
+// null and values implementing encoding.TextMarshaler are written using
+// MarshalText. It is quoted, with JSON escapes, if it is empty or contains
+// characters that logfmt requires quoting: spaces, control characters,
+// '=', '"' and invalid UTF-8. A value that reads null but is not nil is
+// quoted too.
+//
+// It returns the length of written. If key is empty or contains a space,
+// '=', '"', a control character or invalid UTF-8, or if MarshalText fails,
+// b is left unchanged and an error is returned.
+func (b *Builder) WriteKV(key string, value any) (int, error) {
+	if !validLogfmtKey(key) {
+		return 0, errLogfmtKey
+	}
+
+	b.copyCheck()
+	n := len(b.buf)
+	buf, err := b.appendKV(b.buf, key, value)
+	if err != nil {
+		b.rollback(n)
+		return 0, err
+	}
+	b.buf = buf
+	return b.commit(n), nil
+}
+
+// WriteKVs appends the logfmt pairs given by keyvals, which alternates
+// string keys and their values, as a single write. Each pair is written as
+// by WriteKV.
+// It returns the length of written. On error, b is left unchanged.
+func (b *Builder) WriteKVs(keyvals ...any) (int, error) {
+	if len(keyvals)%2 != 0 {
+		return 0, errLogfmtKeyvals
+	}
+	for i := 0; i < len(keyvals); i += 2 {
+		key, ok := keyvals[i].(string)
+		if !ok {
+			return 0, errLogfmtKeyvals
+		}
+		if !validLogfmtKey(key) {
+			return 0, errLogfmtKey
+		}
+	}
+
+	b.copyCheck()
+	n := len(b.buf)
+	buf := b.buf
+	for i := 0; i < len(keyvals); i += 2 {
+		var err error
+		if buf, err = b.appendKV(buf, keyvals[i].(string), keyvals[i+1]); err != nil {
+			b.rollback(n)
+			return 0, err
+		}
+	}
+	b.buf = buf
+	return b.commit(n), nil
+}
+
+// WriteGroupKV is like WriteKV, but the key is qualified by groups: each
+// group and then key are written joined with dots, as log/slog qualifies
+// the keys of grouped attributes. Every group must be a valid key.
+// It returns the length of written. On error, b is left unchanged.
+func (b *Builder) WriteGroupKV(groups []string, key string, value any) (int, error) {
+	for _, g := range groups {
+		if !validLogfmtKey(g) {
+			return 0, errLogfmtKey
+		}
+	}
+	if !validLogfmtKey(key) {
+		return 0, errLogfmtKey
+	}
+
+	b.copyCheck()
+	n := len(b.buf)
+	buf := appendKVSeparator(b.buf)
+	for _, g := range groups {
+		buf = append(buf, g...)
+		buf = append(buf, '.')
+	}
+	buf = append(buf, key...)
+	buf, err := b.appendKVValue(buf, value)
+	if err != nil {
+		b.rollback(n)
+		return 0, err
+	}
+	b.buf = buf
+	return b.commit(n), nil
+}
+
+func validLogfmtKey(key string) bool {
+	if key == "" {
+		return false
+	}
+	for _, r := range key {
+		if needsLogfmtQuote(r) {
+			return false
+		}
+	}
+	return true
+}
+
+func needsLogfmtQuote(r rune) bool {
+	return r <= ' ' || r == '=' || r == '"' || r == 0x7f || r == utf8.RuneError
+}
+
+func (b *Builder) appendKV(dst []byte, key string, value any) ([]byte, error) {
+	dst = appendKVSeparator(dst)
+	dst = append(dst, key...)
+	return b.appendKVValue(dst, value)
+}
+
+func appendKVSeparator(dst []byte) []byte {
+	if len(dst) > 0 {
+		if c := dst[len(dst)-1]; c != ' ' && c != '\n' {
+			dst = append(dst, ' ')
+		}
+	}
+	return dst
+}
+
+// appendKVValue appends '=' and value to dst, which ends with a key.
+func (b *Builder) appendKVValue(dst []byte, value any) ([]byte, error) {
+	dst = append(dst, '=')
+
+	v := len(dst)
+	switch value := value.(type) {
+	case nil:
+		return append(dst, "null"...), nil
+	case string:
+		return appendLogfmtValue(dst, value), nil
+	case encoding.TextMarshaler:
+		text, err := value.MarshalText()
+		if err != nil {
+			return dst, err
+		}
+		return appendLogfmtValue(dst, string(text)), nil
+	}
+
+	dst, _ = b.appendArg(dst, 'v', value)
+	s := dst[v:]
+	if len(s) > 0 && string(s) != "null" && !bytesNeedLogfmtQuote(s) {
+		return dst, nil
+	}
+	return appendLogfmtValue(dst[:v], string(s)), nil
+}
+
+func bytesNeedLogfmtQuote(p []byte) bool {
+	for i := 0; i < len(p); {
+		r, size := utf8.DecodeRune(p[i:])
+		if needsLogfmtQuote(r) {
+			return true
+		}
+		i += size
+	}
+	return false
+}
+
+func appendLogfmtValue(dst []byte, s string) []byte {
+	if s == "" || s == "null" {
+		return appendJSONString(dst, s)
+	}
+	for _, r := range s {
+		if needsLogfmtQuote(r) {
+			return appendJSONString(dst, s)
+		}
+	}
+	return append(dst, s...)
+}
@@ -0,0 +1,133 @@
+// Copyright 2023 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package builder_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	. "github.com/weiwenchen2022/builder"
+)
+
+type level int
+
+func (l level) String() string { return [...]string{"debug", "info", "warn error"}[l] }
+
+type badText struct{}
+
+func (badText) MarshalText() ([]byte, error) { return nil, errors.New("bad") }
+
+func TestBuilderWriteKV(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		key   string
+		value any
+		want  string
+	}{
+		{"msg", "hello", "msg=hello"},
+		{"msg", "hello world", `msg="hello world"`},
+		{"msg", "", `msg=""`},
+		{"msg", "a=b", `msg="a=b"`},
+		{"msg", `say "hi"`, `msg="say \"hi\""`},
+		{"msg", "line\nbreak", `msg="line\nbreak"`},
+		{"msg", "bad\xff", `msg="bad\ufffd"`},
+		{"msg", "héllo", "msg=héllo"},
+		{"v", nil, "v=null"},
+		{"v", "null", `v="null"`},
+		{"n", 42, "n=42"},
+		{"n", int8(-3), "n=-3"},
+		{"u", uint64(7), "u=7"},
+		{"f", 1.5, "f=1.5"},
+		{"ok", true, "ok=true"},
+		{"err", errors.New("not found"), `err="not found"`},
+		{"level", level(1), "level=info"},
+		{"level", level(2), `level="warn error"`},
+		{"t", time.Date(2024, 2, 14, 1, 2, 3, 0, time.UTC), "t=2024-02-14T01:02:03Z"},
+		{"d", 1500 * time.Millisecond, "d=1.5s"},
+		{"s", []int{1, 2}, `s="[1 2]"`},
+	}
+
+	for _, tt := range tests {
+		var b Builder
+		n, err := b.WriteKV(tt.key, tt.value)
+		if err != nil || len(tt.want) != n {
+			t.Errorf("WriteKV(%q, %#v): got %d,%v; want %d,nil", tt.key, tt.value, n, err, len(tt.want))
+		}
+		check(t, &b, tt.want)
+	}
+}
+
+func TestBuilderWriteKVSpacing(t *testing.T) {
+	t.Parallel()
+
+	var b Builder
+	b.WriteKV("a", 1)
+	b.WriteKV("b", 2)
+	b.WriteByte('\n')
+	b.WriteKV("c", 3)
+	b.WriteString(" ")
+	b.WriteKV("d", 4)
+	check(t, &b, "a=1 b=2\nc=3 d=4")
+}
+
+func TestBuilderWriteKVError(t *testing.T) {
+	t.Parallel()
+
+	var b Builder
+	b.WriteString("keep")
+	for _, key := range []string{"", "a b", "a=b", `a"b`, "a\nb", "bad\xff"} {
+		if n, err := b.WriteKV(key, 1); err == nil || n != 0 {
+			t.Errorf("WriteKV(%q, 1): got %d,%v; want 0,error", key, n, err)
+		}
+	}
+	if n, err := b.WriteKV("k", badText{}); err == nil || n != 0 {
+		t.Errorf("WriteKV with failing MarshalText: got %d,%v; want 0,error", n, err)
+	}
+	check(t, &b, "keep")
+}
+
+func TestBuilderWriteGroupKV(t *testing.T) {
+	t.Parallel()
+
+	var b Builder
+	b.WriteGroupKV(nil, "a", 1)
+	n, err := b.WriteGroupKV([]string{"http", "req"}, "note", "two words")
+	const want = `a=1 http.req.note="two words"`
+	if err != nil || n != len(want)-len("a=1") {
+		t.Errorf("WriteGroupKV: got %d,%v; want %d,nil", n, err, len(want)-len("a=1"))
+	}
+	check(t, &b, want)
+
+	for _, groups := range [][]string{{""}, {"ok", "a b"}, {"a=b"}} {
+		if n, err := b.WriteGroupKV(groups, "k", 1); err == nil || n != 0 {
+			t.Errorf("WriteGroupKV(%q, \"k\", 1): got %d,%v; want 0,error", groups, n, err)
+		}
+	}
+	if n, err := b.WriteGroupKV([]string{"g"}, "k", badText{}); err == nil || n != 0 {
+		t.Errorf("WriteGroupKV with failing MarshalText: got %d,%v; want 0,error", n, err)
+	}
+	check(t, &b, want)
+}
+
+func TestBuilderWriteKVs(t *testing.T) {
+	t.Parallel()
+
+	var b Builder
+	n, err := b.WriteKVs("level", "info", "msg", "request done", "status", 200)
+	const want = `level=info msg="request done" status=200`
+	if err != nil || n != len(want) {
+		t.Errorf("WriteKVs: got %d,%v; want %d,nil", n, err, len(want))
+	}
+	check(t, &b, want)
+
+	for _, kv := range [][]any{{"odd"}, {1, 2}, {"a", 1, "b c", 2}, {"a", 1, "b", badText{}}} {
+		if n, err := b.WriteKVs(kv...); err == nil || n != 0 {
+			t.Errorf("WriteKVs(%v): got %d,%v; want 0,error", kv, n, err)
+		}
+	}
+	check(t, &b, want)
+}
@@ -0,0 +1,199 @@
+// Copyright 2023 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package builder
+
+import (
+	"encoding/binary"
+	"math"
+)
+
+// A MsgPackWriter appends MessagePack-encoded scalars and container headers
+// to a Builder, always choosing the most compact format. The elements of an
+// array or map are written by further calls after its header.
+type MsgPackWriter struct {
+	b *Builder
+}
+
+// MsgPack returns a MsgPackWriter that writes to b.
+func (b *Builder) MsgPack() *MsgPackWriter {
+	return &MsgPackWriter{b: b}
+}
+
+// Nil writes nil.
+func (w *MsgPackWriter) Nil() {
+	b := w.b
+	b.copyCheck()
+	n := len(b.buf)
+	b.buf = append(b.buf, 0xc0)
+	b.commit(n)
+}
+
+// Bool writes v.
+func (w *MsgPackWriter) Bool(v bool) {
+	b := w.b
+	b.copyCheck()
+	n := len(b.buf)
+	if v {
+		b.buf = append(b.buf, 0xc3)
+	} else {
+		b.buf = append(b.buf, 0xc2)
+	}
+	b.commit(n)
+}
+
+// Int writes i.
+func (w *MsgPackWriter) Int(i int64) {
+	b := w.b
+	b.copyCheck()
+	n := len(b.buf)
+	b.buf = appendMsgPackInt(b.buf, i)
+	b.commit(n)
+}
+
+// Uint writes u.
+func (w *MsgPackWriter) Uint(u uint64) {
+	b := w.b
+	b.copyCheck()
+	n := len(b.buf)
+	b.buf = appendMsgPackUint(b.buf, u)
+	b.commit(n)
+}
+
+// Float32 writes f as a single-precision float.
+func (w *MsgPackWriter) Float32(f float32) {
+	b := w.b
+	b.copyCheck()
+	n := len(b.buf)
+	b.buf = append(b.buf, 0xca)
+	b.buf = binary.BigEndian.AppendUint32(b.buf, math.Float32bits(f))
+	b.commit(n)
+}
+
+// Float64 writes f as a double-precision float.
+func (w *MsgPackWriter) Float64(f float64) {
+	b := w.b
+	b.copyCheck()
+	n := len(b.buf)
+	b.buf = append(b.buf, 0xcb)
+	b.buf = binary.BigEndian.AppendUint64(b.buf, math.Float64bits(f))
+	b.commit(n)
+}
+
+// String writes s using the str format family.
+// It panics if s is longer than math.MaxUint32 bytes, the most MessagePack
+// can represent.
+func (w *MsgPackWriter) String(s string) {
+	if uint64(len(s)) > math.MaxUint32 {
+		panic("builder.MsgPackWriter.String: length too large")
+	}
+	b := w.b
+	b.copyCheck()
+	n := len(b.buf)
+	switch l := len(s); {
+	case l < 32:
+		b.buf = append(b.buf, 0xa0|byte(l))
+	case l <= math.MaxUint8:
+		b.buf = append(b.buf, 0xd9, byte(l))
+	case l <= math.MaxUint16:
+		b.buf = binary.BigEndian.AppendUint16(append(b.buf, 0xda), uint16(l))
+	default:
+		b.buf = binary.BigEndian.AppendUint32(append(b.buf, 0xdb), uint32(l))
+	}
+	b.buf = append(b.buf, s...)
+	b.commit(n)
+}
+
+// Binary writes p using the bin format family.
+// It panics if p is longer than math.MaxUint32 bytes, the most MessagePack
+// can represent.
+func (w *MsgPackWriter) Binary(p []byte) {
+	if uint64(len(p)) > math.MaxUint32 {
+		panic("builder.MsgPackWriter.Binary: length too large")
+	}
+	b := w.b
+	b.copyCheck()
+	n := len(b.buf)
+	switch l := len(p); {
+	case l <= math.MaxUint8:
+		b.buf = append(b.buf, 0xc4, byte(l))
+	case l <= math.MaxUint16:
+		b.buf = binary.BigEndian.AppendUint16(append(b.buf, 0xc5), uint16(l))
+	default:
+		b.buf = binary.BigEndian.AppendUint32(append(b.buf, 0xc6), uint32(l))
+	}
+	b.buf = append(b.buf, p...)
+	b.commit(n)
+}
+
+// ArrayHeader writes the header of an array of length elements.
+// It panics if length is negative or greater than math.MaxUint32.
+func (w *MsgPackWriter) ArrayHeader(length int) {
+	if length < 0 {
+		panic("builder.MsgPackWriter.ArrayHeader: negative length")
+	}
+	if uint64(length) > math.MaxUint32 {
+		panic("builder.MsgPackWriter.ArrayHeader: length too large")
+	}
+	w.header(length, 0x90, 0xdc)
+}
+
+// MapHeader writes the header of a map of length key-value pairs.
+// It panics if length is negative or greater than math.MaxUint32.
+func (w *MsgPackWriter) MapHeader(length int) {
+	if length < 0 {
+		panic("builder.MsgPackWriter.MapHeader: negative length")
+	}
+	if uint64(length) > math.MaxUint32 {
+		panic("builder.MsgPackWriter.MapHeader: length too large")
+	}
+	w.header(length, 0x80, 0xde)
+}
+
+// header writes an array or map header using the fix format fix or the
+// 16-bit format code16, which the 32-bit format code follows.
+func (w *MsgPackWriter) header(length int, fix, code16 byte) {
+	b := w.b
+	b.copyCheck()
+	n := len(b.buf)
+	switch {
+	case length < 16:
+		b.buf = append(b.buf, fix|byte(length))
+	case length <= math.MaxUint16:
+		b.buf = binary.BigEndian.AppendUint16(append(b.buf, code16), uint16(length))
+	default:
+		b.buf = binary.BigEndian.AppendUint32(append(b.buf, code16+1), uint32(length))
+	}
+	b.commit(n)
+}
+
+func appendMsgPackUint(dst []byte, u uint64) []byte {
+	switch {
+	case u < 0x80:
+		return append(dst, byte(u))
+	case u <= math.MaxUint8:
+		return append(dst, 0xcc, byte(u))
+	case u <= math.MaxUint16:
+		return binary.BigEndian.AppendUint16(append(dst, 0xcd), uint16(u))
+	case u <= math.MaxUint32:
+		return binary.BigEndian.AppendUint32(append(dst, 0xce), uint32(u))
+	}
+	return binary.BigEndian.AppendUint64(append(dst, 0xcf), u)
+}
+
+func appendMsgPackInt(dst []byte, i int64) []byte {
+	switch {
+	case i >= 0:
+		return appendMsgPackUint(dst, uint64(i))
+	case i >= -32:
+		return append(dst, byte(i))
+	case i >= math.MinInt8:
+		return append(dst, 0xd0, byte(i))
+	case i >= math.MinInt16:
+		return binary.BigEndian.AppendUint16(append(dst, 0xd1), uint16(i))
+	case i >= math.MinInt32:
+		return binary.BigEndian.AppendUint32(append(dst, 0xd2), uint32(i))
+	}
+	return binary.BigEndian.AppendUint64(append(dst, 0xd3), uint64(i))
+}
@@ -0,0 +1,49 @@
+// Copyright 2023 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build !race
+
+package builder_test
+
+import (
+	"math"
+	"testing"
+	"unsafe"
+
+	. "github.com/weiwenchen2022/builder"
+)
+
+func TestMsgPackWriterLengthPanics(t *testing.T) {
+	t.Parallel()
+
+	const tooLong = uint64(math.MaxUint32) + 1
+	if uint64(int(tooLong)) != tooLong {
+		t.Skip("int cannot hold lengths beyond math.MaxUint32")
+	}
+	// The strings and slices below claim more bytes than they own, which
+	// the race detector's pointer checks reject, hence the build constraint.
+	// The length check must panic before any of them is read.
+	var x byte
+	tests := []struct {
+		name string
+		fn   func(w *MsgPackWriter)
+	}{
+		{"String", func(w *MsgPackWriter) { w.String(unsafe.String(&x, int(tooLong))) }},
+		{"Binary", func(w *MsgPackWriter) { w.Binary(unsafe.Slice(&x, int(tooLong))) }},
+		{"ArrayHeader", func(w *MsgPackWriter) { w.ArrayHeader(int(tooLong)) }},
+		{"MapHeader", func(w *MsgPackWriter) { w.MapHeader(int(tooLong)) }},
+	}
+	for _, tt := range tests {
+		var b Builder
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("%s with length %d did not panic", tt.name, tooLong)
+				}
+			}()
+			tt.fn(b.MsgPack())
+		}()
+		check(t, &b, "")
+	}
+}
@@ -0,0 +1,72 @@
+// Copyright 2023 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package builder_test
+
+import (
+	"encoding/hex"
+	"math"
+	"strings"
+	"testing"
+
+	. "github.com/weiwenchen2022/builder"
+)
+
+func TestMsgPackWriter(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name  string
+		write func(w *MsgPackWriter)
+		want  string // hex
+	}{
+		{"nil", func(w *MsgPackWriter) { w.Nil() }, "c0"},
+		{"false", func(w *MsgPackWriter) { w.Bool(false) }, "c2"},
+		{"true", func(w *MsgPackWriter) { w.Bool(true) }, "c3"},
+		{"0", func(w *MsgPackWriter) { w.Int(0) }, "00"},
+		{"127", func(w *MsgPackWriter) { w.Int(127) }, "7f"},
+		{"128", func(w *MsgPackWriter) { w.Int(128) }, "cc80"},
+		{"256", func(w *MsgPackWriter) { w.Uint(256) }, "cd0100"},
+		{"65536", func(w *MsgPackWriter) { w.Uint(65536) }, "ce00010000"},
+		{"MaxUint64", func(w *MsgPackWriter) { w.Uint(math.MaxUint64) }, "cfffffffffffffffff"},
+		{"-1", func(w *MsgPackWriter) { w.Int(-1) }, "ff"},
+		{"-32", func(w *MsgPackWriter) { w.Int(-32) }, "e0"},
+		{"-33", func(w *MsgPackWriter) { w.Int(-33) }, "d0df"},
+		{"-129", func(w *MsgPackWriter) { w.Int(-129) }, "d1ff7f"},
+		{"-32769", func(w *MsgPackWriter) { w.Int(-32769) }, "d2ffff7fff"},
+		{"MinInt64", func(w *MsgPackWriter) { w.Int(math.MinInt64) }, "d38000000000000000"},
+		{"float32", func(w *MsgPackWriter) { w.Float32(1.5) }, "ca3fc00000"},
+		{"float64", func(w *MsgPackWriter) { w.Float64(1.5) }, "cb3ff8000000000000"},
+		{"fixstr", func(w *MsgPackWriter) { w.String("abc") }, "a3616263"},
+		{"str8", func(w *MsgPackWriter) { w.String(strings.Repeat("a", 32)) }, "d920" + strings.Repeat("61", 32)},
+		{"str16", func(w *MsgPackWriter) { w.String(strings.Repeat("a", 256)) }, "da0100" + strings.Repeat("61", 256)},
+		{"bin8", func(w *MsgPackWriter) { w.Binary([]byte{1, 2}) }, "c4020102"},
+		{"fixarray", func(w *MsgPackWriter) { w.ArrayHeader(3) }, "93"},
+		{"array16", func(w *MsgPackWriter) { w.ArrayHeader(16) }, "dc0010"},
+		{"array32", func(w *MsgPackWriter) { w.ArrayHeader(1 << 16) }, "dd00010000"},
+		{"fixmap", func(w *MsgPackWriter) { w.MapHeader(1) }, "81"},
+		{"map16", func(w *MsgPackWriter) { w.MapHeader(300) }, "de012c"},
+	}
+
+	for _, tt := range tests {
+		var b Builder
+		tt.write(b.MsgPack())
+		if got := hex.EncodeToString([]byte(b.String())); tt.want != got {
+			t.Errorf("%s: got %s; want %s", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestMsgPackWriterEnvelope(t *testing.T) {
+	t.Parallel()
+
+	var b Builder
+	w := b.MsgPack()
+	w.MapHeader(2)
+	w.String("id")
+	w.Int(7)
+	w.String("body")
+	w.String("hi")
+	check(t, &b, "\x82\xa2id\x07\xa4body\xa2hi")
+}
@@ -0,0 +1,51 @@
+// Copyright 2023 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package builder
+
+// defaultChunkSize is the chunk size of a zero MultiBuilder.
+const defaultChunkSize = 4096
+
+// A MultiBuilder builds many short strings out of a few large backing
+// allocations. Its embedded Builder accumulates the string being built;
+// Finish returns that string and starts the next one right after it in the
+// same chunk, so producing many strings costs about one allocation per chunk
+// rather than one per string.
+//
+// Finished strings are never modified, but each of them keeps its whole chunk
+// alive, so a MultiBuilder suits strings with similar lifetimes.
+// The zero value is ready to use. Do not copy a non-zero MultiBuilder.
+type MultiBuilder struct {
+	Builder
+	chunkSize int
+}
+
+// NewMultiBuilder returns a MultiBuilder that allocates chunks of chunkSize bytes.
+func NewMultiBuilder(chunkSize int) *MultiBuilder {
+	if chunkSize <= 0 {
+		panic("builder.NewMultiBuilder: non-positive chunk size")
+	}
+	m := &MultiBuilder{chunkSize: chunkSize}
+	m.buf = make([]byte, 0, chunkSize)
+	return m
+}
+
+// Finish returns the string built since the previous call to Finish and
+// starts a new, empty one.
+func (m *MultiBuilder) Finish() string {
+	m.copyCheck()
+	s := m.String()
+
+	// Continue in the unused tail of the chunk. When little of it is left,
+	// start a new chunk rather than growing small strings piecemeal.
+	m.buf = m.buf[len(m.buf):]
+	size := m.chunkSize
+	if size == 0 {
+		size = defaultChunkSize
+	}
+	if cap(m.buf) < size/4 {
+		m.buf = make([]byte, 0, size)
+	}
+	return s
+}
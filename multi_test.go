@@ -0,0 +1,69 @@
+// Copyright 2023 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package builder_test
+
+import (
+	"strconv"
+	"strings"
+	"testing"
+
+	. "github.com/weiwenchen2022/builder"
+)
+
+func TestMultiBuilder(t *testing.T) {
+	t.Parallel()
+
+	for _, m := range []*MultiBuilder{new(MultiBuilder), NewMultiBuilder(64)} {
+		var got []string
+		for i := 0; i < 100; i++ {
+			m.WriteString("field-")
+			m.WriteInt(int64(i), 10)
+			if i%10 == 0 {
+				// Occasionally build a string larger than a chunk.
+				m.WriteString(strings.Repeat("x", 100))
+			}
+			got = append(got, m.Finish())
+		}
+		if s := m.Finish(); s != "" {
+			t.Errorf("Finish with nothing written: got %q; want empty", s)
+		}
+
+		for i, s := range got {
+			want := "field-" + strconv.Itoa(i)
+			if i%10 == 0 {
+				want += strings.Repeat("x", 100)
+			}
+			if want != s {
+				t.Errorf("string %d: got %q; want %q", i, s, want)
+			}
+		}
+	}
+}
+
+func TestMultiBuilderAllocs(t *testing.T) {
+	m := NewMultiBuilder(4096)
+	allocs := testing.AllocsPerRun(1, func() {
+		for i := 0; i < 1000; i++ {
+			m.WriteString("token")
+			m.WriteInt(int64(i), 10)
+			_ = m.Finish()
+		}
+	})
+	// Roughly 8 bytes per string: two or three chunks per run.
+	if allocs > 5 {
+		t.Errorf("MultiBuilder allocs for 1000 strings = %v; want <= 5", allocs)
+	}
+}
+
+func TestNewMultiBuilderPanic(t *testing.T) {
+	t.Parallel()
+
+	defer func() {
+		if recover() == nil {
+			t.Error("NewMultiBuilder(0) should panic")
+		}
+	}()
+	NewMultiBuilder(0)
+}
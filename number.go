@@ -0,0 +1,332 @@
+// Copyright 2023 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package builder
+
+import (
+	"bytes"
+	"math"
+	"strconv"
+	"unicode/utf8"
+)
+
+// WriteDecimal appends the exact decimal representation of the fixed-point
+// number unscaled × 10^-scale to b's buffer, without going through floating
+// point: WriteDecimal(12345, 2) writes "123.45" and WriteDecimal(-5, 3)
+// writes "-0.005". A negative scale appends zeros, so WriteDecimal(12, -2)
+// writes "1200".
+// It returns the length of written and a nil error.
+func (b *Builder) WriteDecimal(unscaled int64, scale int) (int, error) {
+	b.copyCheck()
+	n := len(b.buf)
+	b.buf = appendDecimal(b.buf, unscaled, scale)
+	return b.commit(n), nil
+}
+
+func appendDecimal(dst []byte, unscaled int64, scale int) []byte {
+	u := uint64(unscaled)
+	if unscaled < 0 {
+		dst = append(dst, '-')
+		u = -u
+	}
+
+	if scale <= 0 {
+		dst = strconv.AppendUint(dst, u, 10)
+		if u != 0 {
+			for ; scale < 0; scale++ {
+				dst = append(dst, '0')
+			}
+		}
+		return dst
+	}
+
+	var scratch [20]byte
+	digits := strconv.AppendUint(scratch[:0], u, 10)
+	if len(digits) <= scale {
+		dst = append(dst, '0', '.')
+		for i := len(digits); i < scale; i++ {
+			dst = append(dst, '0')
+		}
+		return append(dst, digits...)
+	}
+	dst = append(dst, digits[:len(digits)-scale]...)
+	dst = append(dst, '.')
+	return append(dst, digits[len(digits)-scale:]...)
+}
+
+// WriteIntPadded appends the string form of the integer i in the given base,
+// as generated by FormatInt, left-padded with zeros to width bytes, to b's
+// buffer. As with fmt's %0*d, the width includes the sign, which precedes the
+// zeros: WriteIntPadded(-42, 10, 5) writes "-0042".
+// It returns the length of written and a nil error.
+func (b *Builder) WriteIntPadded(i int64, base, width int) (int, error) {
+	b.copyCheck()
+	n := len(b.buf)
+	u := uint64(i)
+	if i < 0 {
+		b.buf = append(b.buf, '-')
+		u = -u
+		width--
+	}
+	b.buf = appendUintPadded(b.buf, u, base, width)
+	return b.commit(n), nil
+}
+
+// WriteUintPadded appends the string form of the unsigned integer i in the
+// given base, as generated by FormatUint, left-padded with zeros to width
+// bytes, to b's buffer.
+// It returns the length of written and a nil error.
+func (b *Builder) WriteUintPadded(i uint64, base, width int) (int, error) {
+	b.copyCheck()
+	n := len(b.buf)
+	b.buf = appendUintPadded(b.buf, i, base, width)
+	return b.commit(n), nil
+}
+
+func appendUintPadded(dst []byte, u uint64, base, width int) []byte {
+	var scratch [64]byte
+	digits := strconv.AppendUint(scratch[:0], u, base)
+	for k := len(digits); k < width; k++ {
+		dst = append(dst, '0')
+	}
+	return append(dst, digits...)
+}
+
+// WriteIntGrouped appends the decimal form of the integer i to b's buffer
+// with sep between groups of three digits, such as "1,234,567" for sep ','
+// or "-1 234" for sep ' '.
+// It returns the length of written and a nil error.
+func (b *Builder) WriteIntGrouped(i int64, sep rune) (int, error) {
+	b.copyCheck()
+	n := len(b.buf)
+	u := uint64(i)
+	if i < 0 {
+		b.buf = append(b.buf, '-')
+		u = -u
+	}
+
+	b.buf = appendUintGrouped(b.buf, u, sep)
+	return b.commit(n), nil
+}
+
+// appendUintGrouped appends the decimal form of u, with sep between groups of
+// three digits, to dst.
+func appendUintGrouped(dst []byte, u uint64, sep rune) []byte {
+	var scratch [20]byte
+	digits := strconv.AppendUint(scratch[:0], u, 10)
+	k := (len(digits)-1)%3 + 1 // length of the first group
+	dst = append(dst, digits[:k]...)
+	for ; k < len(digits); k += 3 {
+		dst = utf8.AppendRune(dst, sep)
+		dst = append(dst, digits[k:k+3]...)
+	}
+	return dst
+}
+
+// A ByteUnits selects the units written by WriteHumanBytes.
+type ByteUnits int
+
+const (
+	BinaryUnits  ByteUnits = iota // KiB, MiB, ...: powers of 1024, as in "1.5 MiB"
+	DecimalUnits                  // kB, MB, ...: powers of 1000, as in "3.2 GB"
+)
+
+var (
+	binaryByteUnits  = [...]string{"B", "KiB", "MiB", "GiB", "TiB", "PiB", "EiB"}
+	decimalByteUnits = [...]string{"B", "kB", "MB", "GB", "TB", "PB", "EB"}
+)
+
+// WriteHumanBytes appends the byte count n to b's buffer in the largest of
+// the given units that leaves a value of at least one, with one decimal
+// place, such as "1.5 MiB" or "3.2 GB". Counts below one kilobyte are written
+// exactly, as in "512 B".
+// It returns the length of written and a nil error.
+func (b *Builder) WriteHumanBytes(n int64, units ByteUnits) (int, error) {
+	base, names := 1024.0, binaryByteUnits[:]
+	if units == DecimalUnits {
+		base, names = 1000, decimalByteUnits[:]
+	}
+
+	b.copyCheck()
+	m := len(b.buf)
+	u := uint64(n)
+	if n < 0 {
+		b.buf = append(b.buf, '-')
+		u = -u
+	}
+	if float64(u) < base {
+		b.buf = strconv.AppendUint(b.buf, u, 10)
+		b.buf = append(b.buf, " B"...)
+		return b.commit(m), nil
+	}
+
+	v, e := float64(u), 0
+	// Move to the next unit while the value, rounded, would be a full one.
+	for e+1 < len(names) && math.Round(v*10)/10 >= base {
+		v /= base
+		e++
+	}
+	b.buf = strconv.AppendFloat(b.buf, v, 'f', 1, 64)
+	b.buf = append(b.buf, ' ')
+	b.buf = append(b.buf, names[e]...)
+	return b.commit(m), nil
+}
+
+// WriteOrdinal appends the English ordinal form of n, such as "1st", "2nd",
+// "3rd", "11th" or "-22nd", to b's buffer.
+// It returns the length of written and a nil error.
+func (b *Builder) WriteOrdinal(n int) (int, error) {
+	b.copyCheck()
+	m := len(b.buf)
+	b.buf = strconv.AppendInt(b.buf, int64(n), 10)
+	u := uint64(n)
+	if n < 0 {
+		u = -u
+	}
+	suffix := "th"
+	if u%100/10 != 1 { // 11th, 12th and 13th are exceptions
+		switch u % 10 {
+		case 1:
+			suffix = "st"
+		case 2:
+			suffix = "nd"
+		case 3:
+			suffix = "rd"
+		}
+	}
+	b.buf = append(b.buf, suffix...)
+	return b.commit(m), nil
+}
+
+// siPrefixes are the SI prefixes from quecto (10^-30) to quetta (10^30).
+var siPrefixes = [...]string{
+	"q", "r", "y", "z", "a", "f", "p", "n", "µ", "m",
+	"",
+	"k", "M", "G", "T", "P", "E", "Z", "Y", "R", "Q",
+}
+
+// WriteSI appends value to b's buffer scaled by the SI prefix that leaves
+// between one and three digits before the decimal point, followed by a space,
+// the prefix and unit: WriteSI(3300, "Ω", 2) writes "3.3 kΩ" and
+// WriteSI(12e-6, "s", -1) writes "12 µs". prec is the number of significant
+// digits; the special precision -1 uses the smallest number of digits
+// necessary to represent value exactly. Rounding that carries into a new
+// group of three digits moves to the next prefix, as in "1.00 MHz".
+// It returns the length of written and a nil error.
+func (b *Builder) WriteSI(value float64, unit string, prec int) (int, error) {
+	b.copyCheck()
+	n := len(b.buf)
+	b.buf = b.appendSI(b.buf, value, unit, prec)
+	return b.commit(n), nil
+}
+
+func (b *Builder) appendSI(dst []byte, value float64, unit string, prec int) []byte {
+	if value == 0 || math.IsNaN(value) || math.IsInf(value, 0) {
+		dst = b.appendFloatValue(dst, value, 'g', -1, 64)
+		if unit != "" {
+			dst = append(dst, ' ')
+			dst = append(dst, unit...)
+		}
+		return dst
+	}
+
+	if prec >= 0 {
+		prec--
+		if prec < 0 {
+			prec = 0
+		}
+	}
+	// Format as d.ddde±xx, then move the decimal point textually so that
+	// no rounding error is introduced by scaling.
+	var scratch [32]byte
+	e := strconv.AppendFloat(scratch[:0], value, 'e', prec, 64)
+	if e[0] == '-' {
+		dst = append(dst, '-')
+		e = e[1:]
+	}
+	i := bytes.IndexByte(e, 'e')
+	exp, _ := strconv.Atoi(string(e[i+1:]))
+	var d [32]byte
+	digits := append(d[:0], e[0])
+	if i > 1 {
+		digits = append(digits, e[2:i]...)
+	}
+
+	group := exp
+	if group < 0 {
+		group -= 2
+	}
+	group /= 3
+	const maxGroup = len(siPrefixes) / 2
+	if group > maxGroup {
+		group = maxGroup
+	} else if group < -maxGroup {
+		group = -maxGroup
+	}
+
+	intDigits := exp - 3*group + 1
+	switch {
+	case intDigits <= 0:
+		dst = append(dst, '0', '.')
+		for ; intDigits < 0; intDigits++ {
+			dst = append(dst, '0')
+		}
+		dst = append(dst, digits...)
+	case intDigits >= len(digits):
+		dst = append(dst, digits...)
+		for k := len(digits); k < intDigits; k++ {
+			dst = append(dst, '0')
+		}
+	default:
+		dst = append(dst, digits[:intDigits]...)
+		dst = append(dst, '.')
+		dst = append(dst, digits[intDigits:]...)
+	}
+
+	prefix := siPrefixes[group+maxGroup]
+	if prefix != "" || unit != "" {
+		dst = append(dst, ' ')
+		dst = append(dst, prefix...)
+		dst = append(dst, unit...)
+	}
+	return dst
+}
+
+// WriteMaskedPattern appends pattern to b's buffer with each '#' replaced by
+// the next decimal digit of digits, as in filling "(###) ###-####" to format
+// a phone number. Bytes of digits other than '0' to '9' are skipped, so
+// already formatted input can be reformatted. If digits runs out, the output
+// stops before the first unfilled '#'; digits left over after the pattern is
+// filled are appended, so no digit is lost.
+// It returns the length of written and a nil error.
+func (b *Builder) WriteMaskedPattern(digits, pattern string) (int, error) {
+	b.copyCheck()
+	n := len(b.buf)
+	i := 0
+	nextDigit := func() (byte, bool) {
+		for ; i < len(digits); i++ {
+			if c := digits[i]; '0' <= c && c <= '9' {
+				i++
+				return c, true
+			}
+		}
+		return 0, false
+	}
+
+	for j := 0; j < len(pattern); j++ {
+		c := pattern[j]
+		if c == '#' {
+			d, ok := nextDigit()
+			if !ok {
+				break
+			}
+			c = d
+		}
+		b.buf = append(b.buf, c)
+	}
+	for d, ok := nextDigit(); ok; d, ok = nextDigit() {
+		b.buf = append(b.buf, d)
+	}
+	return b.commit(n), nil
+}
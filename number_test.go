@@ -0,0 +1,248 @@
+// Copyright 2023 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package builder_test
+
+import (
+	"math"
+	"strconv"
+	"strings"
+	"testing"
+
+	. "github.com/weiwenchen2022/builder"
+)
+
+func TestBuilderWriteDecimal(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		unscaled int64
+		scale    int
+		want     string
+	}{
+		{12345, 2, "123.45"},
+		{-12345, 2, "-123.45"},
+		{5, 3, "0.005"},
+		{-5, 3, "-0.005"},
+		{100, 2, "1.00"},
+		{0, 2, "0.00"},
+		{0, 0, "0"},
+		{42, 0, "42"},
+		{12, -2, "1200"},
+		{0, -2, "0"},
+		{math.MaxInt64, 4, "922337203685477.5807"},
+		{math.MinInt64, 19, "-0.9223372036854775808"},
+		{math.MinInt64, 20, "-0.09223372036854775808"},
+	}
+
+	for _, tt := range tests {
+		var b Builder
+		n, err := b.WriteDecimal(tt.unscaled, tt.scale)
+		if err != nil || len(tt.want) != n {
+			t.Errorf("WriteDecimal(%d, %d): got %d,%v; want %d,nil", tt.unscaled, tt.scale, n, err, len(tt.want))
+		}
+		check(t, &b, tt.want)
+	}
+}
+
+func TestBuilderWriteIntPadded(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		i     int64
+		base  int
+		width int
+		want  string
+	}{
+		{42, 10, 5, "00042"},
+		{-42, 10, 5, "-0042"},
+		{12345, 10, 3, "12345"},
+		{0, 10, 0, "0"},
+		{0, 10, 4, "0000"},
+		{255, 16, 4, "00ff"},
+		{-255, 16, 4, "-0ff"},
+		{5, 2, 8, "00000101"},
+		{-1, 10, 0, "-1"},
+		{math.MinInt64, 10, 22, "-009223372036854775808"},
+		{math.MinInt64, 2, 0, "-1" + strings.Repeat("0", 63)},
+	}
+	for _, tt := range tests {
+		var b Builder
+		n, err := b.WriteIntPadded(tt.i, tt.base, tt.width)
+		if err != nil || len(tt.want) != n {
+			t.Errorf("WriteIntPadded(%d, %d, %d): got %d,%v; want %d,nil", tt.i, tt.base, tt.width, n, err, len(tt.want))
+		}
+		check(t, &b, tt.want)
+	}
+
+	var b Builder
+	b.WriteUintPadded(7, 10, 3)
+	b.WriteByte(' ')
+	b.WriteUintPadded(math.MaxUint64, 16, 20)
+	b.WriteByte(' ')
+	b.WriteUintPadded(math.MaxUint64, 2, 1)
+	check(t, &b, "007 0000ffffffffffffffff "+strings.Repeat("1", 64))
+}
+
+func TestBuilderWriteIntPaddedAllocs(t *testing.T) {
+	var b Builder
+	b.Grow(1024)
+	allocs := testing.AllocsPerRun(100, func() {
+		b.WriteIntPadded(-42, 10, 8)
+		b.WriteUintPadded(42, 2, 16)
+	})
+	if allocs != 0 {
+		t.Errorf("WriteIntPadded allocs = %v; want 0", allocs)
+	}
+}
+
+func TestBuilderWriteIntGrouped(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		i    int64
+		sep  rune
+		want string
+	}{
+		{0, ',', "0"},
+		{999, ',', "999"},
+		{1000, ',', "1,000"},
+		{-1000, ',', "-1,000"},
+		{1234567, ',', "1,234,567"},
+		{123456, ' ', "123 456"},
+		{-12345, '.', "-12.345"},
+		{1234567, '\u202f', "1\u202f234\u202f567"},
+		{math.MaxInt64, ',', "9,223,372,036,854,775,807"},
+		{math.MinInt64, '_', "-9_223_372_036_854_775_808"},
+	}
+	for _, tt := range tests {
+		var b Builder
+		n, err := b.WriteIntGrouped(tt.i, tt.sep)
+		if err != nil || len(tt.want) != n {
+			t.Errorf("WriteIntGrouped(%d, %q): got %d,%v; want %d,nil", tt.i, tt.sep, n, err, len(tt.want))
+		}
+		check(t, &b, tt.want)
+	}
+}
+
+func TestBuilderWriteHumanBytes(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		n     int64
+		units ByteUnits
+		want  string
+	}{
+		{0, BinaryUnits, "0 B"},
+		{512, BinaryUnits, "512 B"},
+		{1023, BinaryUnits, "1023 B"},
+		{1024, BinaryUnits, "1.0 KiB"},
+		{1536, BinaryUnits, "1.5 KiB"},
+		{1572864, BinaryUnits, "1.5 MiB"},
+		{1048575, BinaryUnits, "1.0 MiB"}, // rounds up into the next unit
+		{-1536, BinaryUnits, "-1.5 KiB"},
+		{math.MaxInt64, BinaryUnits, "8.0 EiB"},
+		{math.MinInt64, BinaryUnits, "-8.0 EiB"},
+		{999, DecimalUnits, "999 B"},
+		{1000, DecimalUnits, "1.0 kB"},
+		{3200000000, DecimalUnits, "3.2 GB"},
+		{999950, DecimalUnits, "1.0 MB"},
+		{999949, DecimalUnits, "999.9 kB"},
+		{math.MaxInt64, DecimalUnits, "9.2 EB"},
+	}
+	for _, tt := range tests {
+		var b Builder
+		n, err := b.WriteHumanBytes(tt.n, tt.units)
+		if err != nil || len(tt.want) != n {
+			t.Errorf("WriteHumanBytes(%d, %d): got %d,%v; want %d,nil", tt.n, tt.units, n, err, len(tt.want))
+		}
+		check(t, &b, tt.want)
+	}
+}
+
+func TestBuilderWriteOrdinal(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		n    int
+		want string
+	}{
+		{0, "0th"}, {1, "1st"}, {2, "2nd"}, {3, "3rd"}, {4, "4th"},
+		{10, "10th"}, {11, "11th"}, {12, "12th"}, {13, "13th"}, {14, "14th"},
+		{21, "21st"}, {22, "22nd"}, {23, "23rd"}, {101, "101st"}, {111, "111th"},
+		{112, "112th"}, {1002, "1002nd"}, {-1, "-1st"}, {-22, "-22nd"}, {-13, "-13th"},
+		{math.MinInt, strconv.Itoa(math.MinInt) + "th"},
+		{math.MaxInt, strconv.Itoa(math.MaxInt) + "th"},
+	}
+	for _, tt := range tests {
+		var b Builder
+		n, err := b.WriteOrdinal(tt.n)
+		if err != nil || len(tt.want) != n {
+			t.Errorf("WriteOrdinal(%d): got %d,%v; want %d,nil", tt.n, n, err, len(tt.want))
+		}
+		check(t, &b, tt.want)
+	}
+}
+
+func TestBuilderWriteSI(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		value float64
+		unit  string
+		prec  int
+		want  string
+	}{
+		{3300, "Ω", 2, "3.3 kΩ"},
+		{3300, "Ω", 3, "3.30 kΩ"},
+		{12e-6, "s", -1, "12 µs"},
+		{1.5e6, "Hz", -1, "1.5 MHz"},
+		{999.96, "Hz", 3, "1.00 kHz"},
+		{999.96, "Hz", -1, "999.96 Hz"},
+		{-2.5e-3, "A", -1, "-2.5 mA"},
+		{1e4, "m", -1, "10 km"},
+		{123456, "B", 2, "120 kB"},
+		{0.5, "", -1, "500 m"},
+		{42, "", -1, "42"},
+		{0, "V", 2, "0 V"},
+		{math.Inf(1), "W", 2, "+Inf W"},
+		{1e33, "g", -1, "1000 Qg"},
+		{1e-33, "g", -1, "0.001 qg"},
+	}
+
+	for _, tt := range tests {
+		var b Builder
+		n, err := b.WriteSI(tt.value, tt.unit, tt.prec)
+		if err != nil || len(tt.want) != n {
+			t.Errorf("WriteSI(%v, %q, %d): got %d,%v; want %d,nil", tt.value, tt.unit, tt.prec, n, err, len(tt.want))
+		}
+		check(t, &b, tt.want)
+	}
+}
+
+func TestBuilderWriteMaskedPattern(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		digits, pattern, want string
+	}{
+		{"5551234567", "(###) ###-####", "(555) 123-4567"},
+		{"555-123-4567", "(###) ###-####", "(555) 123-4567"},
+		{"55512", "(###) ###-####", "(555) 12"},
+		{"4111111111111111", "#### #### #### ####", "4111 1111 1111 1111"},
+		{"41111111111111110", "#### #### #### ####", "4111 1111 1111 11110"},
+		{"", "(###)", "("},
+		{"12", "no digits", "no digits12"},
+		{"123", "", "123"},
+	}
+
+	for _, tt := range tests {
+		var b Builder
+		n, err := b.WriteMaskedPattern(tt.digits, tt.pattern)
+		if err != nil || len(tt.want) != n {
+			t.Errorf("WriteMaskedPattern(%q, %q): got %d,%v; want %d,nil", tt.digits, tt.pattern, n, err, len(tt.want))
+		}
+		check(t, &b, tt.want)
+	}
+}
@@ -0,0 +1,35 @@
+// Copyright 2023 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package builder
+
+// WriteOnce appends s to b's buffer unless a string has already been written
+// under key, so that code generators can emit imports, forward declarations
+// and includes wherever they are needed without keeping track of them.
+// A skipped call writes nothing and does not count as a write for SetSep.
+// Reset forgets the keys.
+// It returns the length of written, which is zero if s was skipped, and a
+// nil error.
+func (b *Builder) WriteOnce(key, s string) (int, error) {
+	if b.mode == nil {
+		b.mode = new(mode)
+	}
+	if _, ok := b.mode.once[key]; ok {
+		return 0, nil
+	}
+	if b.mode.once == nil {
+		b.mode.once = make(map[string]struct{})
+	}
+	b.mode.once[key] = struct{}{}
+	return b.WriteString(s)
+}
+
+// WrittenOnce reports whether WriteOnce has written a string under key.
+func (b *Builder) WrittenOnce(key string) bool {
+	if b.mode == nil {
+		return false
+	}
+	_, ok := b.mode.once[key]
+	return ok
+}
@@ -0,0 +1,45 @@
+// Copyright 2023 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package builder_test
+
+import (
+	"testing"
+
+	. "github.com/weiwenchen2022/builder"
+)
+
+func TestBuilderWriteOnce(t *testing.T) {
+	t.Parallel()
+
+	var b Builder
+	if b.WrittenOnce("import:fmt") {
+		t.Error("WrittenOnce before any write: got true")
+	}
+	for _, pkg := range []string{"fmt", "os", "fmt", "strings", "os"} {
+		b.WriteOnce("import:"+pkg, "\t\""+pkg+"\"\n")
+	}
+	check(t, &b, "\t\"fmt\"\n\t\"os\"\n\t\"strings\"\n")
+	if !b.WrittenOnce("import:fmt") {
+		t.Error("WrittenOnce after write: got false")
+	}
+
+	if n, err := b.WriteOnce("import:os", "x"); n != 0 || err != nil {
+		t.Errorf("WriteOnce(duplicate): got %d,%v; want 0,nil", n, err)
+	}
+
+	// A skipped write is not separated.
+	b.Reset()
+	b.SetSep(", ")
+	b.WriteOnce("a", "a")
+	b.WriteOnce("a", "a")
+	b.WriteOnce("b", "b")
+	check(t, &b, "a, b")
+
+	b.Reset()
+	if n, err := b.WriteOnce("a", "again"); n != 5 || err != nil {
+		t.Errorf("WriteOnce after Reset: got %d,%v; want 5,nil", n, err)
+	}
+	check(t, &b, "again")
+}
@@ -0,0 +1,140 @@
+// Copyright 2023 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package builder
+
+import (
+	"strconv"
+	"unicode/utf8"
+)
+
+// WriteOps is a prepared sequence of typed writes. The size of its output is
+// computed while the sequence is prepared, so applying it to a Builder with
+// WriteOps grows the Builder at most once and dispatches each write with a
+// plain switch rather than through an interface.
+//
+// The zero value is an empty sequence ready to use. Once prepared, a WriteOps
+// may be applied by multiple goroutines simultaneously.
+type WriteOps struct {
+	ops  []writeOp
+	size int
+}
+
+type opKind uint8
+
+const (
+	opString opKind = iota
+	opByte
+	opRune
+	opBool
+	opInt
+	opUint
+	opFloat
+)
+
+type writeOp struct {
+	kind opKind
+	fmt  byte
+	base int // base for integers, precision for floats
+	bits int
+	s    string
+	u    uint64 // payload for bytes, runes, bools and integers
+	f    float64
+}
+
+// Len returns the number of writes in ops.
+func (ops *WriteOps) Len() int { return len(ops.ops) }
+
+// Size returns the number of bytes applying ops appends to a Builder.
+func (ops *WriteOps) Size() int { return ops.size }
+
+// Reset removes all writes from ops, retaining its storage.
+func (ops *WriteOps) Reset() {
+	ops.ops = ops.ops[:0]
+	ops.size = 0
+}
+
+// AddString adds a write of s.
+func (ops *WriteOps) AddString(s string) {
+	ops.ops = append(ops.ops, writeOp{kind: opString, s: s})
+	ops.size += len(s)
+}
+
+// AddByte adds a write of the byte c.
+func (ops *WriteOps) AddByte(c byte) {
+	ops.ops = append(ops.ops, writeOp{kind: opByte, u: uint64(c)})
+	ops.size++
+}
+
+// AddRune adds a write of the UTF-8 encoding of r.
+func (ops *WriteOps) AddRune(r rune) {
+	ops.ops = append(ops.ops, writeOp{kind: opRune, u: uint64(r)})
+	if n := utf8.RuneLen(r); n > 0 {
+		ops.size += n
+	} else {
+		ops.size += len(string(utf8.RuneError))
+	}
+}
+
+// AddBool adds a write of "true" or "false", according to the value of v.
+func (ops *WriteOps) AddBool(v bool) {
+	op := writeOp{kind: opBool}
+	if v {
+		op.u = 1
+		ops.size += len("true")
+	} else {
+		ops.size += len("false")
+	}
+	ops.ops = append(ops.ops, op)
+}
+
+// AddInt adds a write of the integer i as formatted by WriteInt.
+func (ops *WriteOps) AddInt(i int64, base int) {
+	var scratch [65]byte
+	ops.ops = append(ops.ops, writeOp{kind: opInt, u: uint64(i), base: base})
+	ops.size += len(strconv.AppendInt(scratch[:0], i, base))
+}
+
+// AddUint adds a write of the unsigned integer i as formatted by WriteUint.
+func (ops *WriteOps) AddUint(i uint64, base int) {
+	var scratch [64]byte
+	ops.ops = append(ops.ops, writeOp{kind: opUint, u: i, base: base})
+	ops.size += len(strconv.AppendUint(scratch[:0], i, base))
+}
+
+// AddFloat adds a write of the floating-point number f as formatted by WriteFloat.
+func (ops *WriteOps) AddFloat(f float64, fmt byte, prec, bitSize int) {
+	var scratch [64]byte
+	ops.ops = append(ops.ops, writeOp{kind: opFloat, f: f, fmt: fmt, base: prec, bits: bitSize})
+	ops.size += len(strconv.AppendFloat(scratch[:0], f, fmt, prec, bitSize))
+}
+
+// WriteOps applies the writes in ops, in order, to b's buffer.
+// It returns the length of written and a nil error.
+func (b *Builder) WriteOps(ops *WriteOps) (int, error) {
+	if !b.reserve(ops.size) {
+		return 0, ErrFull
+	}
+	n := len(b.buf)
+	for i := range ops.ops {
+		op := &ops.ops[i]
+		switch op.kind {
+		case opString:
+			b.buf = append(b.buf, op.s...)
+		case opByte:
+			b.buf = append(b.buf, byte(op.u))
+		case opRune:
+			b.buf = utf8.AppendRune(b.buf, rune(op.u))
+		case opBool:
+			b.buf = strconv.AppendBool(b.buf, op.u != 0)
+		case opInt:
+			b.buf = strconv.AppendInt(b.buf, int64(op.u), op.base)
+		case opUint:
+			b.buf = strconv.AppendUint(b.buf, op.u, op.base)
+		case opFloat:
+			b.buf = b.appendFloat(b.buf, op.f, op.fmt, op.base, op.bits)
+		}
+	}
+	return b.commit(n), nil
+}
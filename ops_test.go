@@ -0,0 +1,66 @@
+// Copyright 2023 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package builder_test
+
+import (
+	"testing"
+
+	. "github.com/weiwenchen2022/builder"
+)
+
+func TestBuilderWriteOps(t *testing.T) {
+	t.Parallel()
+
+	var ops WriteOps
+	ops.AddString("id=")
+	ops.AddInt(-42, 10)
+	ops.AddByte(' ')
+	ops.AddUint(255, 16)
+	ops.AddRune('世')
+	ops.AddRune(-1)
+	ops.AddBool(true)
+	ops.AddBool(false)
+	ops.AddFloat(1.5, 'f', 2, 64)
+
+	const want = "id=-42 ff世\uFFFDtruefalse1.50"
+	if n := ops.Len(); n != 9 {
+		t.Errorf("Len: got %d; want 9", n)
+	}
+	if n := ops.Size(); len(want) != n {
+		t.Errorf("Size: got %d; want %d", n, len(want))
+	}
+
+	var b Builder
+	for i := 1; i <= 2; i++ {
+		n, err := b.WriteOps(&ops)
+		if err != nil || len(want) != n {
+			t.Errorf("WriteOps: got %d,%v; want %d,nil", n, err, len(want))
+		}
+	}
+	check(t, &b, want+want)
+
+	ops.Reset()
+	if ops.Len() != 0 || ops.Size() != 0 {
+		t.Errorf("after Reset: got Len %d, Size %d; want 0, 0", ops.Len(), ops.Size())
+	}
+}
+
+func TestBuilderWriteOpsAllocs(t *testing.T) {
+	var ops WriteOps
+	ops.AddString("user=")
+	ops.AddString("gopher")
+	ops.AddString(" id=")
+	ops.AddInt(1234567, 10)
+	ops.AddFloat(3.25, 'g', -1, 64)
+
+	allocs := testing.AllocsPerRun(100, func() {
+		var b Builder
+		b.WriteOps(&ops)
+		_ = b.String()
+	})
+	if allocs != 1 {
+		t.Errorf("WriteOps allocs = %v; want 1", allocs)
+	}
+}
@@ -0,0 +1,58 @@
+// Copyright 2023 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package builder
+
+import (
+	"runtime"
+	"sync"
+	"sync/atomic"
+)
+
+// Parallel calls gen(i, b) for each i in [0, n), concurrently on up to
+// GOMAXPROCS goroutines, giving each call its own empty Builder. It returns
+// the contents of the n Builders concatenated in index order, assembled with a
+// single allocation and copy.
+//
+// Calls of gen may use b.Grow to size their Builder by an estimate of the
+// section they produce. gen must not retain b after it returns.
+func Parallel(n int, gen func(i int, b *Builder)) string {
+	if n <= 0 {
+		return ""
+	}
+
+	sections := make([]Builder, n)
+	workers := runtime.GOMAXPROCS(0)
+	if workers > n {
+		workers = n
+	}
+
+	var next atomic.Int64
+	var wg sync.WaitGroup
+	wg.Add(workers)
+	for w := 0; w < workers; w++ {
+		go func() {
+			defer wg.Done()
+			for {
+				i := int(next.Add(1) - 1)
+				if i >= n {
+					return
+				}
+				gen(i, &sections[i])
+			}
+		}()
+	}
+	wg.Wait()
+
+	size := 0
+	for i := range sections {
+		size += sections[i].Len()
+	}
+	var b Builder
+	b.Grow(size)
+	for i := range sections {
+		b.buf = append(b.buf, sections[i].buf...)
+	}
+	return b.String()
+}
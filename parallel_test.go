@@ -0,0 +1,34 @@
+// Copyright 2023 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package builder_test
+
+import (
+	"strconv"
+	"strings"
+	"testing"
+
+	. "github.com/weiwenchen2022/builder"
+)
+
+func TestParallel(t *testing.T) {
+	t.Parallel()
+
+	for _, n := range []int{0, 1, 3, 100} {
+		got := Parallel(n, func(i int, b *Builder) {
+			b.Grow(16)
+			b.WriteString("<section ")
+			b.WriteInt(int64(i), 10)
+			b.WriteString(">\n")
+		})
+
+		var want strings.Builder
+		for i := 0; i < n; i++ {
+			want.WriteString("<section " + strconv.Itoa(i) + ">\n")
+		}
+		if want.String() != got {
+			t.Errorf("Parallel(%d): got %q; want %q", n, got, want.String())
+		}
+	}
+}
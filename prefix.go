@@ -0,0 +1,77 @@
+// Copyright 2023 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package builder
+
+import (
+	"bytes"
+	"strings"
+)
+
+// PushLinePrefix adds prefix to the line prefix stack. Every line begun after
+// the call is prefixed with the concatenation of the stacked prefixes, outer
+// ones first, which suits quoting replies ("> "), commenting out generated
+// code ("// ") or indenting nested blocks. On empty lines, trailing spaces
+// and tabs of the prefix are dropped. The counts returned by write methods do
+// not include prefixes.
+func (b *Builder) PushLinePrefix(prefix string) {
+	if b.mode == nil {
+		b.mode = new(mode)
+	}
+	m := b.mode
+	if len(m.prefixes) == 0 {
+		m.midLine = len(b.buf) > 0 && b.buf[len(b.buf)-1] != '\n'
+	}
+	m.prefixes = append(m.prefixes, prefix)
+	m.prefix = strings.Join(m.prefixes, "")
+}
+
+// PopLinePrefix removes the prefix most recently added by PushLinePrefix.
+// It panics if the stack is empty.
+func (b *Builder) PopLinePrefix() {
+	if b.mode == nil || len(b.mode.prefixes) == 0 {
+		panic("builder.Builder.PopLinePrefix: empty prefix stack")
+	}
+	m := b.mode
+	m.prefixes = m.prefixes[:len(m.prefixes)-1]
+	m.prefix = strings.Join(m.prefixes, "")
+}
+
+// SetLinePrefix replaces the line prefix stack with prefix alone.
+// An empty prefix clears the stack.
+func (b *Builder) SetLinePrefix(prefix string) {
+	if b.mode != nil {
+		b.mode.prefixes = b.mode.prefixes[:0]
+		b.mode.prefix = ""
+	}
+	if prefix != "" {
+		b.PushLinePrefix(prefix)
+	}
+}
+
+// prefixLines inserts the line prefix at the start of every line begun in
+// b.buf[n:].
+func (m *mode) prefixLines(b *Builder, n int) {
+	p := b.buf[n:]
+	if len(p) == 0 {
+		return
+	}
+	if !m.midLine || bytes.IndexByte(p[:len(p)-1], '\n') >= 0 {
+		m.scratch = append(m.scratch[:0], p...)
+		b.buf = b.buf[:n]
+		for _, c := range m.scratch {
+			if !m.midLine {
+				if c == '\n' {
+					b.buf = append(b.buf, strings.TrimRight(m.prefix, " \t")...)
+				} else {
+					b.buf = append(b.buf, m.prefix...)
+				}
+			}
+			b.buf = append(b.buf, c)
+			m.midLine = c != '\n'
+		}
+		return
+	}
+	m.midLine = p[len(p)-1] != '\n'
+}
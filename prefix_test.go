@@ -0,0 +1,61 @@
+// Copyright 2023 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package builder_test
+
+import (
+	"testing"
+
+	. "github.com/weiwenchen2022/builder"
+)
+
+func TestBuilderLinePrefix(t *testing.T) {
+	t.Parallel()
+
+	var b Builder
+	b.WriteString("On Monday, gopher wrote:\n")
+	b.PushLinePrefix("> ")
+	n, _ := b.WriteString("Hello,\n\nthis is")
+	if n != len("Hello,\n\nthis is") {
+		t.Errorf("WriteString: got n=%d; want %d", n, len("Hello,\n\nthis is"))
+	}
+	b.WriteString(" a reply.\n")
+	b.PushLinePrefix("> ")
+	b.WriteLine("nested")
+	b.PopLinePrefix()
+	b.WriteByte('x')
+	b.WriteByte('\n')
+	b.PopLinePrefix()
+	b.WriteString("Thanks\n")
+
+	const want = "On Monday, gopher wrote:\n" +
+		"> Hello,\n" +
+		">\n" +
+		"> this is a reply.\n" +
+		"> > nested\n" +
+		"> x\n" +
+		"Thanks\n"
+	check(t, &b, want)
+}
+
+func TestBuilderSetLinePrefix(t *testing.T) {
+	t.Parallel()
+
+	var b Builder
+	b.WriteString("x := 1")
+	b.SetLinePrefix("// ")
+	b.WriteString(" // not prefixed\nfmt.Println(x)\n")
+	b.SetLinePrefix("\t")
+	b.WriteString("a\nb")
+	b.SetLinePrefix("")
+	b.WriteString("\nc")
+	check(t, &b, "x := 1 // not prefixed\n// fmt.Println(x)\n\ta\n\tb\nc")
+
+	defer func() {
+		if recover() == nil {
+			t.Error("PopLinePrefix on an empty stack should panic")
+		}
+	}()
+	b.PopLinePrefix()
+}
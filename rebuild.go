@@ -0,0 +1,123 @@
+// Copyright 2023 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package builder
+
+import "unsafe"
+
+// A Snapshot is an immutable view of the content of a Builder at some point,
+// for use with RebuildFrom. Taking one does not copy the content, so it
+// shares memory with the Builder, like the result of String, until it is
+// passed to Release.
+type Snapshot struct {
+	s string
+}
+
+// Snapshot returns a Snapshot of the accumulated content.
+func (b *Builder) Snapshot() Snapshot {
+	return Snapshot{unsafe.String(unsafe.SliceData(b.buf), len(b.buf))}
+}
+
+// String returns the content captured by s.
+func (s Snapshot) String() string { return s.s }
+
+// Len returns the number of bytes captured by s.
+func (s Snapshot) Len() int { return len(s.s) }
+
+// RebuildFrom empties b, leaving its write modes in place, to build a new
+// version of the content captured by prev, as rendering loops do every frame.
+// As content is written, b tracks how much of it matches prev, so that after
+// the rebuild Unchanged and ChangedTail tell which part of the output must be
+// emitted again.
+//
+// RebuildFrom builds into a new buffer, so strings returned by String are
+// never affected, unless the buffer b retired at the previous call has since
+// been passed to Release: then that buffer is reused, and a rendering loop
+// that releases each snapshot once it has the next one does not allocate.
+// A Builder created by NewFixed rebuilds in place, comparing against a copy of
+// prev kept in a reused scratch buffer.
+func (b *Builder) RebuildFrom(prev Snapshot) {
+	b.copyCheck()
+	if b.mode == nil {
+		b.mode = new(mode)
+	}
+	m := b.mode
+
+	if m.fixed != nil {
+		if overlaps(m.fixed[:cap(m.fixed)], prev.s) {
+			m.spare = append(m.spare[:0], prev.s...)
+			prev.s = unsafe.String(unsafe.SliceData(m.spare), len(m.spare))
+		}
+		b.buf = m.fixed[:0]
+	} else {
+		buf := m.spare
+		if m.released != nil && unsafe.SliceData(buf) == m.released &&
+			cap(buf) >= len(prev.s) && !overlaps(buf[:cap(buf)], prev.s) {
+			m.released = nil
+		} else {
+			buf = make([]byte, 0, len(prev.s))
+		}
+		m.spare = b.buf
+		b.buf = buf[:0]
+	}
+	m.cutContent(b, 0)
+	m.rebuilding = true
+	m.prev = prev.s
+	m.same = 0
+}
+
+// Release tells b that the memory of s is no longer used: neither s nor any
+// string obtained from b while it held the same content buffer, by String,
+// Snapshot, Lines or ChangedTail, will be used again. RebuildFrom may then
+// overwrite that memory.
+func (b *Builder) Release(s Snapshot) {
+	if len(s.s) == 0 {
+		return
+	}
+	if b.mode == nil {
+		b.mode = new(mode)
+	}
+	b.mode.released = unsafe.StringData(s.s)
+}
+
+// Unchanged returns the length of the longest common prefix of the content
+// written since the last call to RebuildFrom and the content it was given.
+// Without a call to RebuildFrom, it returns 0.
+func (b *Builder) Unchanged() int {
+	if b.mode == nil || !b.mode.rebuilding {
+		return 0
+	}
+	return b.mode.same
+}
+
+// ChangedTail returns the content following the unchanged prefix reported by
+// Unchanged. Like a Snapshot, it shares memory with b.
+func (b *Builder) ChangedTail() string {
+	tail := b.buf[b.Unchanged():]
+	return unsafe.String(unsafe.SliceData(tail), len(tail))
+}
+
+// overlaps reports whether s shares memory with p.
+func overlaps(p []byte, s string) bool {
+	if len(p) == 0 || len(s) == 0 {
+		return false
+	}
+	ps := uintptr(unsafe.Pointer(unsafe.SliceData(p)))
+	ss := uintptr(unsafe.Pointer(unsafe.StringData(s)))
+	return ss < ps+uintptr(len(p)) && ps < ss+uintptr(len(s))
+}
+
+// commonPrefix returns the length of the longest common prefix of p and s.
+func commonPrefix(p []byte, s string) int {
+	n := len(p)
+	if len(s) < n {
+		n = len(s)
+	}
+	for i := 0; i < n; i++ {
+		if p[i] != s[i] {
+			return i
+		}
+	}
+	return n
+}
@@ -0,0 +1,156 @@
+// Copyright 2023 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package builder_test
+
+import (
+	"strconv"
+	"testing"
+	"unsafe"
+
+	. "github.com/weiwenchen2022/builder"
+)
+
+func renderFrame(b *Builder, cpu int, status string) {
+	b.WriteString("host: gopher\n")
+	b.WriteString("cpu: ")
+	b.WriteInt(int64(cpu), 10)
+	b.WriteString("%\nstatus: ")
+	b.WriteString(status)
+	b.WriteByte('\n')
+}
+
+func TestBuilderRebuildFrom(t *testing.T) {
+	t.Parallel()
+
+	var b Builder
+	if n := b.Unchanged(); n != 0 {
+		t.Errorf("Unchanged without RebuildFrom: got %d; want 0", n)
+	}
+
+	renderFrame(&b, 42, "ok")
+	prev := b.Snapshot()
+	first := b.String()
+	if prev.String() != first || prev.Len() != len(first) {
+		t.Fatalf("Snapshot: got %q; want %q", prev.String(), first)
+	}
+
+	tests := []struct {
+		cpu    int
+		status string
+		same   int
+		tail   string
+	}{
+		{42, "ok", len(first), ""},
+		{47, "ok", len("host: gopher\ncpu: 4"), "7%\nstatus: ok\n"},
+		{42, "ok, and then some", len("host: gopher\ncpu: 42%\nstatus: ok"), ", and then some\n"},
+		{42, "o", len("host: gopher\ncpu: 42%\nstatus: o"), "\n"},
+	}
+	for _, tt := range tests {
+		b.RebuildFrom(prev)
+		if b.Len() != 0 || b.Cap() < prev.Len() {
+			t.Errorf("RebuildFrom: got Len %d, Cap %d; want 0, >= %d", b.Len(), b.Cap(), prev.Len())
+		}
+		renderFrame(&b, tt.cpu, tt.status)
+		if got := b.Unchanged(); tt.same != got {
+			t.Errorf("%d %q: Unchanged: got %d; want %d", tt.cpu, tt.status, got, tt.same)
+		}
+		if got := b.ChangedTail(); tt.tail != got {
+			t.Errorf("%d %q: ChangedTail: got %q; want %q", tt.cpu, tt.status, got, tt.tail)
+		}
+	}
+
+	if prev.String() != first {
+		t.Errorf("snapshot changed after rebuilds: got %q; want %q", prev.String(), first)
+	}
+}
+
+func TestBuilderRebuildFromString(t *testing.T) {
+	t.Parallel()
+
+	// Strings returned by String must survive any number of rebuilds.
+	var b Builder
+	renderFrame(&b, 0, "ok")
+	prev := b.Snapshot()
+	var frames []string
+	for i := 1; i <= 5; i++ {
+		b.RebuildFrom(prev)
+		renderFrame(&b, i, "ok")
+		frames = append(frames, b.String())
+		prev = b.Snapshot()
+	}
+	for i, s := range frames {
+		if want := "host: gopher\ncpu: " + strconv.Itoa(i+1) + "%\nstatus: ok\n"; s != want {
+			t.Errorf("frame %d changed after rebuilds: got %q; want %q", i+1, s, want)
+		}
+	}
+}
+
+func TestBuilderRebuildFromStale(t *testing.T) {
+	t.Parallel()
+
+	// A snapshot that was not released views the spare buffer, which must
+	// then not be rebuilt into.
+	var b Builder
+	renderFrame(&b, 10, "ok")
+	old := b.Snapshot()
+	b.RebuildFrom(old)
+	renderFrame(&b, 11, "ok")
+	b.RebuildFrom(old)
+	renderFrame(&b, 12, "ok")
+	if want := "host: gopher\ncpu: 10%\nstatus: ok\n"; old.String() != want {
+		t.Errorf("stale snapshot changed: got %q; want %q", old.String(), want)
+	}
+	if got, want := b.ChangedTail(), "2%\nstatus: ok\n"; got != want {
+		t.Errorf("ChangedTail: got %q; want %q", got, want)
+	}
+}
+
+func TestBuilderRebuildFromAllocs(t *testing.T) {
+	var b Builder
+	renderFrame(&b, 1, "ok")
+	prev := b.Snapshot()
+	cpu := 0
+	allocs := testing.AllocsPerRun(100, func() {
+		b.RebuildFrom(prev)
+		renderFrame(&b, 10+cpu%10, "ok")
+		_ = b.ChangedTail()
+		old := prev
+		prev = b.Snapshot()
+		b.Release(old)
+		cpu++
+	})
+	if allocs != 0 {
+		t.Errorf("RebuildFrom allocs = %v; want 0", allocs)
+	}
+}
+
+func TestBuilderRebuildFromRelease(t *testing.T) {
+	t.Parallel()
+
+	// Without Release, a rebuild must not overwrite the previous frame even
+	// if the frame before it is still in use; with it, the memory is reused.
+	var b Builder
+	renderFrame(&b, 1, "ok")
+	first := b.Snapshot()
+	b.RebuildFrom(first)
+	renderFrame(&b, 2, "ok")
+	second := b.Snapshot()
+	b.RebuildFrom(second)
+	renderFrame(&b, 3, "ok")
+	if want := "host: gopher\ncpu: 1%\nstatus: ok\n"; first.String() != want {
+		t.Errorf("unreleased snapshot changed: got %q; want %q", first.String(), want)
+	}
+
+	third := b.Snapshot()
+	b.Release(second)
+	b.RebuildFrom(third)
+	renderFrame(&b, 4, "ok")
+	if got, want := b.String(), "host: gopher\ncpu: 4%\nstatus: ok\n"; got != want {
+		t.Errorf("after Release: got %q; want %q", got, want)
+	}
+	if unsafe.StringData(b.String()) != unsafe.StringData(second.String()) {
+		t.Errorf("RebuildFrom did not reuse the released buffer")
+	}
+}
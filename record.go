@@ -0,0 +1,118 @@
+// Copyright 2023 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package builder
+
+import (
+	"runtime"
+	"strconv"
+	"strings"
+)
+
+// A WriteRecord describes one write made to a Builder while recording.
+type WriteRecord struct {
+	Method string // write method, such as "WriteString" or "Table.Row"
+	Offset int    // length of the content before the write
+	Len    int    // number of bytes the write appended, excluding write modes
+	Data   string // the appended bytes, quoted and cut after 40 bytes
+}
+
+// String returns r in the format used by Recording.
+func (r WriteRecord) String() string {
+	return strconv.Itoa(r.Offset) + " +" + strconv.Itoa(r.Len) + " " + r.Method + " " + r.Data
+}
+
+// maxRecordData is the number of written bytes quoted in WriteRecord.Data.
+const maxRecordData = 40
+
+// StartRecording makes b record a WriteRecord for every following write,
+// to help track down where the output of nondeterministic generators
+// diverges. Recording is slow: each write looks up its caller.
+func (b *Builder) StartRecording() {
+	if b.mode == nil {
+		b.mode = new(mode)
+	}
+	b.mode.recording = true
+}
+
+// StopRecording stops recording writes and returns the records collected
+// since StartRecording.
+func (b *Builder) StopRecording() []WriteRecord {
+	if b.mode == nil {
+		return nil
+	}
+	records := b.mode.records
+	b.mode.recording = false
+	b.mode.records = nil
+	return records
+}
+
+// Records returns the records collected so far. The caller must not modify
+// the returned slice.
+func (b *Builder) Records() []WriteRecord {
+	if b.mode == nil {
+		return nil
+	}
+	return b.mode.records
+}
+
+// Recording returns the records collected so far as text, one line per
+// write giving the offset, the length, the method and the data, like
+//
+//	12 +5 WriteString "hello"
+//
+// so that the recordings of two runs can be compared with diff.
+func (b *Builder) Recording() string {
+	var sb strings.Builder
+	for _, r := range b.Records() {
+		sb.WriteString(r.String())
+		sb.WriteByte('\n')
+	}
+	return sb.String()
+}
+
+// record records a write that appended b.buf[n:].
+func (m *mode) record(b *Builder, n int) {
+	data := b.buf[n:]
+	var quoted string
+	if len(data) > maxRecordData {
+		quoted = strconv.Quote(string(data[:maxRecordData])) + "..."
+	} else {
+		quoted = strconv.Quote(string(data))
+	}
+	m.records = append(m.records, WriteRecord{
+		Method: writeMethod(),
+		Offset: n,
+		Len:    len(data),
+		Data:   quoted,
+	})
+}
+
+// writeMethod returns the name of the exported method of this package that
+// made the write being recorded, without the package path and the receiver
+// type Builder, such as "WriteString" or "HCLWriter.Attribute". A write made
+// by one method through another, such as WriteRune through WriteByte, is
+// attributed to the outermost one, which the caller used.
+func writeMethod() string {
+	var pcs [16]uintptr
+	frames := runtime.CallersFrames(pcs[:runtime.Callers(3, pcs[:])])
+	name := ""
+	for {
+		frame, more := frames.Next()
+		fn := frame.Function[strings.LastIndexByte(frame.Function, '/')+1:]
+		if !strings.HasPrefix(fn, "builder.") {
+			break
+		}
+		fn = receiverReplacer.Replace(strings.TrimPrefix(fn, "builder."))
+		if last := fn[strings.LastIndexByte(fn, '.')+1:]; last != "" && 'A' <= last[0] && last[0] <= 'Z' {
+			name = fn
+		}
+		if !more {
+			break
+		}
+	}
+	return name
+}
+
+var receiverReplacer = strings.NewReplacer("(*Builder).", "", "(*", "", ")", "")
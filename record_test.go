@@ -0,0 +1,44 @@
+// Copyright 2023 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package builder_test
+
+import (
+	"strings"
+	"testing"
+
+	. "github.com/weiwenchen2022/builder"
+)
+
+func TestBuilderRecording(t *testing.T) {
+	t.Parallel()
+
+	var b Builder
+	b.WriteString("not recorded;")
+	b.StartRecording()
+	b.WriteString("hello")
+	b.WriteRune('!')
+	b.WriteInt(42, 10)
+	b.HCL().Attribute("a", "1")
+	b.WriteString(strings.Repeat("x", 50))
+
+	want := `13 +5 WriteString "hello"
+18 +1 WriteRune "!"
+19 +2 WriteInt "42"
+21 +6 HCLWriter.Attribute "a = 1\n"
+27 +50 WriteString "xxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxx"...
+`
+	if got := b.Recording(); want != got {
+		t.Errorf("Recording:\ngot  %s\nwant %s", got, want)
+	}
+
+	records := b.StopRecording()
+	if len(records) != 5 || records[0] != (WriteRecord{"WriteString", 13, 5, `"hello"`}) {
+		t.Errorf("StopRecording: got %v", records)
+	}
+	b.WriteString("after")
+	if got := b.Records(); len(got) != 0 {
+		t.Errorf("Records after StopRecording: got %v", got)
+	}
+}
@@ -0,0 +1,32 @@
+// Copyright 2023 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package builder
+
+// WriteRegexpQuoteMeta appends s to b's buffer with all regular expression
+// metacharacters escaped, producing the same output as regexp.QuoteMeta, so
+// the result matches s literally when used within a pattern.
+// It returns the length of written and a nil error.
+func (b *Builder) WriteRegexpQuoteMeta(s string) (int, error) {
+	b.copyCheck()
+	n := len(b.buf)
+	last := 0
+	for i := 0; i < len(s); i++ {
+		if isRegexpSpecial(s[i]) {
+			b.buf = append(b.buf, s[last:i]...)
+			b.buf = append(b.buf, '\\', s[i])
+			last = i + 1
+		}
+	}
+	b.buf = append(b.buf, s[last:]...)
+	return b.commit(n), nil
+}
+
+func isRegexpSpecial(c byte) bool {
+	switch c {
+	case '\\', '.', '+', '*', '?', '(', ')', '|', '[', ']', '{', '}', '^', '$':
+		return true
+	}
+	return false
+}
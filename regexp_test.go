@@ -0,0 +1,46 @@
+// Copyright 2023 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package builder_test
+
+import (
+	"regexp"
+	"testing"
+
+	. "github.com/weiwenchen2022/builder"
+	"github.com/weiwenchen2022/builder/buildertest"
+)
+
+func TestBuilderWriteRegexpQuoteMeta(t *testing.T) {
+	t.Parallel()
+
+	tests := append([]string{
+		"",
+		"plain",
+		`\.+*?()|[]{}^$`,
+		"1.5-2.0?",
+		"héllo (世界)",
+	}, buildertest.Adversarial...)
+
+	for _, s := range tests {
+		var b Builder
+		want := regexp.QuoteMeta(s)
+		n, err := b.WriteRegexpQuoteMeta(s)
+		if err != nil || len(want) != n {
+			t.Errorf("WriteRegexpQuoteMeta(%q): got %d,%v; want %d,nil", s, n, err, len(want))
+		}
+		check(t, &b, want)
+	}
+
+	var b Builder
+	b.WriteString("^(")
+	b.WriteRegexpQuoteMeta("a.b")
+	b.WriteString("|")
+	b.WriteRegexpQuoteMeta("c+d")
+	b.WriteString(")$")
+	re := regexp.MustCompile(b.String())
+	if !re.MatchString("c+d") || re.MatchString("axb") {
+		t.Errorf("pattern %q does not match its fragments literally", b.String())
+	}
+}
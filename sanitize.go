@@ -0,0 +1,80 @@
+// Copyright 2023 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package builder
+
+import (
+	"unicode/utf8"
+	"unsafe"
+)
+
+// WriteStringValid appends s to b's buffer with each byte that is not part of
+// a valid UTF-8 encoding replaced by the replacement character U+FFFD, as
+// ranging over s does, so the result is valid UTF-8 even if s comes from an
+// untrusted source. To sanitize every write, see SanitizeUTF8.
+// It returns the length of written and a nil error.
+func (b *Builder) WriteStringValid(s string) (int, error) {
+	b.copyCheck()
+	n := len(b.buf)
+	b.buf, _ = appendValidUTF8(b.buf, s, true)
+	return b.commit(n), nil
+}
+
+// SanitizeUTF8 returns a Transformer that replaces invalid UTF-8 as
+// WriteStringValid does. Pushed with PushTransformer, it makes every following
+// write, including ones of single bytes, land in the buffer as valid UTF-8.
+// A multi-byte encoding may be split across writes: its start is held back
+// until the rest arrives. PopTransformer writes an incomplete encoding held
+// back at that point as replacement characters.
+func SanitizeUTF8() Transformer {
+	return new(utf8Sanitizer)
+}
+
+type utf8Sanitizer struct {
+	pending []byte // incomplete encoding at the end of the previous write
+}
+
+func (t *utf8Sanitizer) Transform(dst, p []byte) []byte {
+	if len(t.pending) > 0 {
+		t.pending = append(t.pending, p...)
+		p = t.pending
+	}
+	dst, k := appendValidUTF8(dst, unsafe.String(unsafe.SliceData(p), len(p)), false)
+	t.pending = append(t.pending[:0], p[len(p)-k:]...)
+	return dst
+}
+
+func (t *utf8Sanitizer) Flush(dst []byte) []byte {
+	dst, _ = appendValidUTF8(dst, string(t.pending), true)
+	t.pending = t.pending[:0]
+	return dst
+}
+
+// appendValidUTF8 appends s to dst with invalid UTF-8 replaced by U+FFFD.
+// Unless final is set, an incomplete encoding at the end of s is not appended;
+// appendValidUTF8 returns the extended dst and the length of the encoding
+// left out.
+func appendValidUTF8(dst []byte, s string, final bool) ([]byte, int) {
+	start := 0
+	for i := 0; i < len(s); {
+		if i += asciiPrefix(s[i:]); i == len(s) {
+			break
+		}
+		if s[i] < utf8.RuneSelf {
+			i++
+			continue
+		}
+		if !final && !utf8.FullRuneInString(s[i:]) {
+			return append(dst, s[start:i]...), len(s) - i
+		}
+		r, size := utf8.DecodeRuneInString(s[i:])
+		if r == utf8.RuneError && size == 1 {
+			dst = append(dst, s[start:i]...)
+			dst = append(dst, "\uFFFD"...)
+			start = i + 1
+		}
+		i += size
+	}
+	return append(dst, s[start:]...), 0
+}
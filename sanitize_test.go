@@ -0,0 +1,49 @@
+// Copyright 2023 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package builder_test
+
+import (
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	. "github.com/weiwenchen2022/builder"
+	"github.com/weiwenchen2022/builder/buildertest"
+)
+
+func TestBuilderWriteStringValid(t *testing.T) {
+	t.Parallel()
+
+	inputs := append([]string{
+		"", "plain ascii", "h\u00e9llo \u4e16\u754c", "bad\xffbyte",
+		"\xe4\xb8", "\xed\xa0\x80", "a\xc0\xafb", strings.Repeat("x", 20) + "\x80",
+	}, buildertest.Adversarial...)
+	for _, s := range inputs {
+		var b Builder
+		want := strings.Map(func(r rune) rune { return r }, s)
+		n, err := b.WriteStringValid(s)
+		if err != nil || n != len(want) {
+			t.Errorf("WriteStringValid(%q): got %d,%v; want %d,nil", s, n, err, len(want))
+		}
+		check(t, &b, want)
+	}
+}
+
+func TestBuilderSanitizeUTF8(t *testing.T) {
+	t.Parallel()
+
+	var b Builder
+	b.PushTransformer(SanitizeUTF8())
+	b.WriteString("ok \xe4")
+	b.WriteByte(0xb8)
+	if !utf8.ValidString(b.String()) {
+		t.Errorf("String: got invalid UTF-8 %q", b.String())
+	}
+	b.WriteString("\x96 \xff")
+	b.Write([]byte{' ', 0xe4, 0xb8})
+	b.PopTransformer()
+	b.WriteString("\xff")
+	check(t, &b, "ok \u4e16 \ufffd \ufffd\ufffd\xff")
+}
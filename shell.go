@@ -0,0 +1,126 @@
+// Copyright 2023 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package builder
+
+// WriteShellQuoted appends s to b's buffer quoted for a POSIX shell, so that
+// the shell reads it back as a single word with the same value. Words made
+// only of characters no shell treats specially are written unquoted; any
+// other word is enclosed in single quotes, and each single quote in s is
+// written as a backslash-escaped quote between closing and reopening quotes.
+// See WriteWindowsArg for Windows command lines.
+// It returns the length of written and a nil error.
+func (b *Builder) WriteShellQuoted(s string) (int, error) {
+	b.copyCheck()
+	n := len(b.buf)
+	b.buf = appendShellQuoted(b.buf, s)
+	return b.commit(n), nil
+}
+
+func appendShellQuoted(dst []byte, s string) []byte {
+	if s == "" {
+		return append(dst, "''"...)
+	}
+
+	safe := true
+	for i := 0; i < len(s); i++ {
+		if !isShellSafe(s[i]) {
+			safe = false
+			break
+		}
+	}
+	if safe {
+		return append(dst, s...)
+	}
+
+	dst = append(dst, '\'')
+	for i := 0; i < len(s); i++ {
+		if s[i] == '\'' {
+			dst = append(dst, `'\''`...)
+		} else {
+			dst = append(dst, s[i])
+		}
+	}
+	return append(dst, '\'')
+}
+
+func isShellSafe(c byte) bool {
+	switch {
+	case 'a' <= c && c <= 'z', 'A' <= c && c <= 'Z', '0' <= c && c <= '9':
+		return true
+	}
+	switch c {
+	case '@', '%', '+', '=', ':', ',', '.', '/', '_', '-':
+		return true
+	}
+	return false
+}
+
+// WriteWindowsArg appends s to b's buffer escaped as a single Windows
+// command-line argument, following the rules CommandLineToArgvW and the
+// Microsoft C runtime use to split a command line: the argument is quoted if
+// it is empty or contains white space, double quotes are escaped with a
+// backslash and backslashes preceding a double quote are doubled.
+// This differs from the escaping a POSIX shell expects.
+// It returns the length of written and a nil error.
+func (b *Builder) WriteWindowsArg(s string) (int, error) {
+	b.copyCheck()
+	n := len(b.buf)
+	b.buf = appendWindowsArg(b.buf, s)
+	return b.commit(n), nil
+}
+
+func appendWindowsArg(dst []byte, s string) []byte {
+	if s == "" {
+		return append(dst, `""`...)
+	}
+
+	needsBackslash := false
+	hasSpace := false
+	for i := 0; i < len(s); i++ {
+		switch s[i] {
+		case '"', '\\':
+			needsBackslash = true
+		case ' ', '\t', '\n', '\v':
+			hasSpace = true
+		}
+	}
+
+	if !needsBackslash && !hasSpace {
+		return append(dst, s...)
+	}
+	if !needsBackslash {
+		dst = append(dst, '"')
+		dst = append(dst, s...)
+		return append(dst, '"')
+	}
+
+	if hasSpace {
+		dst = append(dst, '"')
+	}
+	slashes := 0
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch c {
+		default:
+			slashes = 0
+		case '\\':
+			slashes++
+		case '"':
+			for ; slashes > 0; slashes-- {
+				dst = append(dst, '\\')
+			}
+			dst = append(dst, '\\')
+		}
+		dst = append(dst, c)
+	}
+	if hasSpace {
+		// Backslashes before the closing quote must be doubled too.
+		for ; slashes > 0; slashes-- {
+			dst = append(dst, '\\')
+		}
+		dst = append(dst, '"')
+	}
+	return dst
+}
@@ -0,0 +1,134 @@
+// Copyright 2023 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package builder_test
+
+import (
+	"testing"
+
+	. "github.com/weiwenchen2022/builder"
+)
+
+func TestBuilderWriteWindowsArg(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		in, want string
+	}{
+		{``, `""`},
+		{`plain`, `plain`},
+		{`C:\Program Files\app`, `"C:\Program Files\app"`},
+		{`a"b`, `a\"b`},
+		{`a\b`, `a\b`},
+		{`a\"b`, `a\\\"b`},
+		{`a b\`, `"a b\\"`},
+		{`a b\\`, `"a b\\\\"`},
+		{`say "hi" \\"`, `"say \"hi\" \\\\\""`},
+		{"tab\there", "\"tab\there\""},
+	}
+
+	for _, tt := range tests {
+		var b Builder
+		n, err := b.WriteWindowsArg(tt.in)
+		if err != nil || len(tt.want) != n {
+			t.Errorf("WriteWindowsArg(%q): got %d,%v; want %d,nil", tt.in, n, err, len(tt.want))
+		}
+		check(t, &b, tt.want)
+
+		if got := splitWindowsArg(b.String()); tt.in != got {
+			t.Errorf("WriteWindowsArg(%q) = %q parses back as %q", tt.in, b.String(), got)
+		}
+	}
+}
+
+// splitWindowsArg parses a single argument following the rules of
+// CommandLineToArgvW.
+func splitWindowsArg(cmd string) string {
+	var arg []byte
+	for i := 0; i < len(cmd); {
+		c := cmd[i]
+		switch c {
+		case '\\':
+			n := 0
+			for i < len(cmd) && cmd[i] == '\\' {
+				n++
+				i++
+			}
+			if i < len(cmd) && cmd[i] == '"' {
+				for ; n >= 2; n -= 2 {
+					arg = append(arg, '\\')
+				}
+				if n == 1 {
+					arg = append(arg, '"')
+					i++
+				}
+				continue
+			}
+			for ; n > 0; n-- {
+				arg = append(arg, '\\')
+			}
+			continue
+		case '"':
+			// Unescaped quotes only group white space into the argument.
+		default:
+			arg = append(arg, c)
+		}
+		i++
+	}
+	return string(arg)
+}
+
+func TestBuilderWriteShellQuoted(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		s, want string
+	}{
+		{"", "''"},
+		{"plain", "plain"},
+		{"/usr/local/bin/go", "/usr/local/bin/go"},
+		{"--flag=a,b:c@d%e+f", "--flag=a,b:c@d%e+f"},
+		{"two words", "'two words'"},
+		{"it's", `'it'\''s'`},
+		{"$HOME `id` $(id) \\ \"x\" *?[a]", "'$HOME `id` $(id) \\ \"x\" *?[a]'"},
+		{"line\nbreak", "'line\nbreak'"},
+		{"'", `''\'''`},
+		{"héllo", "'héllo'"},
+	}
+
+	for _, tt := range tests {
+		var b Builder
+		n, err := b.WriteShellQuoted(tt.s)
+		if err != nil || len(tt.want) != n {
+			t.Errorf("WriteShellQuoted(%q): got %d,%v; want %d,nil", tt.s, n, err, len(tt.want))
+		}
+		check(t, &b, tt.want)
+		if got := splitShellWord(b.String()); tt.s != got {
+			t.Errorf("WriteShellQuoted(%q) = %s, which a shell reads as %q", tt.s, b.String(), got)
+		}
+	}
+}
+
+// splitShellWord returns the value of the single POSIX shell word w, which
+// may only contain single-quoted strings, backslash escapes and plain text.
+func splitShellWord(w string) string {
+	var out []byte
+	for i := 0; i < len(w); i++ {
+		switch c := w[i]; c {
+		case '\'':
+			j := i + 1
+			for j < len(w) && w[j] != '\'' {
+				j++
+			}
+			out = append(out, w[i+1:j]...)
+			i = j
+		case '\\':
+			i++
+			out = append(out, w[i])
+		default:
+			out = append(out, c)
+		}
+	}
+	return string(out)
+}
@@ -0,0 +1,92 @@
+// Copyright 2023 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package builder
+
+// Shortcodes is the default table used by WriteWithShortcodes, mapping
+// shortcode names, without colons, to emoji. It holds a selection of the
+// names used by GitHub and Slack and may be extended before use.
+var Shortcodes = map[string]string{
+	"+1":                 "👍",
+	"-1":                 "👎",
+	"bug":                "🐛",
+	"construction":       "🚧",
+	"fire":               "🔥",
+	"heart":              "❤️",
+	"heavy_check_mark":   "✔️",
+	"hourglass":          "⌛",
+	"information_source": "ℹ️",
+	"lock":               "🔒",
+	"memo":               "📝",
+	"package":            "📦",
+	"rocket":             "🚀",
+	"sparkles":           "✨",
+	"smile":              "😄",
+	"tada":               "🎉",
+	"thumbsdown":         "👎",
+	"thumbsup":           "👍",
+	"warning":            "⚠️",
+	"white_check_mark":   "✅",
+	"wrench":             "🔧",
+	"x":                  "❌",
+	"zap":                "⚡",
+}
+
+// SetShortcodes sets the table used by WriteWithShortcodes. A nil table
+// selects Shortcodes.
+func (b *Builder) SetShortcodes(table map[string]string) {
+	if table == nil && b.mode == nil {
+		return
+	}
+	if b.mode == nil {
+		b.mode = new(mode)
+	}
+	b.mode.shortcodes = table
+}
+
+// WriteWithShortcodes appends s to b's buffer, replacing every shortcode
+// such as ":rocket:" whose name is in the table set by SetShortcodes with
+// the corresponding emoji. Names consist of ASCII letters, digits and the
+// characters _, + and -; unknown shortcodes are written unchanged.
+// It returns the length of written and a nil error.
+func (b *Builder) WriteWithShortcodes(s string) (int, error) {
+	table := Shortcodes
+	if b.mode != nil && b.mode.shortcodes != nil {
+		table = b.mode.shortcodes
+	}
+
+	b.copyCheck()
+	n := len(b.buf)
+	last := 0
+	for i := 0; i < len(s); i++ {
+		if s[i] != ':' {
+			continue
+		}
+		j := i + 1
+		for j < len(s) && isShortcodeByte(s[j]) {
+			j++
+		}
+		if j == i+1 || j == len(s) || s[j] != ':' {
+			// Not a shortcode; s[j] may start the next one.
+			i = j - 1
+			continue
+		}
+		emoji, ok := table[s[i+1:j]]
+		if !ok {
+			// The closing colon may open the next shortcode.
+			i = j - 1
+			continue
+		}
+		b.buf = append(b.buf, s[last:i]...)
+		b.buf = append(b.buf, emoji...)
+		last = j + 1
+		i = j
+	}
+	b.buf = append(b.buf, s[last:]...)
+	return b.commit(n), nil
+}
+
+func isShortcodeByte(c byte) bool {
+	return 'a' <= c && c <= 'z' || 'A' <= c && c <= 'Z' || '0' <= c && c <= '9' || c == '_' || c == '+' || c == '-'
+}
@@ -0,0 +1,50 @@
+// Copyright 2023 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package builder_test
+
+import (
+	"testing"
+
+	. "github.com/weiwenchen2022/builder"
+)
+
+func TestBuilderWriteWithShortcodes(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		s, want string
+	}{
+		{"", ""},
+		{"no codes here", "no codes here"},
+		{"ship it :rocket:", "ship it 🚀"},
+		{":tada::tada:", "🎉🎉"},
+		{":+1: and :-1:", "👍 and 👎"},
+		{"time 10:30:00", "time 10:30:00"},
+		{":unknown:rocket:", ":unknown🚀"},
+		{"a :: b :", "a :: b :"},
+		{":rocket", ":rocket"},
+		{":ro cket:", ":ro cket:"},
+	}
+
+	for _, tt := range tests {
+		var b Builder
+		n, err := b.WriteWithShortcodes(tt.s)
+		if err != nil || len(tt.want) != n {
+			t.Errorf("WriteWithShortcodes(%q): got %d,%v; want %d,nil", tt.s, n, err, len(tt.want))
+		}
+		check(t, &b, tt.want)
+	}
+}
+
+func TestBuilderSetShortcodes(t *testing.T) {
+	t.Parallel()
+
+	var b Builder
+	b.SetShortcodes(map[string]string{"shipit": "🐿️"})
+	b.WriteWithShortcodes(":shipit: :rocket: ")
+	b.SetShortcodes(nil)
+	b.WriteWithShortcodes(":shipit: :rocket:")
+	check(t, &b, "🐿️ :rocket: :shipit: 🚀")
+}
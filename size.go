@@ -0,0 +1,87 @@
+// Copyright 2023 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package builder
+
+import "errors"
+
+// ErrTooLarge is returned by GrowChecked and SizePlan.Size when a size does
+// not fit in an int or exceeds the runtime's limit on slice length. Running
+// out of memory for a size within those limits is still fatal.
+var ErrTooLarge = errors.New("builder: size too large")
+
+var errNegativeSize = errors.New("builder: negative size")
+
+// GrowChecked is like Grow but returns an error rather than panicking: an
+// error if n is negative, ErrTooLarge if the grown buffer would be larger
+// than the largest int or the runtime's slice length limit, and ErrFull if b
+// was created by NewFixed and n bytes do not fit. On error, b is unchanged.
+// GrowChecked does not guard against running out of memory, which remains a
+// fatal error.
+func (b *Builder) GrowChecked(n int) (err error) {
+	b.copyCheck()
+	if n < 0 {
+		return errNegativeSize
+	}
+	if cap(b.buf)-len(b.buf) >= n {
+		return nil
+	}
+	if b.mode != nil && b.mode.fixed != nil {
+		return ErrFull
+	}
+	if n > maxInt-len(b.buf) {
+		return ErrTooLarge
+	}
+
+	defer func() {
+		// make panics with a runtime error for lengths beyond the slice limit.
+		if recover() != nil {
+			err = ErrTooLarge
+		}
+	}()
+	b.grow(n)
+	return nil
+}
+
+// A SizePlan sums size estimates, such as the lengths of the parts of a large
+// join, detecting int overflow, so that a caller can plan a single
+// GrowChecked and degrade gracefully on absurd inputs rather than panic.
+// The zero value is an empty plan ready to use.
+type SizePlan struct {
+	n   int
+	err error
+}
+
+// Add adds n bytes to the plan. A negative n makes the plan invalid.
+func (p *SizePlan) Add(n int) {
+	switch {
+	case p.err != nil:
+	case n < 0:
+		p.err = errNegativeSize
+	case n > maxInt-p.n:
+		p.err = ErrTooLarge
+	default:
+		p.n += n
+	}
+}
+
+// AddN adds count items of size bytes each to the plan. A negative count or
+// size makes the plan invalid.
+func (p *SizePlan) AddN(count, size int) {
+	switch {
+	case p.err != nil:
+	case count < 0 || size < 0:
+		p.err = errNegativeSize
+	case size > 0 && count > maxInt/size:
+		p.err = ErrTooLarge
+	default:
+		p.Add(count * size)
+	}
+}
+
+// Size returns the planned number of bytes. It returns ErrTooLarge if the
+// sum overflows an int, and an error if an estimate was negative.
+func (p *SizePlan) Size() (int, error) {
+	return p.n, p.err
+}
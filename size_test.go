@@ -0,0 +1,84 @@
+// Copyright 2023 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package builder_test
+
+import (
+	"errors"
+	"math"
+	"testing"
+
+	. "github.com/weiwenchen2022/builder"
+)
+
+func TestBuilderGrowChecked(t *testing.T) {
+	t.Parallel()
+
+	var b Builder
+	b.WriteString("abc")
+	if err := b.GrowChecked(100); err != nil {
+		t.Fatalf("GrowChecked(100): %v", err)
+	}
+	if b.Cap()-b.Len() < 100 {
+		t.Errorf("GrowChecked(100): got capacity %d for length %d", b.Cap(), b.Len())
+	}
+	check(t, &b, "abc")
+
+	c := b.Cap()
+	for _, n := range []int{-1, math.MaxInt, math.MaxInt - 2, math.MaxInt / 2} {
+		if err := b.GrowChecked(n); err == nil {
+			t.Errorf("GrowChecked(%d): got nil error", n)
+		}
+		if b.Cap() != c {
+			t.Errorf("GrowChecked(%d): capacity changed from %d to %d", n, c, b.Cap())
+		}
+		check(t, &b, "abc")
+	}
+	if err := b.GrowChecked(math.MaxInt); !errors.Is(err, ErrTooLarge) {
+		t.Errorf("GrowChecked(MaxInt): got %v; want ErrTooLarge", err)
+	}
+
+	f := NewFixed(make([]byte, 8))
+	if err := f.GrowChecked(8); err != nil {
+		t.Errorf("fixed GrowChecked(8): %v", err)
+	}
+	if err := f.GrowChecked(9); !errors.Is(err, ErrFull) {
+		t.Errorf("fixed GrowChecked(9): got %v; want ErrFull", err)
+	}
+}
+
+func TestSizePlan(t *testing.T) {
+	t.Parallel()
+
+	var p SizePlan
+	if n, err := p.Size(); n != 0 || err != nil {
+		t.Errorf("empty Size: got %d,%v; want 0,nil", n, err)
+	}
+	p.Add(10)
+	p.AddN(3, 4)
+	p.AddN(0, math.MaxInt)
+	if n, err := p.Size(); n != 22 || err != nil {
+		t.Errorf("Size: got %d,%v; want 22,nil", n, err)
+	}
+
+	tests := []struct {
+		name string
+		plan func(p *SizePlan)
+		want error
+	}{
+		{"AddOverflow", func(p *SizePlan) { p.Add(math.MaxInt); p.Add(1) }, ErrTooLarge},
+		{"AddNOverflow", func(p *SizePlan) { p.AddN(math.MaxInt/2+1, 2) }, ErrTooLarge},
+		{"AddNSumOverflow", func(p *SizePlan) { p.Add(2); p.AddN(math.MaxInt/2, 2) }, ErrTooLarge},
+		{"AddNegative", func(p *SizePlan) { p.Add(-1); p.Add(1) }, nil},
+		{"AddNNegative", func(p *SizePlan) { p.AddN(-1, 1) }, nil},
+	}
+	for _, tt := range tests {
+		var p SizePlan
+		tt.plan(&p)
+		_, err := p.Size()
+		if err == nil || tt.want != nil && !errors.Is(err, tt.want) {
+			t.Errorf("%s: got %v; want %v", tt.name, err, tt.want)
+		}
+	}
+}
@@ -0,0 +1,94 @@
+// Copyright 2023 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build go1.21
+
+// Package slogutil helps log/slog handlers use a builder.Builder as their
+// record buffer. Records are written in the logfmt layout of
+// slog.TextHandler using Builder.WriteKV, so a custom handler only has to
+// decide what to write, not how to quote it.
+package slogutil
+
+import (
+	"log/slog"
+	"time"
+
+	"github.com/weiwenchen2022/builder"
+)
+
+// WriteRecord appends r to b as a logfmt line ending with a newline: the
+// time, unless it is zero, the level, the message and the attributes of r,
+// whose keys are qualified by groups, the groups opened by the handler's
+// WithGroup. It returns the first error from Builder.WriteKV, after which
+// the line is incomplete.
+func WriteRecord(b *builder.Builder, r slog.Record, groups []string) error {
+	if !r.Time.IsZero() {
+		if err := WriteTime(b, r.Time); err != nil {
+			return err
+		}
+	}
+	if err := WriteLevel(b, r.Level); err != nil {
+		return err
+	}
+	if _, err := b.WriteKV(slog.MessageKey, r.Message); err != nil {
+		return err
+	}
+
+	var err error
+	r.Attrs(func(a slog.Attr) bool {
+		err = WriteAttr(b, groups, a)
+		return err == nil
+	})
+	if err != nil {
+		return err
+	}
+	return b.WriteByte('\n')
+}
+
+// WriteTime appends the pair time=t, with t in RFC 3339 format with
+// nanoseconds.
+func WriteTime(b *builder.Builder, t time.Time) error {
+	_, err := b.WriteKV(slog.TimeKey, t)
+	return err
+}
+
+// WriteLevel appends the pair level=l, such as level=INFO.
+func WriteLevel(b *builder.Builder, l slog.Level) error {
+	_, err := b.WriteKV(slog.LevelKey, l.String())
+	return err
+}
+
+// WriteAttr appends a as one logfmt pair, or several if a is a group,
+// following the rules of slog.Handler: the value is resolved, empty
+// attributes and empty groups are ignored, and the attributes of a group are
+// qualified by its key unless it is empty. Keys are qualified by the given
+// groups, joined with dots.
+func WriteAttr(b *builder.Builder, groups []string, a slog.Attr) error {
+	a.Value = a.Value.Resolve()
+	if a.Equal(slog.Attr{}) {
+		return nil
+	}
+
+	if a.Value.Kind() == slog.KindGroup {
+		attrs := a.Value.Group()
+		if len(attrs) == 0 {
+			return nil
+		}
+		if a.Key != "" {
+			// Qualify by a.Key in a stack copy, so that nesting does not
+			// allocate or modify the caller's groups.
+			var stack [8]string
+			groups = append(append(stack[:0], groups...), a.Key)
+		}
+		for _, ga := range attrs {
+			if err := WriteAttr(b, groups, ga); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	_, err := b.WriteGroupKV(groups, a.Key, a.Value.Any())
+	return err
+}
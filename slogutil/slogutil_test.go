@@ -0,0 +1,89 @@
+// Copyright 2023 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build go1.21
+
+package slogutil_test
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/weiwenchen2022/builder"
+	"github.com/weiwenchen2022/builder/slogutil"
+)
+
+func TestWriteRecordMatchesTextHandler(t *testing.T) {
+	t.Parallel()
+
+	attrs := []slog.Attr{
+		slog.String("user", "alice"),
+		slog.String("note", "two words"),
+		slog.String("empty", ""),
+		slog.Int("n", -3),
+		slog.Float64("ratio", 0.25),
+		slog.Bool("ok", true),
+		slog.Duration("took", 1500*time.Millisecond),
+		slog.Any("err", errors.New("not found")),
+		slog.Group("req", slog.String("method", "GET"), slog.Int("status", 200)),
+		slog.Group("empty_group"),
+		slog.Group("", slog.String("inline", "yes")),
+		{},
+	}
+
+	r := slog.NewRecord(time.Time{}, slog.LevelWarn, "request done", 0)
+	r.AddAttrs(attrs...)
+
+	var want strings.Builder
+	h := slog.NewTextHandler(&want, nil).WithGroup("http")
+	if err := h.Handle(context.Background(), r); err != nil {
+		t.Fatal(err)
+	}
+
+	var b builder.Builder
+	if err := slogutil.WriteRecord(&b, r, []string{"http"}); err != nil {
+		t.Fatal(err)
+	}
+	if want.String() != b.String() {
+		t.Errorf("WriteRecord:\ngot  %s\nwant %s", b.String(), want.String())
+	}
+}
+
+func TestWriteAttrAllocs(t *testing.T) {
+	groups := []string{"http"}
+	attrs := []slog.Attr{
+		slog.Bool("ok", true),
+		slog.Group("req", slog.Int("status", 200), slog.Group("tls", slog.Bool("resumed", false))),
+	}
+
+	var b builder.Builder
+	b.Grow(256)
+	allocs := testing.AllocsPerRun(100, func() {
+		b.Reset()
+		b.Grow(256)
+		for _, a := range attrs {
+			slogutil.WriteAttr(&b, groups, a)
+		}
+	})
+	if want := "http.ok=true http.req.status=200 http.req.tls.resumed=false"; want != b.String() {
+		t.Errorf("WriteAttr: got %s; want %s", b.String(), want)
+	}
+	if allocs > 1 {
+		t.Errorf("WriteAttr: got %v allocs; want at most 1 for the buffer", allocs)
+	}
+}
+
+func TestWriteTime(t *testing.T) {
+	t.Parallel()
+
+	var b builder.Builder
+	slogutil.WriteTime(&b, time.Date(2024, 2, 14, 1, 2, 3, 500, time.UTC))
+	if want := "time=2024-02-14T01:02:03.0000005Z"; want != b.String() {
+		t.Errorf("WriteTime: got %s; want %s", b.String(), want)
+	}
+}
@@ -0,0 +1,67 @@
+// Copyright 2023 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package builder
+
+import "sort"
+
+// A SortedKV collects key-value pairs to be written in sorted order, as the
+// canonicalization steps of request signing schemes such as OAuth 1.0 and
+// AWS Signature Version 4, and of cache keys, require.
+// The zero value is an empty SortedKV ready to use.
+type SortedKV struct {
+	pairs []kvPair
+}
+
+type kvPair struct{ k, v string }
+
+// Add adds the pair k, v. Duplicate keys are kept.
+func (kv *SortedKV) Add(k, v string) {
+	kv.pairs = append(kv.pairs, kvPair{k, v})
+}
+
+// Len returns the number of pairs added.
+func (kv *SortedKV) Len() int { return len(kv.pairs) }
+
+// Reset removes all pairs.
+func (kv *SortedKV) Reset() { kv.pairs = kv.pairs[:0] }
+
+// WriteSorted appends the pairs to b's buffer, each key and value escaped by
+// escape and joined by kvSep, the pairs joined by pairSep, as in
+//
+//	kv.WriteSorted(&b, "=", "&", url.QueryEscape)
+//
+// Pairs are sorted by escaped key, then by escaped value, as the signing
+// schemes require. A nil escape writes keys and values unchanged. The pairs
+// of kv are not modified, so kv may be written several times, or
+// concurrently to different Builders.
+// It returns the length of written and a nil error.
+func (kv *SortedKV) WriteSorted(b *Builder, kvSep, pairSep string, escape func(string) string) (int, error) {
+	// Sort a copy, so that writing leaves the pairs in insertion order.
+	pairs := make([]kvPair, len(kv.pairs))
+	copy(pairs, kv.pairs)
+	if escape != nil {
+		for i, p := range pairs {
+			pairs[i] = kvPair{escape(p.k), escape(p.v)}
+		}
+	}
+	sort.SliceStable(pairs, func(i, j int) bool {
+		if pairs[i].k != pairs[j].k {
+			return pairs[i].k < pairs[j].k
+		}
+		return pairs[i].v < pairs[j].v
+	})
+
+	b.copyCheck()
+	n := len(b.buf)
+	for i, p := range pairs {
+		if i > 0 {
+			b.buf = append(b.buf, pairSep...)
+		}
+		b.buf = append(b.buf, p.k...)
+		b.buf = append(b.buf, kvSep...)
+		b.buf = append(b.buf, p.v...)
+	}
+	return b.commit(n), nil
+}
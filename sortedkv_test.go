@@ -0,0 +1,92 @@
+// Copyright 2023 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package builder_test
+
+import (
+	"net/url"
+	"sync"
+	"testing"
+
+	. "github.com/weiwenchen2022/builder"
+)
+
+func TestSortedKV(t *testing.T) {
+	t.Parallel()
+
+	var kv SortedKV
+	var b Builder
+	if n, err := kv.WriteSorted(&b, "=", "&", nil); n != 0 || err != nil {
+		t.Errorf("WriteSorted with no pairs: got %d,%v; want 0,nil", n, err)
+	}
+
+	kv.Add("b", "2")
+	kv.Add("a", "z")
+	kv.Add("a", "y")
+	kv.Add("c d", "e&f")
+	kv.Add("A", "upper")
+	if kv.Len() != 5 {
+		t.Errorf("Len: got %d; want 5", kv.Len())
+	}
+
+	want := "A=upper&a=y&a=z&b=2&c+d=e%26f"
+	n, err := kv.WriteSorted(&b, "=", "&", url.QueryEscape)
+	if err != nil || n != len(want) {
+		t.Errorf("WriteSorted: got %d,%v; want %d,nil", n, err, len(want))
+	}
+	check(t, &b, want)
+
+	// The query matches url.Values.Encode, which also sorts.
+	v := url.Values{"b": {"2"}, "a": {"y", "z"}, "c d": {"e&f"}, "A": {"upper"}}
+	if enc := v.Encode(); enc != want {
+		t.Errorf("url.Values.Encode: got %q; want %q", enc, want)
+	}
+
+	b.Reset()
+	kv.WriteSorted(&b, ": ", "\n", nil)
+	check(t, &b, "A: upper\na: y\na: z\nb: 2\nc d: e&f")
+
+	kv.Reset()
+	kv.Add("k", "v")
+	b.Reset()
+	kv.WriteSorted(&b, "=", "&", nil)
+	check(t, &b, "k=v")
+}
+
+func TestSortedKVConcurrent(t *testing.T) {
+	t.Parallel()
+
+	// WriteSorted must not sort kv in place, so that it can be written
+	// concurrently; run with -race.
+	var kv SortedKV
+	kv.Add("b", "2")
+	kv.Add("a", "1")
+	kv.Add("c", "3")
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			var b Builder
+			kv.WriteSorted(&b, "=", "&", nil)
+			if got, want := b.String(), "a=1&b=2&c=3"; got != want {
+				t.Errorf("WriteSorted: got %q; want %q", got, want)
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+func TestSortedKVEscapedOrder(t *testing.T) {
+	t.Parallel()
+
+	// "a0" sorts before "a<", but "a%3C" sorts before "a0",
+	// so the order must be that of the escaped keys.
+	var kv SortedKV
+	kv.Add("a0", "1")
+	kv.Add("a<", "2")
+	var b Builder
+	kv.WriteSorted(&b, "=", "&", url.QueryEscape)
+	check(t, &b, "a%3C=2&a0=1")
+}
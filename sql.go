@@ -0,0 +1,77 @@
+// Copyright 2023 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package builder
+
+import "errors"
+
+// A Dialect selects the SQL string literal syntax written by WriteSQLString.
+type Dialect int
+
+const (
+	// StandardSQL doubles single quotes, as the SQL standard specifies.
+	StandardSQL Dialect = iota
+
+	// MySQL escapes single quotes, backslashes, NUL, line breaks and
+	// Control-Z with backslash sequences, which MySQL and MariaDB interpret
+	// unless the NO_BACKSLASH_ESCAPES SQL mode is enabled.
+	MySQL
+
+	// PostgreSQL doubles single quotes, relying on
+	// standard_conforming_strings, on by default since PostgreSQL 9.1.
+	// PostgreSQL text cannot contain NUL bytes.
+	PostgreSQL
+)
+
+var errSQLNUL = errors.New("builder: PostgreSQL string literal cannot contain NUL")
+
+// WriteSQLString appends s to b's buffer as a single-quoted SQL string
+// literal for the given dialect, for rendering values inline in logged or
+// generated statements. It is no substitute for query parameters.
+// It returns the length of written. If s cannot be represented in the
+// dialect, b is left unchanged and an error is returned.
+func (b *Builder) WriteSQLString(s string, dialect Dialect) (int, error) {
+	if dialect == PostgreSQL {
+		for i := 0; i < len(s); i++ {
+			if s[i] == 0 {
+				return 0, errSQLNUL
+			}
+		}
+	}
+
+	b.copyCheck()
+	n := len(b.buf)
+	b.buf = append(b.buf, '\'')
+	last := 0
+	for i := 0; i < len(s); i++ {
+		var esc string
+		switch c := s[i]; {
+		case c == '\'':
+			esc = "''"
+			if dialect == MySQL {
+				esc = `\'`
+			}
+		case dialect != MySQL:
+			continue
+		case c == '\\':
+			esc = `\\`
+		case c == 0:
+			esc = `\0`
+		case c == '\n':
+			esc = `\n`
+		case c == '\r':
+			esc = `\r`
+		case c == '\x1a':
+			esc = `\Z`
+		default:
+			continue
+		}
+		b.buf = append(b.buf, s[last:i]...)
+		b.buf = append(b.buf, esc...)
+		last = i + 1
+	}
+	b.buf = append(b.buf, s[last:]...)
+	b.buf = append(b.buf, '\'')
+	return b.commit(n), nil
+}
@@ -0,0 +1,55 @@
+// Copyright 2023 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package builder_test
+
+import (
+	"testing"
+
+	. "github.com/weiwenchen2022/builder"
+)
+
+func TestBuilderWriteSQLString(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		s       string
+		dialect Dialect
+		want    string
+	}{
+		{"", StandardSQL, "''"},
+		{"plain", StandardSQL, "'plain'"},
+		{"O'Reilly", StandardSQL, "'O''Reilly'"},
+		{`back\slash "dq"`, StandardSQL, `'back\slash "dq"'`},
+		{"nul\x00", StandardSQL, "'nul\x00'"},
+		{"O'Reilly", MySQL, `'O\'Reilly'`},
+		{`back\slash`, MySQL, `'back\\slash'`},
+		{"a\x00b\nc\rd\x1ae\tf\"g", MySQL, `'a\0b\nc\rd\Ze` + "\t" + `f"g'`},
+		{"'; DROP TABLE t; --", MySQL, `'\'; DROP TABLE t; --'`},
+		{"O'Reilly", PostgreSQL, "'O''Reilly'"},
+		{`back\slash`, PostgreSQL, `'back\slash'`},
+		{"héllo\n", PostgreSQL, "'héllo\n'"},
+	}
+
+	for _, tt := range tests {
+		var b Builder
+		n, err := b.WriteSQLString(tt.s, tt.dialect)
+		if err != nil || len(tt.want) != n {
+			t.Errorf("WriteSQLString(%q, %d): got %d,%v; want %d,nil", tt.s, tt.dialect, n, err, len(tt.want))
+		}
+		check(t, &b, tt.want)
+	}
+}
+
+func TestBuilderWriteSQLStringError(t *testing.T) {
+	t.Parallel()
+
+	var b Builder
+	b.WriteString("x = ")
+	n, err := b.WriteSQLString("nul\x00", PostgreSQL)
+	if err == nil || n != 0 {
+		t.Errorf("WriteSQLString: got %d,%v; want 0,error", n, err)
+	}
+	check(t, &b, "x = ")
+}
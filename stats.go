@@ -0,0 +1,103 @@
+// Copyright 2023 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package builder
+
+import (
+	"sync/atomic"
+)
+
+// Stats are package-wide statistics of Builder use, collected while
+// enabled by EnableStats. All counts are cumulative: the package does not
+// pool Builders and cannot tell when one is no longer used, so there is no
+// count of live builders.
+type Stats struct {
+	BuildersUsed int64 // builders written to; a Reset builder counts again
+	Writes       int64 // calls to write methods
+	Bytes        int64 // bytes appended by write methods, excluding write modes
+	Grows        int64 // buffer allocations, including the first of each builder
+}
+
+// A StatsHook receives Builder events as they happen, for forwarding to a
+// metrics system such as Prometheus. Its methods are called synchronously
+// by the writing goroutine, possibly from many goroutines at once.
+type StatsHook interface {
+	// Wrote is called after a write method appended n bytes.
+	Wrote(n int)
+	// Grew is called when a write finds that the buffer of a builder has
+	// been reallocated from capacity oldCap to newCap since the last write.
+	Grew(oldCap, newCap int)
+}
+
+var (
+	statsEnabled atomic.Bool
+	stats        struct{ builders, writes, bytes, grows atomic.Int64 }
+	statsHook    atomic.Value // of hookHolder
+)
+
+type hookHolder struct{ h StatsHook }
+
+// EnableStats turns the collection of Stats, and calls to the hook set by
+// SetStatsHook, on or off. Collection is off by default; when on, every
+// write costs a few atomic operations and each builder allocates a little
+// state on its first write.
+func EnableStats(enabled bool) {
+	statsEnabled.Store(enabled)
+}
+
+// SetStatsHook sets the hook that is called while stats are enabled.
+// A nil h removes the hook.
+func SetStatsHook(h StatsHook) {
+	statsHook.Store(hookHolder{h})
+}
+
+// ReadStats returns the statistics collected so far.
+func ReadStats() Stats {
+	return Stats{
+		BuildersUsed: stats.builders.Load(),
+		Writes:       stats.writes.Load(),
+		Bytes:        stats.bytes.Load(),
+		Grows:        stats.grows.Load(),
+	}
+}
+
+// StatsValue returns ReadStats() as an any, so that the statistics can be
+// published with expvar:
+//
+//	expvar.Publish("builder", expvar.Func(builder.StatsValue))
+func StatsValue() any {
+	return ReadStats()
+}
+
+// recordStats records a write that appended m bytes.
+func (b *Builder) recordStats(m int) {
+	if b.mode == nil {
+		b.mode = new(mode)
+	}
+	var h StatsHook
+	if hh, ok := statsHook.Load().(hookHolder); ok {
+		h = hh.h
+	}
+
+	if !b.mode.counted {
+		b.mode.counted = true
+		stats.builders.Add(1)
+	}
+	stats.writes.Add(1)
+	stats.bytes.Add(int64(m))
+	if h != nil {
+		h.Wrote(m)
+	}
+
+	if c := cap(b.buf); c != b.mode.statsCap {
+		// Clip shrinks the capacity without allocating.
+		if c > b.mode.statsCap {
+			stats.grows.Add(1)
+			if h != nil {
+				h.Grew(b.mode.statsCap, c)
+			}
+		}
+		b.mode.statsCap = c
+	}
+}
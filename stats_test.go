@@ -0,0 +1,76 @@
+// Copyright 2023 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package builder_test
+
+import (
+	"expvar"
+	"testing"
+
+	. "github.com/weiwenchen2022/builder"
+)
+
+type recordingHook struct {
+	wrote int
+	grew  [][2]int
+}
+
+func (h *recordingHook) Wrote(n int)             { h.wrote += n }
+func (h *recordingHook) Grew(oldCap, newCap int) { h.grew = append(h.grew, [2]int{oldCap, newCap}) }
+
+// TestStats is not parallel: the statistics are package-wide, and parallel
+// tests only start once the sequential ones have finished.
+func TestStats(t *testing.T) {
+	var h recordingHook
+	SetStatsHook(&h)
+	EnableStats(true)
+	defer func() {
+		EnableStats(false)
+		SetStatsHook(nil)
+	}()
+
+	before := ReadStats()
+	var b Builder
+	b.Grow(8)
+	b.WriteString("hello")
+	b.WriteString(" world")
+	b.WriteByte('!')
+	var b2 Builder
+	b2.WriteString("x")
+	after := ReadStats()
+
+	if got := after.BuildersUsed - before.BuildersUsed; got != 2 {
+		t.Errorf("BuildersUsed: got %d; want 2", got)
+	}
+	if got := after.Writes - before.Writes; got != 4 {
+		t.Errorf("Writes: got %d; want 4", got)
+	}
+	if got := after.Bytes - before.Bytes; got != 13 {
+		t.Errorf("Bytes: got %d; want 13", got)
+	}
+	if got := after.Grows - before.Grows; got != 3 {
+		t.Errorf("Grows: got %d; want 3", got)
+	}
+	if h.wrote != 13 {
+		t.Errorf("hook Wrote total: got %d; want 13", h.wrote)
+	}
+	if len(h.grew) != 3 || h.grew[0] != [2]int{0, 8} || h.grew[1][0] != 8 {
+		t.Errorf("hook Grew calls: got %v", h.grew)
+	}
+
+	EnableStats(false)
+	b.WriteString("uncounted")
+	if got := ReadStats(); got != after {
+		t.Errorf("stats changed while disabled: got %+v; want %+v", got, after)
+	}
+}
+
+func TestStatsValue(t *testing.T) {
+	t.Parallel()
+
+	v := expvar.Func(StatsValue)
+	if s := v.String(); s == "" || s[0] != '{' {
+		t.Errorf("expvar.Func(StatsValue).String() = %q; want a JSON object", s)
+	}
+}
@@ -0,0 +1,73 @@
+// Copyright 2023 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package builder
+
+import (
+	"strconv"
+	"strings"
+	"time"
+)
+
+// A SubtitleFormat selects the syntax written by WriteCue.
+type SubtitleFormat int
+
+const (
+	SRT    SubtitleFormat = iota // SubRip, with timestamps such as 00:01:02,500
+	WebVTT                       // WebVTT, with timestamps such as 00:01:02.500
+)
+
+// WriteCue appends a subtitle cue to b's buffer: the cue index, the timing
+// line from start to end and the lines of text, followed by the blank line
+// that separates cues. Times are written with millisecond precision,
+// truncating, and negative times are written as zero.
+//
+// Blank lines in text are dropped, since they would end the cue early.
+// For WebVTT an index of zero or less omits the optional cue identifier,
+// and the "WEBVTT" file header must be written separately.
+// It returns the length of written and a nil error.
+func (b *Builder) WriteCue(format SubtitleFormat, index int, start, end time.Duration, text string) (int, error) {
+	b.copyCheck()
+	n := len(b.buf)
+	if format == SRT || index > 0 {
+		b.buf = strconv.AppendInt(b.buf, int64(index), 10)
+		b.buf = append(b.buf, b.eol()...)
+	}
+	b.buf = appendCueTime(b.buf, start, format)
+	b.buf = append(b.buf, " --> "...)
+	b.buf = appendCueTime(b.buf, end, format)
+	b.buf = append(b.buf, b.eol()...)
+	for text != "" {
+		var line string
+		line, text, _ = strings.Cut(text, "\n")
+		line = strings.TrimSuffix(line, "\r")
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		b.buf = append(b.buf, line...)
+		b.buf = append(b.buf, b.eol()...)
+	}
+	b.buf = append(b.buf, b.eol()...)
+	return b.commit(n), nil
+}
+
+// appendCueTime appends d as hh:mm:ss followed by the milliseconds,
+// separated by a comma for SRT and a dot for WebVTT.
+func appendCueTime(dst []byte, d time.Duration, format SubtitleFormat) []byte {
+	if d < 0 {
+		d = 0
+	}
+	ms := int(d / time.Millisecond)
+	dst = appendZeroPadded(dst, ms/3600000, 2)
+	dst = append(dst, ':')
+	dst = appendZeroPadded(dst, ms/60000%60, 2)
+	dst = append(dst, ':')
+	dst = appendZeroPadded(dst, ms/1000%60, 2)
+	if format == SRT {
+		dst = append(dst, ',')
+	} else {
+		dst = append(dst, '.')
+	}
+	return appendZeroPadded(dst, ms%1000, 3)
+}
@@ -0,0 +1,54 @@
+// Copyright 2023 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package builder_test
+
+import (
+	"testing"
+	"time"
+
+	. "github.com/weiwenchen2022/builder"
+)
+
+func TestBuilderWriteCue(t *testing.T) {
+	t.Parallel()
+
+	const (
+		ms = time.Millisecond
+		s  = time.Second
+		h  = time.Hour
+	)
+
+	tests := []struct {
+		format     SubtitleFormat
+		index      int
+		start, end time.Duration
+		text       string
+		want       string
+	}{
+		{SRT, 1, 1 * s, 4*s + 500*ms, "Hello", "1\n00:00:01,000 --> 00:00:04,500\nHello\n\n"},
+		{WebVTT, 1, 1 * s, 4*s + 500*ms, "Hello", "1\n00:00:01.000 --> 00:00:04.500\nHello\n\n"},
+		{WebVTT, 0, 61*s + 7*ms, 62 * s, "no id", "00:01:01.007 --> 00:01:02.000\nno id\n\n"},
+		{SRT, 12, 100*h + 999999*time.Microsecond, 101 * h, "a\r\n\nb\n", "12\n100:00:00,999 --> 101:00:00,000\na\nb\n\n"},
+		{SRT, 2, -s, 0, "", "2\n00:00:00,000 --> 00:00:00,000\n\n"},
+	}
+
+	for _, tt := range tests {
+		var b Builder
+		n, err := b.WriteCue(tt.format, tt.index, tt.start, tt.end, tt.text)
+		if err != nil || len(tt.want) != n {
+			t.Errorf("WriteCue(%v, %d, %v, %v, %q): got %d,%v; want %d,nil", tt.format, tt.index, tt.start, tt.end, tt.text, n, err, len(tt.want))
+		}
+		check(t, &b, tt.want)
+	}
+}
+
+func TestBuilderWriteCueCRLF(t *testing.T) {
+	t.Parallel()
+
+	var b Builder
+	b.SetLineEnding(CRLF)
+	b.WriteCue(SRT, 1, 0, time.Second, "one\ntwo")
+	check(t, &b, "1\r\n00:00:00,000 --> 00:00:01,000\r\none\r\ntwo\r\n\r\n")
+}
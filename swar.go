@@ -0,0 +1,93 @@
+// Copyright 2023 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package builder
+
+// The escapers spend most of their time looking for the next byte that
+// needs attention. The functions in this file skip runs of ordinary bytes
+// eight at a time, testing a whole word with SIMD-within-a-register bit
+// tricks; the caller's byte loop then handles the word holding the hit.
+// The tests are exact: a word is only rejected if one of its bytes matches.
+
+const (
+	lsb = 0x0101010101010101
+	msb = 0x8080808080808080
+)
+
+// load64 returns the eight bytes of s starting at i as a little-endian word.
+// The compiler combines the loads into one.
+func load64[S string | []byte](s S, i int) uint64 {
+	_ = s[i+7]
+	return uint64(s[i]) | uint64(s[i+1])<<8 | uint64(s[i+2])<<16 | uint64(s[i+3])<<24 |
+		uint64(s[i+4])<<32 | uint64(s[i+5])<<40 | uint64(s[i+6])<<48 | uint64(s[i+7])<<56
+}
+
+// hasZero returns a non-zero value if any byte of x is zero.
+func hasZero(x uint64) uint64 {
+	return (x - lsb) & ^x & msb
+}
+
+// hasByte returns a non-zero value if any byte of x equals c.
+func hasByte(x uint64, c byte) uint64 {
+	return hasZero(x ^ (lsb * uint64(c)))
+}
+
+// hasLess returns a non-zero value if any byte of x is less than n,
+// which must be at most 128.
+func hasLess(x uint64, n byte) uint64 {
+	return (x - lsb*uint64(n)) & ^x & msb
+}
+
+// jsonSafePrefix returns the length of the longest prefix of s made of whole
+// words with no control character, '"' or '\\', nor, if ascii is true,
+// any byte outside ASCII.
+func jsonSafePrefix(s string, ascii bool) int {
+	var high uint64
+	if ascii {
+		high = msb
+	}
+	i := 0
+	for ; i+8 <= len(s); i += 8 {
+		x := load64(s, i)
+		if hasLess(x, 0x20)|hasByte(x, '"')|hasByte(x, '\\')|x&high != 0 {
+			break
+		}
+	}
+	return i
+}
+
+// htmlSafePrefix returns the length of the longest prefix of s made of whole
+// words with none of the bytes escaped by appendHTMLEscaped.
+func htmlSafePrefix(s string) int {
+	i := 0
+	for ; i+8 <= len(s); i += 8 {
+		x := load64(s, i)
+		if hasByte(x, '<')|hasByte(x, '>')|hasByte(x, '&')|hasByte(x, '\'')|hasByte(x, '"') != 0 {
+			break
+		}
+	}
+	return i
+}
+
+// csvSafePrefix returns the length of the longest prefix of s made of whole
+// words with no line break, '"' or comma, which must be ASCII.
+func csvSafePrefix(s string, comma byte) int {
+	i := 0
+	for ; i+8 <= len(s); i += 8 {
+		x := load64(s, i)
+		if hasByte(x, '\n')|hasByte(x, '\r')|hasByte(x, '"')|hasByte(x, comma) != 0 {
+			break
+		}
+	}
+	return i
+}
+
+// asciiPrefix returns the length of the longest prefix of p made of whole
+// words of ASCII bytes.
+func asciiPrefix[S string | []byte](p S) int {
+	i := 0
+	for ; i+8 <= len(p) && load64(p, i)&msb == 0; i += 8 {
+	}
+	return i
+}
@@ -0,0 +1,123 @@
+// Copyright 2023 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package builder_test
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"html"
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	. "github.com/weiwenchen2022/builder"
+)
+
+// The escapers skip ordinary bytes a word at a time, so place each special
+// byte at every offset around the word boundaries.
+var wordBoundarySpecials = []string{
+	"\x00", "\x1f", "\n", "\r", "\"", "\\", "<", ">", "&", "'", ",",
+	"\x7f", "\x80", "\xff", "é", " ", "\U0001F600",
+}
+
+func forEachWordBoundary(f func(s string)) {
+	const filler = "abcdefghijklmnopqrstuvwxyz0123456789ABCDEFGHIJ"
+	for _, sp := range wordBoundarySpecials {
+		for i := 0; i <= 40; i++ {
+			f(filler[:i] + sp)
+			f(filler[:i] + sp + filler[i:])
+		}
+	}
+}
+
+func TestBuilderWordBoundaryJSONString(t *testing.T) {
+	t.Parallel()
+
+	forEachWordBoundary(func(s string) {
+		var b Builder
+		b.WriteJSONString(s)
+		var got string
+		if err := json.Unmarshal([]byte(b.String()), &got); err != nil {
+			t.Fatalf("WriteJSONString(%q) = %s: %v", s, b.String(), err)
+		}
+		if utf8.ValidString(s) && got != s {
+			t.Errorf("WriteJSONString(%q) round trip: got %q", s, got)
+		}
+	})
+}
+
+func TestBuilderWordBoundaryHTMLEscaped(t *testing.T) {
+	t.Parallel()
+
+	forEachWordBoundary(func(s string) {
+		var b Builder
+		b.WriteHTMLEscaped(s)
+		if want := html.EscapeString(s); b.String() != want {
+			t.Errorf("WriteHTMLEscaped(%q): got %q; want %q", s, b.String(), want)
+		}
+	})
+}
+
+func TestBuilderWordBoundaryCSVField(t *testing.T) {
+	t.Parallel()
+
+	forEachWordBoundary(func(s string) {
+		if !utf8.ValidString(s) || strings.Contains(s, "\r") {
+			return // encoding/csv rewrites these
+		}
+		var b Builder
+		b.WriteCSVField(s)
+		var want bytes.Buffer
+		w := csv.NewWriter(&want)
+		w.Write([]string{s})
+		w.Flush()
+		if got := b.String() + "\n"; got != want.String() {
+			t.Errorf("WriteCSVField(%q): got %q; want %q", s, got, want.String())
+		}
+	})
+}
+
+func TestBuilderWordBoundaryValidUTF8(t *testing.T) {
+	t.Parallel()
+
+	forEachWordBoundary(func(s string) {
+		var b Builder
+		b.AddValidator(ValidUTF8())
+		b.WriteString(s)
+		if got, want := b.Err() == nil, utf8.ValidString(s); got != want {
+			t.Errorf("ValidUTF8(%q): valid = %v; want %v", s, got, want)
+		}
+	})
+}
+
+var escapeBenchInput = strings.Repeat("The quick brown fox jumps over the lazy dog. ", 20) + "<\"&\">"
+
+func BenchmarkWriteJSONString(b *testing.B) {
+	var buf Builder
+	b.SetBytes(int64(len(escapeBenchInput)))
+	for i := 0; i < b.N; i++ {
+		buf.Reset()
+		buf.WriteJSONString(escapeBenchInput)
+	}
+}
+
+func BenchmarkWriteHTMLEscaped(b *testing.B) {
+	var buf Builder
+	b.SetBytes(int64(len(escapeBenchInput)))
+	for i := 0; i < b.N; i++ {
+		buf.Reset()
+		buf.WriteHTMLEscaped(escapeBenchInput)
+	}
+}
+
+func BenchmarkWriteCSVField(b *testing.B) {
+	var buf Builder
+	b.SetBytes(int64(len(escapeBenchInput)))
+	for i := 0; i < b.N; i++ {
+		buf.Reset()
+		buf.WriteCSVField(escapeBenchInput)
+	}
+}
@@ -0,0 +1,80 @@
+// Copyright 2023 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package builder
+
+// A Table writes rows of cells aligned in columns of fixed width directly to
+// a Builder, like a lightweight text/tabwriter that needs no second buffer.
+// Cells are measured like the other alignment helpers; see SetEastAsianWidth.
+type Table struct {
+	b      *Builder
+	widths []int
+	sep    string
+}
+
+// Columns returns a Table writing to b with columns of the given widths.
+// A positive width left-aligns the cells of its column and a negative width
+// right-aligns them in a column of -width. Columns are separated by two spaces.
+func (b *Builder) Columns(widths ...int) *Table {
+	return &Table{b: b, widths: widths, sep: "  "}
+}
+
+// SetSeparator sets the string written between columns.
+func (t *Table) SetSeparator(sep string) {
+	t.sep = sep
+}
+
+// Row writes a row of cells followed by a line ending. Cells wider than their
+// column overflow it, shifting the rest of the row. Cells beyond the last
+// column are written unpadded, and a left-aligned last cell is not padded,
+// so rows have no trailing spaces.
+func (t *Table) Row(cells ...string) {
+	b := t.b
+	b.copyCheck()
+	n := len(b.buf)
+	for i, cell := range cells {
+		if i > 0 {
+			b.buf = append(b.buf, t.sep...)
+		}
+
+		width := 0
+		if i < len(t.widths) {
+			width = t.widths[i]
+		}
+		switch {
+		case width < 0:
+			b.buf = b.appendPad(b.buf, ' ', -width-b.width(cell))
+			b.buf = append(b.buf, cell...)
+		case i == len(cells)-1:
+			b.buf = append(b.buf, cell...)
+		default:
+			b.buf = append(b.buf, cell...)
+			b.buf = b.appendPad(b.buf, ' ', width-b.width(cell))
+		}
+	}
+	b.buf = append(b.buf, b.eol()...)
+	b.commit(n)
+}
+
+// Rule writes a horizontal rule spanning all columns, drawn with fill,
+// followed by a line ending.
+func (t *Table) Rule(fill rune) {
+	width := 0
+	for i, w := range t.widths {
+		if i > 0 {
+			width += t.b.width(t.sep)
+		}
+		if w < 0 {
+			w = -w
+		}
+		width += w
+	}
+
+	b := t.b
+	b.copyCheck()
+	n := len(b.buf)
+	b.buf = b.appendPad(b.buf, fill, width)
+	b.buf = append(b.buf, b.eol()...)
+	b.commit(n)
+}
@@ -0,0 +1,51 @@
+// Copyright 2023 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package builder_test
+
+import (
+	"testing"
+
+	. "github.com/weiwenchen2022/builder"
+)
+
+func TestTable(t *testing.T) {
+	t.Parallel()
+
+	var b Builder
+	tbl := b.Columns(8, -6, 10)
+	tbl.Row("NAME", "SIZE", "STATUS")
+	tbl.Rule('-')
+	tbl.Row("gopher", "42", "running")
+	tbl.Row("verylongname", "7", "ok")
+	tbl.Row("x")
+	tbl.Row("a", "1", "b", "extra")
+
+	const want = "" +
+		"NAME        SIZE  STATUS\n" +
+		"----------------------------\n" +
+		"gopher        42  running\n" +
+		"verylongname       7  ok\n" +
+		"x\n" +
+		"a              1  b           extra\n"
+	check(t, &b, want)
+}
+
+func TestTableSeparatorEastAsian(t *testing.T) {
+	t.Parallel()
+
+	var b Builder
+	b.SetEastAsianWidth(true)
+	tbl := b.Columns(6, 4)
+	tbl.SetSeparator(" | ")
+	tbl.Row("世界", "ok")
+	tbl.Row("abc", "done")
+	tbl.Rule('=')
+
+	const want = "" +
+		"世界   | ok\n" +
+		"abc    | done\n" +
+		"=============\n"
+	check(t, &b, want)
+}
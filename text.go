@@ -0,0 +1,292 @@
+// Copyright 2023 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package builder
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// wide holds the East Asian wide (W) and fullwidth (F) ranges of Unicode
+// Standard Annex #11, which terminals display two columns wide.
+var wide = &unicode.RangeTable{
+	R16: []unicode.Range16{
+		{0x1100, 0x115f, 1},
+		{0x231a, 0x231b, 1},
+		{0x2329, 0x232a, 1},
+		{0x23e9, 0x23ec, 1},
+		{0x23f0, 0x23f3, 3},
+		{0x25fd, 0x25fe, 1},
+		{0x2614, 0x2615, 1},
+		{0x2648, 0x2653, 1},
+		{0x267f, 0x2693, 20},
+		{0x26a1, 0x26aa, 9},
+		{0x26ab, 0x26bd, 18},
+		{0x26be, 0x26c4, 6},
+		{0x26c5, 0x26ce, 9},
+		{0x26d4, 0x26ea, 22},
+		{0x26f2, 0x26f3, 1},
+		{0x26f5, 0x26fa, 5},
+		{0x26fd, 0x2705, 8},
+		{0x270a, 0x270b, 1},
+		{0x2728, 0x274c, 36},
+		{0x274e, 0x2753, 5},
+		{0x2754, 0x2755, 1},
+		{0x2757, 0x2757, 1},
+		{0x2795, 0x2797, 1},
+		{0x27b0, 0x27bf, 15},
+		{0x2b1b, 0x2b1c, 1},
+		{0x2b50, 0x2b55, 5},
+		{0x2e80, 0x303e, 1},
+		{0x3041, 0x33ff, 1},
+		{0x3400, 0x4dbf, 1},
+		{0x4e00, 0x9fff, 1},
+		{0xa000, 0xa4cf, 1},
+		{0xa960, 0xa97f, 1},
+		{0xac00, 0xd7a3, 1},
+		{0xf900, 0xfaff, 1},
+		{0xfe10, 0xfe19, 1},
+		{0xfe30, 0xfe6f, 1},
+		{0xff00, 0xff60, 1},
+		{0xffe0, 0xffe6, 1},
+	},
+	R32: []unicode.Range32{
+		{0x16fe0, 0x16fe4, 1},
+		{0x17000, 0x18aff, 1},
+		{0x1b000, 0x1b2ff, 1},
+		{0x1f004, 0x1f0cf, 203},
+		{0x1f18e, 0x1f191, 3},
+		{0x1f192, 0x1f19a, 1},
+		{0x1f200, 0x1f251, 1},
+		{0x1f300, 0x1f64f, 1},
+		{0x1f680, 0x1f6ff, 1},
+		{0x1f900, 0x1f9ff, 1},
+		{0x20000, 0x2fffd, 1},
+		{0x30000, 0x3fffd, 1},
+	},
+}
+
+// runeWidth returns the number of columns r occupies in a terminal.
+func runeWidth(r rune) int {
+	switch {
+	case r < 0x20 || r == 0x7f:
+		return 0
+	case r < utf8.RuneSelf:
+		return 1
+	case r == 0x200b || unicode.In(r, unicode.Mn, unicode.Me, unicode.Cf):
+		return 0
+	case unicode.Is(wide, r):
+		return 2
+	}
+	return 1
+}
+
+// width returns the width of s as measured by the padding, alignment and
+// truncation helpers; see SetEastAsianWidth.
+func (b *Builder) width(s string) int {
+	if b.mode == nil || !b.mode.eastAsian {
+		return utf8.RuneCountInString(s)
+	}
+	w := 0
+	for _, r := range s {
+		w += runeWidth(r)
+	}
+	return w
+}
+
+// runeWidth returns the width of r as measured by b.
+func (b *Builder) runeWidth(r rune) int {
+	if b.mode == nil || !b.mode.eastAsian {
+		return 1
+	}
+	return runeWidth(r)
+}
+
+// appendPad appends pad repeatedly to dst until it fills width columns,
+// as measured by b. It appends nothing if width is not positive.
+func (b *Builder) appendPad(dst []byte, pad rune, width int) []byte {
+	pw := b.runeWidth(pad)
+	if pw <= 0 {
+		pw = 1
+	}
+	count := width / pw
+	if count <= 0 {
+		return dst
+	}
+
+	if uint32(pad) < utf8.RuneSelf {
+		n := len(dst)
+		dst = append(dst, make([]byte, count)...)
+		fill(dst[n:], byte(pad))
+		return dst
+	}
+	for ; count > 0; count-- {
+		dst = utf8.AppendRune(dst, pad)
+	}
+	return dst
+}
+
+// WritePadLeft appends s to b's buffer, preceded by as many copies of pad as
+// needed to make it width runes wide, so that s is right-aligned. If s is
+// already that wide, it is written unchanged. See SetEastAsianWidth for
+// measuring display width instead of runes.
+// It returns the length of written and a nil error.
+func (b *Builder) WritePadLeft(s string, width int, pad rune) (int, error) {
+	b.copyCheck()
+	n := len(b.buf)
+	b.buf = b.appendPad(b.buf, pad, width-b.width(s))
+	b.buf = append(b.buf, s...)
+	return b.commit(n), nil
+}
+
+// WritePadRight appends s to b's buffer, followed by as many copies of pad as
+// needed to make it width runes wide, so that s is left-aligned. If s is
+// already that wide, it is written unchanged. See SetEastAsianWidth for
+// measuring display width instead of runes.
+// It returns the length of written and a nil error.
+func (b *Builder) WritePadRight(s string, width int, pad rune) (int, error) {
+	b.copyCheck()
+	n := len(b.buf)
+	b.buf = append(b.buf, s...)
+	b.buf = b.appendPad(b.buf, pad, width-b.width(s))
+	return b.commit(n), nil
+}
+
+// WriteCenter appends s to b's buffer centered in a field width runes wide,
+// with copies of pad on both sides. When the padding cannot be split evenly,
+// the extra column goes to the right. If s is already width runes wide, it is
+// written unchanged. See SetEastAsianWidth for measuring display width
+// instead of runes.
+// It returns the length of written and a nil error.
+func (b *Builder) WriteCenter(s string, width int, pad rune) (int, error) {
+	b.copyCheck()
+	n := len(b.buf)
+	padding := width - b.width(s)
+	b.buf = b.appendPad(b.buf, pad, padding/2)
+	b.buf = append(b.buf, s...)
+	b.buf = b.appendPad(b.buf, pad, padding-padding/2)
+	return b.commit(n), nil
+}
+
+// WriteTruncated appends s to b's buffer if it is at most maxWidth runes wide.
+// Otherwise it appends the longest prefix of s that, followed by ellipsis,
+// fits in maxWidth runes, and then ellipsis. s is only ever cut between runes.
+// See SetEastAsianWidth for measuring display width instead of runes.
+// It returns the length of written and a nil error.
+func (b *Builder) WriteTruncated(s string, maxWidth int, ellipsis string) (int, error) {
+	b.copyCheck()
+	n := len(b.buf)
+	if b.width(s) <= maxWidth {
+		b.buf = append(b.buf, s...)
+		return b.commit(n), nil
+	}
+
+	limit := maxWidth - b.width(ellipsis)
+	i, w := 0, 0
+	for i < len(s) {
+		r, size := utf8.DecodeRuneInString(s[i:])
+		rw := b.runeWidth(r)
+		if w+rw > limit {
+			break
+		}
+		w += rw
+		i += size
+	}
+	b.buf = append(b.buf, s[:i]...)
+	b.buf = append(b.buf, ellipsis...)
+	return b.commit(n), nil
+}
+
+// WrapOptions controls how WriteWrappedOptions wraps text.
+type WrapOptions struct {
+	// BreakLongWords splits words wider than a line across lines.
+	// Otherwise such words are put on a line of their own and overflow it.
+	BreakLongWords bool
+
+	// Indent is written at the start of every line produced by wrapping,
+	// giving a hanging indent. It counts towards the line width.
+	Indent string
+}
+
+// WriteWrapped appends s to b's buffer word-wrapped so that lines are at most
+// width runes wide. Lines break at runs of spaces and tabs, which are
+// collapsed to a single space; line breaks in s are kept. Lines end as set by
+// SetLineEnding. See SetEastAsianWidth
+// for measuring display width instead of runes.
+// It returns the length of written and a nil error.
+func (b *Builder) WriteWrapped(s string, width int) (int, error) {
+	return b.WriteWrappedOptions(s, width, WrapOptions{})
+}
+
+// WriteWrappedOptions is like WriteWrapped but wraps according to opts.
+func (b *Builder) WriteWrappedOptions(s string, width int, opts WrapOptions) (int, error) {
+	b.copyCheck()
+	n := len(b.buf)
+	indent := b.width(opts.Indent)
+	for i := 0; ; i++ {
+		line, rest, more := strings.Cut(s, "\n")
+		line = strings.TrimSuffix(line, "\r")
+		if i > 0 {
+			b.buf = append(b.buf, b.eol()...)
+		}
+		b.buf = b.appendWrapped(b.buf, line, width, indent, opts)
+		if !more {
+			break
+		}
+		s = rest
+	}
+	return b.commit(n), nil
+}
+
+// appendWrapped appends the wrapped form of line, which contains no newlines,
+// to dst.
+func (b *Builder) appendWrapped(dst []byte, line string, width, indent int, opts WrapOptions) []byte {
+	col := 0
+	empty := true // no word on the current output line yet
+	for _, word := range strings.FieldsFunc(line, func(r rune) bool { return r == ' ' || r == '\t' }) {
+		ww := b.width(word)
+		// A word too wide for any line is started on the current one
+		// when it is to be broken anyway.
+		long := opts.BreakLongWords && ww > width-indent && col+2 <= width
+		if !empty && col+1+ww > width && !long {
+			dst = append(dst, b.eol()...)
+			dst = append(dst, opts.Indent...)
+			col, empty = indent, true
+		}
+		if !empty {
+			dst = append(dst, ' ')
+			col++
+		}
+
+		for opts.BreakLongWords && col+ww > width {
+			// Fill the rest of the line, taking at least one rune so
+			// that narrow widths still make progress.
+			i, w := 0, 0
+			for i < len(word) {
+				r, size := utf8.DecodeRuneInString(word[i:])
+				rw := b.runeWidth(r)
+				if i > 0 && col+w+rw > width {
+					break
+				}
+				w += rw
+				i += size
+			}
+			if i == len(word) {
+				break
+			}
+			dst = append(dst, word[:i]...)
+			dst = append(dst, b.eol()...)
+			dst = append(dst, opts.Indent...)
+			word, ww = word[i:], ww-w
+			col = indent
+		}
+
+		dst = append(dst, word...)
+		col += ww
+		empty = false
+	}
+	return dst
+}
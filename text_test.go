@@ -0,0 +1,151 @@
+// Copyright 2023 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package builder_test
+
+import (
+	"testing"
+
+	. "github.com/weiwenchen2022/builder"
+)
+
+func TestBuilderWritePad(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		s           string
+		width       int
+		pad         rune
+		eastAsian   bool
+		left, right string
+	}{
+		{"abc", 6, ' ', false, "   abc", "abc   "},
+		{"abc", 3, ' ', false, "abc", "abc"},
+		{"abcdef", 3, ' ', false, "abcdef", "abcdef"},
+		{"", 2, '.', false, "..", ".."},
+		{"héllo", 7, '*', false, "**héllo", "héllo**"},
+		{"世界", 4, ' ', false, "  世界", "世界  "},
+		{"世界", 6, ' ', true, "  世界", "世界  "},
+		{"é", 3, '-', true, "--é", "é--"},
+		{"ab", 5, '·', false, "···ab", "ab···"},
+		{"ab", 6, '世', true, "世世ab", "ab世世"},
+		{"ab", -1, ' ', false, "ab", "ab"},
+	}
+
+	for _, tt := range tests {
+		var b Builder
+		b.SetEastAsianWidth(tt.eastAsian)
+		n, err := b.WritePadLeft(tt.s, tt.width, tt.pad)
+		if err != nil || len(tt.left) != n {
+			t.Errorf("WritePadLeft(%q, %d, %q): got %d,%v; want %d,nil", tt.s, tt.width, tt.pad, n, err, len(tt.left))
+		}
+		check(t, &b, tt.left)
+
+		b.Reset()
+		b.SetEastAsianWidth(tt.eastAsian)
+		n, err = b.WritePadRight(tt.s, tt.width, tt.pad)
+		if err != nil || len(tt.right) != n {
+			t.Errorf("WritePadRight(%q, %d, %q): got %d,%v; want %d,nil", tt.s, tt.width, tt.pad, n, err, len(tt.right))
+		}
+		check(t, &b, tt.right)
+	}
+}
+
+func TestBuilderWriteCenter(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		s         string
+		width     int
+		pad       rune
+		eastAsian bool
+		want      string
+	}{
+		{"abc", 7, ' ', false, "  abc  "},
+		{"abc", 6, '*', false, "*abc**"},
+		{"abc", 3, ' ', false, "abc"},
+		{"abcdef", 3, ' ', false, "abcdef"},
+		{"", 3, '=', false, "==="},
+		{"世界", 8, '·', true, "··世界··"},
+		{" Report ", 20, '=', false, "====== Report ======"},
+	}
+
+	for _, tt := range tests {
+		var b Builder
+		b.SetEastAsianWidth(tt.eastAsian)
+		n, err := b.WriteCenter(tt.s, tt.width, tt.pad)
+		if err != nil || len(tt.want) != n {
+			t.Errorf("WriteCenter(%q, %d, %q): got %d,%v; want %d,nil", tt.s, tt.width, tt.pad, n, err, len(tt.want))
+		}
+		check(t, &b, tt.want)
+	}
+}
+
+func TestBuilderWriteTruncated(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		s         string
+		max       int
+		ellipsis  string
+		eastAsian bool
+		want      string
+	}{
+		{"hello", 5, "...", false, "hello"},
+		{"hello, world", 8, "...", false, "hello..."},
+		{"hello, world", 8, "…", false, "hello, …"},
+		{"héllo wörld", 4, "", false, "héll"},
+		{"hello", 2, "...", false, "..."},
+		{"世界你好", 5, "…", true, "世界…"},
+		{"世界你好", 4, "…", true, "世…"},
+		{"e\u0301e\u0301e\u0301", 3, "…", true, "e\u0301e\u0301e\u0301"},
+		{"e\u0301e\u0301e\u0301x", 3, "…", true, "e\u0301e\u0301…"},
+	}
+
+	for _, tt := range tests {
+		var b Builder
+		b.SetEastAsianWidth(tt.eastAsian)
+		n, err := b.WriteTruncated(tt.s, tt.max, tt.ellipsis)
+		if err != nil || len(tt.want) != n {
+			t.Errorf("WriteTruncated(%q, %d, %q): got %d,%v; want %d,nil", tt.s, tt.max, tt.ellipsis, n, err, len(tt.want))
+		}
+		check(t, &b, tt.want)
+	}
+}
+
+func TestBuilderWriteWrapped(t *testing.T) {
+	t.Parallel()
+
+	const text = "The quick brown fox jumps over the lazy dog."
+	tests := []struct {
+		s     string
+		width int
+		opts  WrapOptions
+		want  string
+	}{
+		{text, 80, WrapOptions{}, text},
+		{text, 15, WrapOptions{}, "The quick brown\nfox jumps over\nthe lazy dog."},
+		{text, 10, WrapOptions{}, "The quick\nbrown fox\njumps over\nthe lazy\ndog."},
+		{"a  b\t\tc", 80, WrapOptions{}, "a b c"},
+		{"first para\n\nsecond para", 6, WrapOptions{}, "first\npara\n\nsecond\npara"},
+		{"see https://example.com/very/long/path now", 10, WrapOptions{}, "see\nhttps://example.com/very/long/path\nnow"},
+		{"see abcdefghijklmnop now", 10, WrapOptions{BreakLongWords: true}, "see abcdef\nghijklmnop\nnow"},
+		{"abcdefghij", 3, WrapOptions{BreakLongWords: true, Indent: "  "}, "abc\n  d\n  e\n  f\n  g\n  h\n  i\n  j"},
+		{text, 16, WrapOptions{Indent: "    "}, "The quick brown\n    fox jumps\n    over the\n    lazy dog."},
+		{"", 10, WrapOptions{}, ""},
+	}
+
+	for _, tt := range tests {
+		var b Builder
+		n, err := b.WriteWrappedOptions(tt.s, tt.width, tt.opts)
+		if err != nil || len(tt.want) != n {
+			t.Errorf("WriteWrappedOptions(%q, %d, %+v): got %d,%v; want %d,nil", tt.s, tt.width, tt.opts, n, err, len(tt.want))
+		}
+		check(t, &b, tt.want)
+	}
+
+	var b Builder
+	b.WriteWrapped(text, 15)
+	check(t, &b, "The quick brown\nfox jumps over\nthe lazy dog.")
+}
@@ -0,0 +1,81 @@
+// Copyright 2023 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package textutil attaches golang.org/x/text transforms, such as Unicode
+// normalization, charset encoding or accent stripping, to a builder.Builder,
+// turning it into a transforming sink. It is a separate package so that
+// programs that do not need it do not depend on golang.org/x/text.
+package textutil
+
+import (
+	"golang.org/x/text/transform"
+
+	"github.com/weiwenchen2022/builder"
+)
+
+// SetTransformer resets t and pushes it onto b's transformer stack, so that
+// all following writes pass through t before landing in b's buffer. t may
+// hold back the end of a write, such as a character that a following
+// combining mark may still change; b.PopTransformer removes t and writes the
+// data it holds back. If t reports an error, the rest of the write is passed
+// through unchanged.
+func SetTransformer(b *builder.Builder, t transform.Transformer) {
+	b.PushTransformer(Transformer(t))
+}
+
+// Transformer returns t reset and adapted to the builder.Transformer
+// interface, with a Flush method that writes the data t holds back.
+func Transformer(t transform.Transformer) builder.Transformer {
+	t.Reset()
+	return &adapter{t: t}
+}
+
+type adapter struct {
+	t   transform.Transformer
+	src []byte // input held back by t at the end of the previous write
+}
+
+func (a *adapter) Transform(dst, p []byte) []byte {
+	a.src = append(a.src, p...)
+	return a.transform(dst, false)
+}
+
+func (a *adapter) Flush(dst []byte) []byte {
+	dst = a.transform(dst, true)
+	a.t.Reset()
+	return dst
+}
+
+// transform appends the transformed form of a.src to dst, keeping in a.src
+// the input t holds back.
+func (a *adapter) transform(dst []byte, atEOF bool) []byte {
+	src := a.src
+	for {
+		if cap(dst)-len(dst) < len(src) {
+			dst = append(dst[:cap(dst)], make([]byte, len(src))...)[:len(dst)]
+		}
+		nDst, nSrc, err := a.t.Transform(dst[len(dst):cap(dst)], src, atEOF)
+		dst = dst[:len(dst)+nDst]
+		src = src[nSrc:]
+		switch err {
+		case nil:
+		case transform.ErrShortDst:
+			if nDst == 0 && nSrc == 0 {
+				dst = append(dst[:cap(dst)], 0)[:len(dst)]
+			}
+			continue
+		case transform.ErrShortSrc:
+			if !atEOF {
+				a.src = append(a.src[:0], src...)
+				return dst
+			}
+			dst = append(dst, src...)
+		default:
+			dst = append(dst, src...)
+			a.t.Reset()
+		}
+		a.src = a.src[:0]
+		return dst
+	}
+}
@@ -0,0 +1,76 @@
+// Copyright 2023 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package textutil_test
+
+import (
+	"strings"
+	"testing"
+	"unicode"
+
+	"golang.org/x/text/encoding/charmap"
+	"golang.org/x/text/runes"
+	"golang.org/x/text/transform"
+	"golang.org/x/text/unicode/norm"
+
+	"github.com/weiwenchen2022/builder"
+	. "github.com/weiwenchen2022/builder/textutil"
+)
+
+func TestSetTransformerNFC(t *testing.T) {
+	t.Parallel()
+
+	var b builder.Builder
+	SetTransformer(&b, norm.NFC)
+	// The combining acute accent arrives in a later write than its base.
+	b.WriteString("cafe")
+	b.WriteString("\u0301 ole")
+	b.WriteString("\u0301")
+	b.PopTransformer()
+	b.WriteString("e\u0301")
+	if got, want := b.String(), "caf\u00e9 ol\u00e9e\u0301"; got != want {
+		t.Errorf("String: got %q; want %q", got, want)
+	}
+}
+
+func TestSetTransformerLong(t *testing.T) {
+	t.Parallel()
+
+	var b builder.Builder
+	SetTransformer(&b, norm.NFD)
+	s := strings.Repeat("\u00e9", 5000)
+	b.WriteString(s)
+	b.PopTransformer()
+	if got, want := b.String(), strings.Repeat("e\u0301", 5000); got != want {
+		t.Errorf("String: got %d bytes; want %d", len(got), len(want))
+	}
+}
+
+func TestSetTransformerStripAccents(t *testing.T) {
+	t.Parallel()
+
+	var b builder.Builder
+	SetTransformer(&b, transform.Chain(norm.NFD, runes.Remove(runes.In(unicode.Mn)), norm.NFC))
+	b.WriteString("cr\u00e8me ")
+	b.WriteString("bru\u0302")
+	b.WriteString("l\u00e9e")
+	b.PopTransformer()
+	if got, want := b.String(), "creme brulee"; got != want {
+		t.Errorf("String: got %q; want %q", got, want)
+	}
+}
+
+func TestSetTransformerError(t *testing.T) {
+	t.Parallel()
+
+	var b builder.Builder
+	SetTransformer(&b, charmap.ISO8859_1.NewEncoder())
+	b.WriteString("caf\u00e9")
+	b.WriteString("\u4e16x")
+	b.WriteString("\u00e9")
+	b.PopTransformer()
+	if got, want := b.String(), "caf\xe9\u4e16x\xe9"; got != want {
+		t.Errorf("String: got %q; want %q", got, want)
+	}
+}
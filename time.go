@@ -0,0 +1,124 @@
+// Copyright 2023 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package builder
+
+import (
+	"strconv"
+	"time"
+)
+
+// appendZeroPadded appends the decimal form of i, which must not be
+// negative, left-padded with zeros to width digits.
+func appendZeroPadded(dst []byte, i, width int) []byte {
+	var scratch [20]byte
+	digits := strconv.AppendInt(scratch[:0], int64(i), 10)
+	for k := len(digits); k < width; k++ {
+		dst = append(dst, '0')
+	}
+	return append(dst, digits...)
+}
+
+// appendYear appends year with at least four digits, as ISO 8601 requires.
+func appendYear(dst []byte, year int) []byte {
+	if year < 0 {
+		dst = append(dst, '-')
+		year = -year
+	}
+	return appendZeroPadded(dst, year, 4)
+}
+
+// WriteISOWeek appends the ISO 8601 week date of t, such as "2024-W07-3",
+// to b's buffer. The year is the ISO week-numbering year, which differs from
+// t.Year() for some days near the start and end of a year, and days are
+// numbered from 1 for Monday to 7 for Sunday.
+// It returns the length of written and a nil error.
+func (b *Builder) WriteISOWeek(t time.Time) (int, error) {
+	year, week := t.ISOWeek()
+	day := int(t.Weekday())
+	if day == 0 {
+		day = 7
+	}
+
+	b.copyCheck()
+	n := len(b.buf)
+	b.buf = appendYear(b.buf, year)
+	b.buf = append(b.buf, '-', 'W')
+	b.buf = appendZeroPadded(b.buf, week, 2)
+	b.buf = append(b.buf, '-', byte('0'+day))
+	return b.commit(n), nil
+}
+
+// WriteOrdinalDate appends the ISO 8601 ordinal date of t, the year and the
+// day of the year, such as "2024-038", to b's buffer.
+// It returns the length of written and a nil error.
+func (b *Builder) WriteOrdinalDate(t time.Time) (int, error) {
+	b.copyCheck()
+	n := len(b.buf)
+	b.buf = appendYear(b.buf, t.Year())
+	b.buf = append(b.buf, '-')
+	b.buf = appendZeroPadded(b.buf, t.YearDay(), 3)
+	return b.commit(n), nil
+}
+
+// durationUnits are the units of WriteDurationHuman, largest first.
+var durationUnits = [...]struct {
+	name string
+	d    time.Duration
+}{
+	{"d", 24 * time.Hour},
+	{"h", time.Hour},
+	{"m", time.Minute},
+	{"s", time.Second},
+	{"ms", time.Millisecond},
+	{"µs", time.Microsecond},
+	{"ns", time.Nanosecond},
+}
+
+// WriteDurationHuman appends d to b's buffer as a list of whole units, such
+// as "2h 3m 4s" or "1d 4h", where a day is 24 hours. Units smaller than a
+// second are only used for durations under one second, as in "1ms 500µs".
+// prec limits the number of units counted from the largest one written,
+// truncating the rest: with prec 2, 1d 4h 30m is written as "1d 4h" and
+// 2h 0m 4s as "2h". Units with a zero value are omitted. A prec of zero or
+// less writes every unit. A zero duration is written as "0s".
+// It returns the length of written and a nil error.
+func (b *Builder) WriteDurationHuman(d time.Duration, prec int) (int, error) {
+	b.copyCheck()
+	n := len(b.buf)
+	u := uint64(d)
+	if d < 0 {
+		b.buf = append(b.buf, '-')
+		u = -u
+	}
+	if u == 0 {
+		b.buf = append(b.buf, "0s"...)
+		return b.commit(n), nil
+	}
+
+	units := durationUnits[:4]
+	if u < uint64(time.Second) {
+		units = durationUnits[4:]
+	}
+	first := true
+	for _, unit := range units {
+		v := u / uint64(unit.d)
+		u %= uint64(unit.d)
+		if first && v == 0 {
+			continue
+		}
+		if v != 0 {
+			if !first {
+				b.buf = append(b.buf, ' ')
+			}
+			b.buf = strconv.AppendUint(b.buf, v, 10)
+			b.buf = append(b.buf, unit.name...)
+		}
+		first = false
+		if prec--; prec == 0 {
+			break
+		}
+	}
+	return b.commit(n), nil
+}
@@ -0,0 +1,98 @@
+// Copyright 2023 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package builder_test
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	. "github.com/weiwenchen2022/builder"
+)
+
+func date(year int, month time.Month, day int) time.Time {
+	return time.Date(year, month, day, 12, 0, 0, 0, time.UTC)
+}
+
+func TestBuilderWriteISOWeek(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		t    time.Time
+		want string
+	}{
+		{date(2024, time.February, 14), "2024-W07-3"},
+		{date(2024, time.January, 1), "2024-W01-1"},
+		{date(2021, time.January, 3), "2020-W53-7"},
+		{date(2024, time.December, 30), "2025-W01-1"},
+		{date(999, time.June, 1), "0999-W22-6"},
+	}
+
+	for _, tt := range tests {
+		var b Builder
+		n, err := b.WriteISOWeek(tt.t)
+		if err != nil || len(tt.want) != n {
+			t.Errorf("WriteISOWeek(%v): got %d,%v; want %d,nil", tt.t, n, err, len(tt.want))
+		}
+		check(t, &b, tt.want)
+	}
+}
+
+func TestBuilderWriteOrdinalDate(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		t    time.Time
+		want string
+	}{
+		{date(2024, time.February, 7), "2024-038"},
+		{date(2024, time.December, 31), "2024-366"},
+		{date(2023, time.December, 31), "2023-365"},
+		{date(2023, time.January, 1), "2023-001"},
+	}
+
+	for _, tt := range tests {
+		var b Builder
+		n, err := b.WriteOrdinalDate(tt.t)
+		if err != nil || len(tt.want) != n {
+			t.Errorf("WriteOrdinalDate(%v): got %d,%v; want %d,nil", tt.t, n, err, len(tt.want))
+		}
+		check(t, &b, tt.want)
+	}
+}
+
+func TestBuilderWriteDurationHuman(t *testing.T) {
+	t.Parallel()
+
+	day := 24 * time.Hour
+	tests := []struct {
+		d    time.Duration
+		prec int
+		want string
+	}{
+		{0, 0, "0s"},
+		{2*time.Hour + 3*time.Minute + 4*time.Second, 0, "2h 3m 4s"},
+		{2*time.Hour + 3*time.Minute + 4*time.Second, 2, "2h 3m"},
+		{2*time.Hour + 4*time.Second, 2, "2h"},
+		{2*time.Hour + 4*time.Second, 3, "2h 4s"},
+		{day + 4*time.Hour + 30*time.Minute, 2, "1d 4h"},
+		{day + 4*time.Hour + 30*time.Minute, 1, "1d"},
+		{-90 * time.Second, 0, "-1m 30s"},
+		{1500 * time.Millisecond, 0, "1s"},
+		{1500 * time.Microsecond, 0, "1ms 500µs"},
+		{1500 * time.Microsecond, 1, "1ms"},
+		{42, 0, "42ns"},
+		{math.MaxInt64, 0, "106751d 23h 47m 16s"},
+		{math.MinInt64, 2, "-106751d 23h"},
+	}
+	for _, tt := range tests {
+		var b Builder
+		n, err := b.WriteDurationHuman(tt.d, tt.prec)
+		if err != nil || len(tt.want) != n {
+			t.Errorf("WriteDurationHuman(%v, %d): got %d,%v; want %d,nil", tt.d, tt.prec, n, err, len(tt.want))
+		}
+		check(t, &b, tt.want)
+	}
+}
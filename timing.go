@@ -0,0 +1,75 @@
+// Copyright 2023 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package builder
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// A SectionTiming records how long one call of TimedSection took.
+type SectionTiming struct {
+	Name     string        // section name, after those of enclosing sections and "/"
+	Duration time.Duration // wall time spent in the section, including nested sections
+	Bytes    int           // growth of the content during the section
+}
+
+// String returns t in the format used by TimingReport.
+func (t SectionTiming) String() string {
+	return t.Name + " " + t.Duration.String() + " " + strconv.Itoa(t.Bytes) + "B"
+}
+
+// TimedSection calls fn with b and records how long it took and how many
+// bytes it wrote, under name. Sections may be nested; the name of a nested
+// section is recorded after the names of the enclosing ones, separated
+// by "/".
+func (b *Builder) TimedSection(name string, fn func(*Builder)) {
+	if b.mode == nil {
+		b.mode = new(mode)
+	}
+	m := b.mode
+	parent := m.section
+	if parent != "" {
+		name = parent + "/" + name
+	}
+	i := len(m.timings)
+	m.timings = append(m.timings, SectionTiming{Name: name})
+	m.section = name
+	defer func() { m.section = parent }()
+
+	n := len(b.buf)
+	start := time.Now()
+	fn(b)
+	m.timings[i].Duration = time.Since(start)
+	m.timings[i].Bytes = len(b.buf) - n
+}
+
+// SectionTimings returns the timings recorded by TimedSection, in the order
+// the sections started. The caller must not modify the returned slice.
+func (b *Builder) SectionTimings() []SectionTiming {
+	if b.mode == nil {
+		return nil
+	}
+	return b.mode.timings
+}
+
+// TimingReport returns the timings recorded by TimedSection as text,
+// slowest section first, one line per section giving the name, the duration
+// and the number of bytes written, like
+//
+//	report/body 1.2s 48213B
+func (b *Builder) TimingReport() string {
+	timings := append([]SectionTiming(nil), b.SectionTimings()...)
+	sort.SliceStable(timings, func(i, j int) bool { return timings[i].Duration > timings[j].Duration })
+
+	var sb strings.Builder
+	for _, t := range timings {
+		sb.WriteString(t.String())
+		sb.WriteByte('\n')
+	}
+	return sb.String()
+}
@@ -0,0 +1,69 @@
+// Copyright 2023 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package builder_test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	. "github.com/weiwenchen2022/builder"
+)
+
+func TestBuilderTimedSection(t *testing.T) {
+	t.Parallel()
+
+	var b Builder
+	if got := b.SectionTimings(); got != nil {
+		t.Errorf("SectionTimings before any section: got %v; want nil", got)
+	}
+
+	b.TimedSection("header", func(b *Builder) {
+		b.WriteString("title\n")
+	})
+	b.TimedSection("body", func(b *Builder) {
+		b.WriteString("<")
+		b.TimedSection("slow", func(b *Builder) {
+			time.Sleep(20 * time.Millisecond)
+			b.WriteString("rows")
+		})
+		b.WriteString(">")
+	})
+	check(t, &b, "title\n<rows>")
+
+	timings := b.SectionTimings()
+	wantNames := []string{"header", "body", "body/slow"}
+	wantBytes := []int{6, 6, 4}
+	if len(timings) != len(wantNames) {
+		t.Fatalf("SectionTimings: got %v; want %d sections", timings, len(wantNames))
+	}
+	for i, tm := range timings {
+		if tm.Name != wantNames[i] || tm.Bytes != wantBytes[i] {
+			t.Errorf("section %d: got %s,%d; want %s,%d", i, tm.Name, tm.Bytes, wantNames[i], wantBytes[i])
+		}
+	}
+	if timings[2].Duration < 20*time.Millisecond || timings[1].Duration < timings[2].Duration {
+		t.Errorf("durations: body %v, body/slow %v; want body >= body/slow >= 20ms", timings[1].Duration, timings[2].Duration)
+	}
+
+	report := strings.Split(strings.TrimSuffix(b.TimingReport(), "\n"), "\n")
+	if len(report) != 3 || !strings.HasPrefix(report[0], "body ") || !strings.HasSuffix(report[0], " 6B") || !strings.HasPrefix(report[2], "header ") {
+		t.Errorf("TimingReport: got %q; want body first and header last", report)
+	}
+}
+
+func TestBuilderTimedSectionPanic(t *testing.T) {
+	t.Parallel()
+
+	var b Builder
+	func() {
+		defer func() { recover() }()
+		b.TimedSection("outer", func(b *Builder) { panic("boom") })
+	}()
+	b.TimedSection("next", func(b *Builder) {})
+	if got := b.SectionTimings()[1].Name; got != "next" {
+		t.Errorf("section after panic: got %q; want %q", got, "next")
+	}
+}
@@ -0,0 +1,66 @@
+// Copyright 2023 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package builder
+
+// A Transformer rewrites the content written to a Builder, as a stage of the
+// pipeline set up by PushTransformer. Transform appends the transformed form
+// of p, the bytes of one write, to dst and returns the extended slice. It is
+// fed every write, in order, so it can keep state across writes; note that a
+// write may end in the middle of a UTF-8 encoding. p is a scratch buffer that
+// the Builder reuses for the next write, so Transform must not retain it, and
+// must copy any bytes of p it holds back.
+//
+// If a Transformer holding back data also has a method Flush(dst []byte) []byte,
+// PopTransformer calls it to append the data held back.
+type Transformer interface {
+	Transform(dst, p []byte) []byte
+}
+
+// The TransformerFunc type is an adapter to allow the use of ordinary
+// functions as Transformers.
+type TransformerFunc func(dst, p []byte) []byte
+
+// Transform returns f(dst, p).
+func (f TransformerFunc) Transform(dst, p []byte) []byte { return f(dst, p) }
+
+// PushTransformer adds t to the transformer stack. The content of every
+// following write is passed through the stacked transformers, the most
+// recently pushed first, before the other write modes, such as line prefixes,
+// apply to it. The counts returned by write methods are those of the content
+// before it is transformed.
+func (b *Builder) PushTransformer(t Transformer) {
+	if b.mode == nil {
+		b.mode = new(mode)
+	}
+	b.mode.transformers = append(b.mode.transformers, t)
+}
+
+// PopTransformer removes the transformer most recently added by
+// PushTransformer, appending any data it holds back, as returned by its Flush
+// method, through the remaining transformers. It panics if the stack is empty.
+func (b *Builder) PopTransformer() {
+	if b.mode == nil || len(b.mode.transformers) == 0 {
+		panic("builder.Builder.PopTransformer: empty transformer stack")
+	}
+	m := b.mode
+	t := m.transformers[len(m.transformers)-1]
+	m.transformers = m.transformers[:len(m.transformers)-1]
+
+	if f, ok := t.(interface{ Flush(dst []byte) []byte }); ok {
+		b.copyCheck()
+		n := len(b.buf)
+		if b.buf = f.Flush(b.buf); len(b.buf) > n {
+			b.commit(n)
+		}
+	}
+}
+
+// transform passes b.buf[n:] through the transformer stack.
+func (m *mode) transform(b *Builder, n int) {
+	for i := len(m.transformers) - 1; i >= 0; i-- {
+		m.scratch = append(m.scratch[:0], b.buf[n:]...)
+		b.buf = m.transformers[i].Transform(b.buf[:n], m.scratch)
+	}
+}
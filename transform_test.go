@@ -0,0 +1,102 @@
+// Copyright 2023 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package builder_test
+
+import (
+	"bytes"
+	"testing"
+
+	. "github.com/weiwenchen2022/builder"
+)
+
+var upper = TransformerFunc(func(dst, p []byte) []byte {
+	return append(dst, bytes.ToUpper(p)...)
+})
+
+func wrap(open, close string) Transformer {
+	return TransformerFunc(func(dst, p []byte) []byte {
+		dst = append(dst, open...)
+		dst = append(dst, p...)
+		return append(dst, close...)
+	})
+}
+
+// pairer holds back an odd trailing byte and swaps each pair of bytes.
+type pairer struct{ held []byte }
+
+func (t *pairer) Transform(dst, p []byte) []byte {
+	t.held = append(t.held, p...)
+	i := 0
+	for ; i+1 < len(t.held); i += 2 {
+		dst = append(dst, t.held[i+1], t.held[i])
+	}
+	t.held = append(t.held[:0], t.held[i:]...)
+	return dst
+}
+
+func (t *pairer) Flush(dst []byte) []byte {
+	dst = append(dst, t.held...)
+	t.held = t.held[:0]
+	return dst
+}
+
+func TestBuilderTransformer(t *testing.T) {
+	t.Parallel()
+
+	var b Builder
+	b.WriteString("a")
+	b.PushTransformer(upper)
+	n, err := b.WriteString("bc")
+	if err != nil || n != 2 {
+		t.Errorf("WriteString(%q): got %d,%v; want 2,nil", "bc", n, err)
+	}
+	b.PushTransformer(wrap("[", "]"))
+	b.WriteByte('d')
+	b.PopTransformer()
+	b.WriteString("e")
+	b.PopTransformer()
+	b.WriteString("f")
+	check(t, &b, "aBC[D]Ef")
+}
+
+func TestBuilderTransformerState(t *testing.T) {
+	t.Parallel()
+
+	var b Builder
+	b.PushTransformer(upper)
+	b.PushTransformer(new(pairer))
+	b.WriteString("abc")
+	check(t, &b, "BA")
+	b.WriteString("de")
+	check(t, &b, "BADC")
+	b.PopTransformer()
+	check(t, &b, "BADCE")
+	b.WriteString("f")
+	check(t, &b, "BADCEF")
+}
+
+func TestBuilderTransformerModes(t *testing.T) {
+	t.Parallel()
+
+	var b Builder
+	b.PushLinePrefix("> ")
+	b.PushTransformer(upper)
+	b.WriteString("one\ntwo\n")
+	b.PopTransformer()
+	b.WriteString("three\n")
+	check(t, &b, "> ONE\n> TWO\n> three\n")
+}
+
+func TestBuilderPopTransformerEmpty(t *testing.T) {
+	t.Parallel()
+
+	defer func() {
+		if recover() == nil {
+			t.Error("PopTransformer on an empty stack did not panic")
+		}
+	}()
+	var b Builder
+	b.PopTransformer()
+}
@@ -0,0 +1,121 @@
+// Copyright 2023 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package builder
+
+import (
+	"bytes"
+	"strings"
+	"unicode/utf8"
+)
+
+// TruncateTo cuts b's content to at most n bytes and then writes notice, for
+// keeping logs and alerts within a size quota. The content is cut after the
+// last newline within the first n bytes, or if there is none, between runes,
+// so a multi-byte encoding is never split. Each "{}" in notice is replaced by
+// the number of bytes cut, with commas between groups of three digits, so
+// "\n... (truncated, {} bytes omitted)" may become
+// "\n... (truncated, 12,345 bytes omitted)". If the kept content ends with a
+// newline, a line ending at the start of notice is dropped so that the notice
+// does not follow a blank line. The notice is written like any other write,
+// so enabled write modes apply to it.
+// TruncateTo reports whether the content was cut; if it was at most n bytes
+// long, it is left unchanged and notice is not written.
+// Strings previously returned by String are not affected.
+func (b *Builder) TruncateTo(n int, notice string) bool {
+	b.copyCheck()
+	if n < 0 {
+		panic("builder.Builder.TruncateTo: negative length")
+	}
+	if len(b.buf) <= n {
+		return false
+	}
+
+	c := bytes.LastIndexByte(b.buf[:n], '\n') + 1
+	if c == 0 {
+		for c = n; c > 0 && !utf8.RuneStart(b.buf[c]); c-- {
+		}
+	}
+	omitted := len(b.buf) - c
+	b.cut(c)
+	if c > 0 && b.buf[c-1] == '\n' {
+		if strings.HasPrefix(notice, "\r\n") {
+			notice = notice[2:]
+		} else {
+			notice = strings.TrimPrefix(notice, "\n")
+		}
+	}
+
+	start := len(b.buf)
+	for {
+		before, after, found := strings.Cut(notice, "{}")
+		b.buf = append(b.buf, before...)
+		if !found {
+			break
+		}
+		b.buf = appendUintGrouped(b.buf, uint64(omitted), ',')
+		notice = after
+	}
+	b.commit(start)
+	return true
+}
+
+// TruncateRunes cuts b's content to at most n runes, never splitting a
+// multi-byte encoding, for enforcing user-visible length limits. Each byte
+// of invalid UTF-8 counts as one rune, as when ranging over a string.
+// TruncateRunes reports whether the content was cut.
+// Strings previously returned by String are not affected.
+func (b *Builder) TruncateRunes(n int) bool {
+	b.copyCheck()
+	if n < 0 {
+		panic("builder.Builder.TruncateRunes: negative count")
+	}
+	if len(b.buf) <= n {
+		return false
+	}
+
+	c := 0
+	for k := 0; k < n && c < len(b.buf); k++ {
+		if b.buf[c] < utf8.RuneSelf {
+			c++
+			continue
+		}
+		_, size := utf8.DecodeRune(b.buf[c:])
+		c += size
+	}
+	if c == len(b.buf) {
+		return false
+	}
+	b.cut(c)
+	return true
+}
+
+// cutContent updates the write modes after b's content from offset c on was
+// cut or replaced.
+func (m *mode) cutContent(b *Builder, c int) {
+	if m.trackCuts && c < m.cutTo {
+		m.cutTo = c
+	}
+	if len(m.validators) > 0 {
+		m.revalidate(b, c)
+	}
+}
+
+// cut shortens b's buffer to c bytes.
+func (b *Builder) cut(c int) {
+	if b.mode != nil && b.mode.fixed != nil {
+		b.buf = b.buf[:c]
+	} else {
+		// Drop the capacity too, so the cut bytes, which may be shared with
+		// a string returned by String, are never overwritten.
+		b.buf = b.buf[:c:c]
+	}
+	if m := b.mode; m != nil {
+		m.midLine = c > 0 && b.buf[c-1] != '\n'
+		m.cutContent(b, c)
+		if m.rebuilding && m.same > c {
+			m.same = c
+		}
+	}
+}
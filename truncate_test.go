@@ -0,0 +1,131 @@
+// Copyright 2023 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package builder_test
+
+import (
+	"strings"
+	"testing"
+
+	. "github.com/weiwenchen2022/builder"
+)
+
+func TestBuilderTruncateTo(t *testing.T) {
+	t.Parallel()
+
+	const notice = "[{} cut]"
+	tests := []struct {
+		in   string
+		n    int
+		want string
+		cut  bool
+	}{
+		{"", 0, "", false},
+		{"short", 5, "short", false},
+		{"one\ntwo\nthree\n", 10, "one\ntwo\n[6 cut]", true},
+		{"one\ntwo\nthree\n", 8, "one\ntwo\n[6 cut]", true},
+		{"one\ntwo\nthree\n", 7, "one\n[10 cut]", true},
+		{"no newline here", 5, "no ne[10 cut]", true},
+		{"ééé", 3, "é[4 cut]", true},
+		{"\U0001F600x", 3, "[5 cut]", true},
+		{"abc", 0, "[3 cut]", true},
+		{strings.Repeat("x", 12350), 5, "xxxxx[12,345 cut]", true},
+	}
+	for _, tt := range tests {
+		var b Builder
+		b.WriteString(tt.in)
+		if cut := b.TruncateTo(tt.n, notice); cut != tt.cut {
+			t.Errorf("TruncateTo(%d) of %q: got %v; want %v", tt.n, tt.in, cut, tt.cut)
+		}
+		check(t, &b, tt.want)
+	}
+}
+
+func TestBuilderTruncateToNewlineNotice(t *testing.T) {
+	t.Parallel()
+
+	const notice = "\n... ({} bytes omitted)"
+	tests := []struct {
+		in   string
+		n    int
+		want string
+	}{
+		// Cut after a newline: the notice starts on the next line.
+		{"line1\nline2 is long\n", 10, "line1\n... (14 bytes omitted)"},
+		// Cut within a line: the notice's newline ends it.
+		{"line1 is long", 5, "line1\n... (8 bytes omitted)"},
+	}
+	for _, tt := range tests {
+		var b Builder
+		b.WriteString(tt.in)
+		b.TruncateTo(tt.n, notice)
+		check(t, &b, tt.want)
+	}
+
+	var b Builder
+	b.SetLineEnding(CRLF)
+	b.WriteString("line1\r\nline2\r\n")
+	b.TruncateTo(8, "\r\n[{} cut]")
+	check(t, &b, "line1\r\n[7 cut]")
+}
+
+func TestBuilderTruncateToShared(t *testing.T) {
+	t.Parallel()
+
+	var b Builder
+	b.Grow(64)
+	b.WriteString("hello, world")
+	s := b.String()
+	b.TruncateTo(5, "...")
+	b.WriteString("!")
+	check(t, &b, "hello...!")
+	if s != "hello, world" {
+		t.Errorf("previous String result changed after TruncateTo: got %q", s)
+	}
+}
+
+func TestBuilderTruncateToLinePrefix(t *testing.T) {
+	t.Parallel()
+
+	var b Builder
+	b.PushLinePrefix("> ")
+	b.WriteString("first line\nsecond line\n")
+	b.TruncateTo(14, "({} bytes omitted)\n")
+	check(t, &b, "> first line\n> (14 bytes omitted)\n")
+}
+
+func TestBuilderTruncateRunes(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		in   string
+		n    int
+		want string
+		cut  bool
+	}{
+		{"", 0, "", false},
+		{"abc", 3, "abc", false},
+		{"abc", 5, "abc", false},
+		{"abc", 0, "", true},
+		{"abcdef", 4, "abcd", true},
+		{"\u00e9t\u00e9", 3, "\u00e9t\u00e9", false},
+		{"\u00e9t\u00e9", 2, "\u00e9t", true},
+		{"\u4e16\u754c\U0001F600", 2, "\u4e16\u754c", true},
+		{"\u4e16\u754c\U0001F600", 1, "\u4e16", true},
+		{"a\xff\xe4\xb8b", 3, "a\xff\xe4", true},
+	}
+	for _, tt := range tests {
+		var b Builder
+		b.WriteString(tt.in)
+		s := b.String()
+		if cut := b.TruncateRunes(tt.n); cut != tt.cut {
+			t.Errorf("TruncateRunes(%d) of %q: got %v; want %v", tt.n, tt.in, cut, tt.cut)
+		}
+		check(t, &b, tt.want)
+		b.WriteString("!")
+		if s != tt.in {
+			t.Errorf("previous String result changed after TruncateRunes: got %q", s)
+		}
+	}
+}
@@ -0,0 +1,65 @@
+// Copyright 2023 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package builder
+
+// WriteQueryEscaped appends s to b's buffer escaped so it can be safely
+// placed inside a URL query, producing the same output as url.QueryEscape.
+// It returns the length of written and a nil error.
+func (b *Builder) WriteQueryEscaped(s string) (int, error) {
+	b.copyCheck()
+	n := len(b.buf)
+	b.buf = appendURLEscaped(b.buf, s, true)
+	return b.commit(n), nil
+}
+
+// WritePathEscaped appends s to b's buffer escaped so it can be safely
+// placed inside a URL path segment, producing the same output as
+// url.PathEscape.
+// It returns the length of written and a nil error.
+func (b *Builder) WritePathEscaped(s string) (int, error) {
+	b.copyCheck()
+	n := len(b.buf)
+	b.buf = appendURLEscaped(b.buf, s, false)
+	return b.commit(n), nil
+}
+
+// shouldEscapeURL reports whether c must be escaped in a query component or,
+// if query is false, in a path segment, following RFC 3986 as net/url does.
+func shouldEscapeURL(c byte, query bool) bool {
+	if 'a' <= c && c <= 'z' || 'A' <= c && c <= 'Z' || '0' <= c && c <= '9' {
+		return false
+	}
+
+	switch c {
+	case '-', '_', '.', '~':
+		return false
+	case '$', '&', '+', ',', '/', ':', ';', '=', '?', '@':
+		if query {
+			return true
+		}
+		return c == '/' || c == ';' || c == ',' || c == '?'
+	}
+	return true
+}
+
+func appendURLEscaped(dst []byte, s string, query bool) []byte {
+	const hex = "0123456789ABCDEF"
+
+	last := 0
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if !shouldEscapeURL(c, query) {
+			continue
+		}
+		dst = append(dst, s[last:i]...)
+		if c == ' ' && query {
+			dst = append(dst, '+')
+		} else {
+			dst = append(dst, '%', hex[c>>4], hex[c&0xF])
+		}
+		last = i + 1
+	}
+	return append(dst, s[last:]...)
+}
@@ -0,0 +1,64 @@
+// Copyright 2023 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package builder_test
+
+import (
+	"net/url"
+	"testing"
+
+	. "github.com/weiwenchen2022/builder"
+	"github.com/weiwenchen2022/builder/buildertest"
+)
+
+var urlEscapeTests = append([]string{
+	"",
+	"abc-DEF_123.~",
+	"one two",
+	"a/b;c,d?e",
+	"$&+,/:;=?@",
+	"100% #1 [ok] {x}",
+	"héllo 世界",
+}, buildertest.Adversarial...)
+
+func TestBuilderWriteQueryEscaped(t *testing.T) {
+	t.Parallel()
+
+	for _, s := range urlEscapeTests {
+		var b Builder
+		want := url.QueryEscape(s)
+		n, err := b.WriteQueryEscaped(s)
+		if err != nil || len(want) != n {
+			t.Errorf("WriteQueryEscaped(%q): got %d,%v; want %d,nil", s, n, err, len(want))
+		}
+		check(t, &b, want)
+	}
+}
+
+func TestBuilderWritePathEscaped(t *testing.T) {
+	t.Parallel()
+
+	for _, s := range urlEscapeTests {
+		var b Builder
+		want := url.PathEscape(s)
+		n, err := b.WritePathEscaped(s)
+		if err != nil || len(want) != n {
+			t.Errorf("WritePathEscaped(%q): got %d,%v; want %d,nil", s, n, err, len(want))
+		}
+		check(t, &b, want)
+	}
+}
+
+func TestBuilderWriteURLEscapedAllocs(t *testing.T) {
+	var b Builder
+	b.Grow(1024)
+	allocs := testing.AllocsPerRun(100, func() {
+		b.WriteString("?q=")
+		b.WriteQueryEscaped("a b&c")
+		b.WritePathEscaped("x/y z")
+	})
+	if allocs != 0 {
+		t.Errorf("WriteQueryEscaped/WritePathEscaped allocs = %v; want 0", allocs)
+	}
+}
@@ -0,0 +1,269 @@
+// Copyright 2023 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package builder
+
+import (
+	"bytes"
+	"errors"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// A Validator checks an invariant of the content written to a Builder.
+// It is fed the bytes of every write, in order, so it can keep state across
+// writes. Validate returns nil if p does not violate the invariant, and
+// otherwise an error describing the first violation together with its offset
+// relative to the start of p. The offset may be negative if the violation
+// started in an earlier write.
+//
+// If a Validator also has a method Done() error, Err calls it to check the
+// parts of the invariant that can only be decided at the end of the content,
+// such as an unclosed bracket.
+//
+// If a Validator also has a method Reset(), which must return it to its
+// initial state, the Builder calls it whenever content the Validator was fed
+// is cut, as by TruncateTo, or replaced, as by UnmarshalText or RebuildFrom,
+// and then feeds it the content that remains in a single call. A Validator
+// without one keeps the state that the removed content left it in. The
+// validators returned by this package all have a Reset method.
+type Validator interface {
+	Validate(p []byte) (int, error)
+}
+
+// A validator is a Validator added to a Builder.
+type validator struct {
+	Validator
+	from int // offset of the first byte it was fed
+}
+
+// A ValidationError records the first violation reported by a Validator.
+type ValidationError struct {
+	Offset int   // offset of the violation in the content
+	Err    error // error reported by the Validator
+}
+
+func (e *ValidationError) Error() string {
+	return "builder: invalid content at offset " + strconv.Itoa(e.Offset) + ": " + e.Err.Error()
+}
+
+func (e *ValidationError) Unwrap() error { return e.Err }
+
+// AddValidator adds v to the validators checking the content b is given.
+// Only content written after the call is checked. Validation stops at the
+// first violation, which Err reports.
+func (b *Builder) AddValidator(v Validator) {
+	if b.mode == nil {
+		b.mode = new(mode)
+	}
+	b.mode.validators = append(b.mode.validators, validator{v, len(b.buf)})
+}
+
+// Err returns the first violation reported by the validators added with
+// AddValidator, as a *ValidationError, or nil if the content is valid so far.
+// For a Builder created by NewFixed, Err returns ErrFull if a write has been
+// discarded because it did not fit.
+func (b *Builder) Err() error {
+	if b.mode == nil {
+		return nil
+	}
+	if b.mode.full {
+		return ErrFull
+	}
+	if b.mode.err != nil {
+		return b.mode.err
+	}
+	for _, v := range b.mode.validators {
+		if d, ok := v.Validator.(interface{ Done() error }); ok {
+			if err := d.Done(); err != nil {
+				return &ValidationError{len(b.buf), err}
+			}
+		}
+	}
+	return nil
+}
+
+// validate feeds p, written at offset n, to the validators.
+func (m *mode) validate(p []byte, n int) {
+	for _, v := range m.validators {
+		if i, err := v.Validate(p); err != nil {
+			m.err = &ValidationError{n + i, err}
+			return
+		}
+	}
+}
+
+// revalidate rewinds the validators after b's content from offset c on was
+// cut or replaced. A violation in the removed content is forgotten, and the
+// validators that can be reset are fed the content they were given again.
+func (m *mode) revalidate(b *Builder, c int) {
+	if m.err != nil && m.err.Offset < c {
+		return // the violation stands, and validation has stopped
+	}
+	m.err = nil
+	for i := range m.validators {
+		v := &m.validators[i]
+		if v.from > c {
+			v.from = c
+		}
+		r, ok := v.Validator.(interface{ Reset() })
+		if !ok {
+			continue
+		}
+		r.Reset()
+		if k, err := v.Validate(b.buf[v.from:]); err != nil && m.err == nil {
+			m.err = &ValidationError{v.from + k, err}
+		}
+	}
+}
+
+var (
+	errInvalidUTF8    = errors.New("invalid UTF-8")
+	errIncompleteUTF8 = errors.New("incomplete UTF-8 sequence")
+	errNUL            = errors.New("NUL byte")
+)
+
+// ValidUTF8 returns a Validator that reports invalid UTF-8. A multi-byte
+// encoding may be split across writes.
+func ValidUTF8() Validator {
+	return new(utf8Validator)
+}
+
+type utf8Validator struct {
+	pending []byte // incomplete encoding at the end of the previous write
+}
+
+func (v *utf8Validator) Validate(p []byte) (int, error) {
+	i := 0
+	if len(v.pending) > 0 {
+		need := utf8.UTFMax - len(v.pending)
+		if need > len(p) {
+			need = len(p)
+		}
+		v.pending = append(v.pending, p[:need]...)
+		if !utf8.FullRune(v.pending) {
+			return 0, nil
+		}
+		r, size := utf8.DecodeRune(v.pending)
+		if r == utf8.RuneError && size == 1 {
+			return -(len(v.pending) - need), errInvalidUTF8
+		}
+		i = size - (len(v.pending) - need)
+		v.pending = v.pending[:0]
+	}
+
+	for i < len(p) {
+		if p[i] < utf8.RuneSelf {
+			i++
+			i += asciiPrefix(p[i:])
+			continue
+		}
+		if !utf8.FullRune(p[i:]) {
+			v.pending = append(v.pending, p[i:]...)
+			return 0, nil
+		}
+		r, size := utf8.DecodeRune(p[i:])
+		if r == utf8.RuneError && size == 1 {
+			return i, errInvalidUTF8
+		}
+		i += size
+	}
+	return 0, nil
+}
+
+func (v *utf8Validator) Reset() { v.pending = v.pending[:0] }
+
+func (v *utf8Validator) Done() error {
+	if len(v.pending) > 0 {
+		return errIncompleteUTF8
+	}
+	return nil
+}
+
+// NoNUL returns a Validator that reports NUL bytes.
+func NoNUL() Validator {
+	return noNUL{}
+}
+
+type noNUL struct{}
+
+func (noNUL) Validate(p []byte) (int, error) {
+	if i := bytes.IndexByte(p, 0); i >= 0 {
+		return i, errNUL
+	}
+	return 0, nil
+}
+
+func (noNUL) Reset() {}
+
+// MaxLineLength returns a Validator that reports lines longer than n bytes,
+// not counting the line terminator.
+func MaxLineLength(n int) Validator {
+	return &lineLengthValidator{max: n}
+}
+
+type lineLengthValidator struct {
+	max  int
+	line int // length of the current line so far
+}
+
+func (v *lineLengthValidator) Validate(p []byte) (int, error) {
+	for i, c := range p {
+		switch c {
+		case '\n':
+			v.line = 0
+		case '\r':
+		default:
+			if v.line++; v.line > v.max {
+				return i, errors.New("line longer than " + strconv.Itoa(v.max) + " bytes")
+			}
+		}
+	}
+	return 0, nil
+}
+
+func (v *lineLengthValidator) Reset() { v.line = 0 }
+
+// Balanced returns a Validator that reports unbalanced brackets. pairs lists
+// the opening and closing byte of each kind of bracket, as in "()[]{}".
+// Brackets inside quoted strings are not treated specially.
+func Balanced(pairs string) Validator {
+	if len(pairs)%2 != 0 {
+		panic("builder.Balanced: odd number of bytes in pairs")
+	}
+	return &balancedValidator{pairs: pairs}
+}
+
+type balancedValidator struct {
+	pairs string
+	open  []byte // closing brackets expected, innermost last
+}
+
+func (v *balancedValidator) Validate(p []byte) (int, error) {
+	for i, c := range p {
+		k := strings.IndexByte(v.pairs, c)
+		switch {
+		case k < 0:
+		case k%2 == 0:
+			v.open = append(v.open, v.pairs[k+1])
+		case len(v.open) == 0:
+			return i, errors.New("unexpected " + strconv.QuoteRune(rune(c)))
+		case v.open[len(v.open)-1] != c:
+			return i, errors.New("unexpected " + strconv.QuoteRune(rune(c)) + ", want " + strconv.QuoteRune(rune(v.open[len(v.open)-1])))
+		default:
+			v.open = v.open[:len(v.open)-1]
+		}
+	}
+	return 0, nil
+}
+
+func (v *balancedValidator) Reset() { v.open = v.open[:0] }
+
+func (v *balancedValidator) Done() error {
+	if len(v.open) > 0 {
+		return errors.New("missing " + strconv.QuoteRune(rune(v.open[len(v.open)-1])))
+	}
+	return nil
+}
@@ -0,0 +1,126 @@
+// Copyright 2023 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package builder_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	. "github.com/weiwenchen2022/builder"
+)
+
+func TestBuilderValidators(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name   string
+		v      Validator
+		writes []string
+		offset int // -1 if valid
+	}{
+		{"UTF8Valid", ValidUTF8(), []string{"héllo", " 世界"}, -1},
+		{"UTF8Split", ValidUTF8(), []string{"a\xe4", "\xb8", "\x96b"}, -1},
+		{"UTF8Invalid", ValidUTF8(), []string{"abc", "de\xfff"}, 5},
+		{"UTF8SplitInvalid", ValidUTF8(), []string{"ab\xe4", "\xb8x"}, 2},
+		{"UTF8Incomplete", ValidUTF8(), []string{"ab\xe4\xb8"}, 4},
+		{"NoNUL", NoNUL(), []string{"abc", "d\x00e"}, 4},
+		{"MaxLineLength", MaxLineLength(4), []string{"abcd\r\nab", "cd\nabc", "de"}, 15},
+		{"MaxLineLengthValid", MaxLineLength(4), []string{"abcd\n", "abcd\n"}, -1},
+		{"Balanced", Balanced("()[]{}"), []string{"f(a[1], {", "b: 2})"}, -1},
+		{"BalancedMismatch", Balanced("()[]{}"), []string{"f(a[1)"}, 5},
+		{"BalancedUnexpected", Balanced("()"), []string{"a)"}, 1},
+		{"BalancedUnclosed", Balanced("()"), []string{"f((a)"}, 5},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var b Builder
+			b.WriteString("\x00\xff((") // not validated
+			b.Reset()
+			b.AddValidator(tt.v)
+			for _, w := range tt.writes {
+				b.WriteString(w)
+			}
+
+			err := b.Err()
+			if tt.offset < 0 {
+				if err != nil {
+					t.Errorf("Err: got %v; want nil", err)
+				}
+				return
+			}
+
+			var verr *ValidationError
+			if !errors.As(err, &verr) {
+				t.Fatalf("Err: got %v; want *ValidationError", err)
+			}
+			if tt.offset != verr.Offset {
+				t.Errorf("Offset: got %d; want %d (%v)", verr.Offset, tt.offset, err)
+			}
+			if errors.Unwrap(err) == nil {
+				t.Error("Unwrap: got nil")
+			}
+		})
+	}
+}
+
+func TestBuilderValidatorsFirstViolation(t *testing.T) {
+	t.Parallel()
+
+	var b Builder
+	if err := b.Err(); err != nil {
+		t.Errorf("Err without validators: got %v", err)
+	}
+
+	b.WriteString("\x00 before")
+	b.AddValidator(NoNUL())
+	b.AddValidator(MaxLineLength(3))
+	b.WriteString("abcd")
+	b.WriteString("\x00")
+	err := b.Err()
+	if want := "builder: invalid content at offset 11: line longer than 3 bytes"; err == nil || err.Error() != want {
+		t.Errorf("Err: got %v; want %s", err, want)
+	}
+}
+
+func TestBuilderValidatorsCut(t *testing.T) {
+	t.Parallel()
+
+	var b Builder
+	b.WriteString("x(")
+	b.AddValidator(Balanced("()[]"))
+	b.AddValidator(MaxLineLength(4))
+	b.WriteString("[abc")
+	b.TruncateRunes(2)
+	// The cut "abc" no longer counts toward the line, nor "[" as open.
+	b.WriteString("123]")
+	if err := b.Err(); err == nil || !strings.Contains(err.Error(), "unexpected ']'") {
+		t.Errorf("Err after TruncateRunes: got %v; want unexpected ']'", err)
+	}
+
+	// A violation that is cut away is forgotten.
+	b.TruncateTo(3, "")
+	b.WriteString("12")
+	if err := b.Err(); err != nil {
+		t.Errorf("Err after cutting the violation: got %v; want nil", err)
+	}
+	b.WriteString("34")
+	if err := b.Err(); err == nil || !strings.Contains(err.Error(), "line longer") {
+		t.Errorf("Err after long line: got %v; want line longer", err)
+	}
+
+	// UnmarshalText replaces the content, which is checked again.
+	if err := b.UnmarshalText([]byte("x()")); err != nil {
+		t.Fatal(err)
+	}
+	if err := b.Err(); err != nil {
+		t.Errorf("Err after UnmarshalText: got %v; want nil", err)
+	}
+	b.WriteString(")")
+	if err := b.Err(); err == nil || !strings.Contains(err.Error(), "unexpected ')'") {
+		t.Errorf("Err after UnmarshalText and \")\": got %v; want unexpected ')'", err)
+	}
+}
@@ -0,0 +1,86 @@
+// Copyright 2023 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package builder
+
+import "unicode/utf8"
+
+// WriteXMLEscaped appends s to b's buffer escaped for use as XML character
+// data: &, <, >, " and ' are replaced by character references, as is the
+// carriage return, which XML parsers would otherwise normalize away.
+// Characters that XML does not allow, such as most control characters,
+// and invalid UTF-8 are replaced by U+FFFD.
+// It returns the length of written and a nil error.
+func (b *Builder) WriteXMLEscaped(s string) (int, error) {
+	b.copyCheck()
+	n := len(b.buf)
+	b.buf = appendXMLEscaped(b.buf, s, false)
+	return b.commit(n), nil
+}
+
+// WriteXMLAttrEscaped is like WriteXMLEscaped but also escapes line feeds
+// and tabs, which attribute-value normalization would turn into spaces,
+// for use in a quoted attribute value. It produces the same output as
+// xml.EscapeText.
+// It returns the length of written and a nil error.
+func (b *Builder) WriteXMLAttrEscaped(s string) (int, error) {
+	b.copyCheck()
+	n := len(b.buf)
+	b.buf = appendXMLEscaped(b.buf, s, true)
+	return b.commit(n), nil
+}
+
+func appendXMLEscaped(dst []byte, s string, attr bool) []byte {
+	last := 0
+	for i := 0; i < len(s); {
+		r, size := utf8.DecodeRuneInString(s[i:])
+		var esc string
+		switch r {
+		case '&':
+			esc = "&amp;"
+		case '<':
+			esc = "&lt;"
+		case '>':
+			esc = "&gt;"
+		case '"':
+			esc = "&#34;"
+		case '\'':
+			esc = "&#39;"
+		case '\r':
+			esc = "&#xD;"
+		case '\n':
+			if !attr {
+				i += size
+				continue
+			}
+			esc = "&#xA;"
+		case '\t':
+			if !attr {
+				i += size
+				continue
+			}
+			esc = "&#x9;"
+		default:
+			if isXMLChar(r) && !(r == utf8.RuneError && size == 1) {
+				i += size
+				continue
+			}
+			esc = "\uFFFD"
+		}
+		dst = append(dst, s[last:i]...)
+		dst = append(dst, esc...)
+		i += size
+		last = i
+	}
+	return append(dst, s[last:]...)
+}
+
+// isXMLChar reports whether r is in the Char production of the XML 1.0
+// specification.
+func isXMLChar(r rune) bool {
+	return r == 0x09 || r == 0x0A || r == 0x0D ||
+		r >= 0x20 && r <= 0xD7FF ||
+		r >= 0xE000 && r <= 0xFFFD ||
+		r >= 0x10000 && r <= 0x10FFFF
+}
@@ -0,0 +1,62 @@
+// Copyright 2023 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package builder_test
+
+import (
+	"encoding/xml"
+	"strings"
+	"testing"
+
+	. "github.com/weiwenchen2022/builder"
+	"github.com/weiwenchen2022/builder/buildertest"
+)
+
+func TestBuilderWriteXMLEscaped(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		s, want string
+	}{
+		{"", ""},
+		{"plain", "plain"},
+		{`<a href="x">Tom & Jerry's</a>`, "&lt;a href=&#34;x&#34;&gt;Tom &amp; Jerry&#39;s&lt;/a&gt;"},
+		{"line 1\nline 2\r\n\tindented", "line 1\nline 2&#xD;\n\tindented"},
+		{"bell\a nul\x00 bad\xff", "bell\uFFFD nul\uFFFD bad\uFFFD"},
+		{"\uFFFE \U0001F600", "\uFFFD \U0001F600"},
+	}
+
+	for _, tt := range tests {
+		var b Builder
+		n, err := b.WriteXMLEscaped(tt.s)
+		if err != nil || len(tt.want) != n {
+			t.Errorf("WriteXMLEscaped(%q): got %d,%v; want %d,nil", tt.s, n, err, len(tt.want))
+		}
+		check(t, &b, tt.want)
+	}
+}
+
+func TestBuilderWriteXMLAttrEscaped(t *testing.T) {
+	t.Parallel()
+
+	tests := append([]string{
+		"",
+		`<a href="x">Tom & Jerry's</a>`,
+		"line 1\nline 2\r\n\tindented",
+		"bell\a nul\x00 bad\xff",
+		"\uFFFE \U0001F600",
+	}, buildertest.Adversarial...)
+
+	for _, s := range tests {
+		var want strings.Builder
+		xml.EscapeText(&want, []byte(s))
+
+		var b Builder
+		n, err := b.WriteXMLAttrEscaped(s)
+		if err != nil || want.Len() != n {
+			t.Errorf("WriteXMLAttrEscaped(%q): got %d,%v; want %d,nil", s, n, err, want.Len())
+		}
+		check(t, &b, want.String())
+	}
+}